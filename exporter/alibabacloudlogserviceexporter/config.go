@@ -27,6 +27,9 @@ type Config struct {
 	Project string `mapstructure:"project"`
 	// LogService's Logstore Name
 	Logstore string `mapstructure:"logstore"`
+	// LogstoreAttribute is the name of a log record attribute whose value, when
+	// set, overrides Logstore for that record. Only used by the logs exporter.
+	LogstoreAttribute string `mapstructure:"logstore_attribute"`
 	// AlibabaCloud access key id
 	AccessKeyID string `mapstructure:"access_key_id"`
 	// AlibabaCloud access key secret
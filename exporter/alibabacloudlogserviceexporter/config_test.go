@@ -54,12 +54,13 @@ func TestLoadConfig(t *testing.T) {
 			TypeVal: configmodels.Type(typeStr),
 			NameVal: expectedName,
 		},
-		Endpoint:        "cn-hangzhou.log.aliyuncs.com",
-		Project:         "demo-project",
-		Logstore:        "demo-logstore",
-		AccessKeyID:     "test-id",
-		AccessKeySecret: "test-secret",
-		ECSRamRole:      "test-role",
+		Endpoint:          "cn-hangzhou.log.aliyuncs.com",
+		Project:           "demo-project",
+		Logstore:          "demo-logstore",
+		LogstoreAttribute: "sls.logstore",
+		AccessKeyID:       "test-id",
+		AccessKeySecret:   "test-secret",
+		ECSRamRole:        "test-role",
 	}
 	assert.Equal(t, &expectedCfg, e1)
 
@@ -72,6 +73,9 @@ func TestLoadConfig(t *testing.T) {
 	me, err := factory.CreateMetricsExporter(context.Background(), params, e0)
 	require.Error(t, err)
 	require.Nil(t, me)
+	le, err := factory.CreateLogsExporter(context.Background(), params, e0)
+	require.Error(t, err)
+	require.Nil(t, le)
 
 	te, err = factory.CreateTraceExporter(context.Background(), params, e1)
 	require.NoError(t, err)
@@ -79,5 +83,8 @@ func TestLoadConfig(t *testing.T) {
 	me, err = factory.CreateMetricsExporter(context.Background(), params, e1)
 	require.NoError(t, err)
 	require.NotNil(t, me)
+	le, err = factory.CreateLogsExporter(context.Background(), params, e1)
+	require.NoError(t, err)
+	require.NotNil(t, le)
 
 }
@@ -0,0 +1,75 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alibabacloudlogserviceexporter
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.uber.org/zap"
+)
+
+// newLogsExporter return a new LogService logs exporter.
+func newLogsExporter(logger *zap.Logger, cfg configmodels.Exporter) (component.LogsExporter, error) {
+
+	l := &logServiceLogsSender{
+		logger:            logger,
+		logstoreAttribute: cfg.(*Config).LogstoreAttribute,
+	}
+
+	var err error
+	if l.client, err = NewLogServiceClient(cfg.(*Config), logger); err != nil {
+		return nil, err
+	}
+
+	return exporterhelper.NewLogsExporter(
+		cfg,
+		l.pushLogsData)
+}
+
+type logServiceLogsSender struct {
+	logger            *zap.Logger
+	client            LogServiceClient
+	logstoreAttribute string
+}
+
+func (s *logServiceLogsSender) pushLogsData(
+	_ context.Context,
+	ld pdata.Logs,
+) (int, error) {
+	// Logs without a logstore override are kept under the empty key and
+	// are sent to the configured logstore.
+	logsByLogstore := logDataToLogServiceData(ld, s.logstoreAttribute)
+	droppedLogs := 0
+	var errs []error
+	for logstore, logs := range logsByLogstore {
+		var err error
+		if logstore == "" {
+			err = s.client.SendLogs(logs)
+		} else {
+			err = s.client.SendLogsToLogstore(logstore, logs)
+		}
+		if err != nil {
+			s.logger.Debug("failed to send logs", zap.String("logstore", logstore), zap.Error(err))
+			droppedLogs += len(logs)
+			errs = append(errs, err)
+		}
+	}
+	return droppedLogs, componenterror.CombineErrors(errs)
+}
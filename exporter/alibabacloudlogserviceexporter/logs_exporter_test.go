@@ -0,0 +1,96 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alibabacloudlogserviceexporter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	sls "github.com/aliyun/aliyun-log-go-sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+type testLogServiceClient struct {
+	logs map[string][]*sls.Log
+	err  error
+}
+
+func (c *testLogServiceClient) SendLogs(logs []*sls.Log) error {
+	return c.SendLogsToLogstore("demo-logstore", logs)
+}
+
+func (c *testLogServiceClient) SendLogsToLogstore(logstore string, logs []*sls.Log) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.logs[logstore] = append(c.logs[logstore], logs...)
+	return nil
+}
+
+func TestNewLogsExporter(t *testing.T) {
+
+	got, err := newLogsExporter(zap.NewNop(), &Config{
+		Endpoint: "cn-hangzhou.log.aliyuncs.com",
+		Project:  "demo-project",
+		Logstore: "demo-logstore",
+	})
+	assert.NoError(t, err)
+	require.NotNil(t, got)
+
+	// This will put log data to send buffer and return success.
+	err = got.ConsumeLogs(context.Background(), pdata.NewLogs())
+	assert.NoError(t, err)
+	assert.Nil(t, got.Shutdown(context.Background()))
+}
+
+func TestNewFailsWithEmptyLogsExporterName(t *testing.T) {
+
+	got, err := newLogsExporter(zap.NewNop(), &Config{})
+	assert.Error(t, err)
+	require.Nil(t, got)
+}
+
+func TestPushLogsDataLogstoreOverride(t *testing.T) {
+	client := &testLogServiceClient{logs: map[string][]*sls.Log{}}
+	s := &logServiceLogsSender{
+		logger:            zap.NewNop(),
+		client:            client,
+		logstoreAttribute: "sls.logstore",
+	}
+
+	ld := newTestLogs()
+	ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(1).Attributes().InsertString("sls.logstore", "audit-logstore")
+
+	dropped, err := s.pushLogsData(context.Background(), ld)
+	require.NoError(t, err)
+	assert.Equal(t, 0, dropped)
+	assert.Len(t, client.logs["demo-logstore"], 1)
+	assert.Len(t, client.logs["audit-logstore"], 1)
+}
+
+func TestPushLogsDataError(t *testing.T) {
+	s := &logServiceLogsSender{
+		logger: zap.NewNop(),
+		client: &testLogServiceClient{err: errors.New("put logs failed")},
+	}
+
+	dropped, err := s.pushLogsData(context.Background(), newTestLogs())
+	assert.EqualError(t, err, "put logs failed")
+	assert.Equal(t, 2, dropped)
+}
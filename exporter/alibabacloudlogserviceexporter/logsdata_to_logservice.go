@@ -0,0 +1,184 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alibabacloudlogserviceexporter
+
+import (
+	"strconv"
+	"time"
+
+	sls "github.com/aliyun/aliyun-log-go-sdk"
+	"github.com/gogo/protobuf/proto"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+const (
+	logTimeUnixNanoField           = "timeUnixNano"
+	logSeverityNumberField         = "severityNumber"
+	logSeverityTextField           = "severityText"
+	logNameField                   = "name"
+	logContentField                = "content"
+	logFlagsField                  = "flags"
+	logInstrumentationNameField    = "otlp.name"
+	logInstrumentationVersionField = "otlp.version"
+	logAttributesPrefix            = "attribute."
+	logResourcePrefix              = "resource."
+)
+
+// logDataToLogServiceData translates log data into the LogService format,
+// grouped by destination logstore. Records whose logstoreAttribute is not set
+// are grouped under the empty logstore.
+func logDataToLogServiceData(ld pdata.Logs, logstoreAttribute string) map[string][]*sls.Log {
+	logsByLogstore := make(map[string][]*sls.Log)
+
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		if rl.IsNil() {
+			continue
+		}
+
+		resourceContents := resourceToLogContents(rl.Resource())
+		ills := rl.InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			ill := ills.At(j)
+			if ill.IsNil() {
+				continue
+			}
+
+			instrumentationContents := instrumentationLibraryToLogContents(ill.InstrumentationLibrary())
+			records := ill.Logs()
+			for k := 0; k < records.Len(); k++ {
+				lr := records.At(k)
+				if lr.IsNil() {
+					continue
+				}
+
+				log := logRecordToLogService(lr)
+				log.Contents = append(log.Contents, resourceContents...)
+				log.Contents = append(log.Contents, instrumentationContents...)
+
+				logstore := logstoreFromAttributes(lr.Attributes(), logstoreAttribute)
+				logsByLogstore[logstore] = append(logsByLogstore[logstore], log)
+			}
+		}
+	}
+	return logsByLogstore
+}
+
+func logstoreFromAttributes(attrs pdata.AttributeMap, logstoreAttribute string) string {
+	if logstoreAttribute == "" {
+		return ""
+	}
+	if v, ok := attrs.Get(logstoreAttribute); ok && v.Type() == pdata.AttributeValueSTRING {
+		return v.StringVal()
+	}
+	return ""
+}
+
+func resourceToLogContents(resource pdata.Resource) []*sls.LogContent {
+	if resource.IsNil() {
+		return nil
+	}
+	return attributeMapToLogContents(logResourcePrefix, resource.Attributes())
+}
+
+func instrumentationLibraryToLogContents(il pdata.InstrumentationLibrary) []*sls.LogContent {
+	if il.IsNil() {
+		return nil
+	}
+
+	var contents []*sls.LogContent
+	if il.Name() != "" {
+		contents = append(contents, &sls.LogContent{
+			Key:   proto.String(logInstrumentationNameField),
+			Value: proto.String(il.Name()),
+		})
+	}
+	if il.Version() != "" {
+		contents = append(contents, &sls.LogContent{
+			Key:   proto.String(logInstrumentationVersionField),
+			Value: proto.String(il.Version()),
+		})
+	}
+	return contents
+}
+
+func logRecordToLogService(lr pdata.LogRecord) *sls.Log {
+	contents := []*sls.LogContent{
+		{
+			Key:   proto.String(logTimeUnixNanoField),
+			Value: proto.String(strconv.FormatUint(uint64(lr.Timestamp()), 10)),
+		},
+		{
+			Key:   proto.String(logSeverityNumberField),
+			Value: proto.String(strconv.Itoa(int(lr.SeverityNumber()))),
+		},
+		{
+			Key:   proto.String(logSeverityTextField),
+			Value: proto.String(lr.SeverityText()),
+		},
+		{
+			Key:   proto.String(logNameField),
+			Value: proto.String(lr.Name()),
+		},
+		{
+			Key:   proto.String(logFlagsField),
+			Value: proto.String(strconv.FormatUint(uint64(lr.Flags()), 16)),
+		},
+		{
+			Key:   proto.String(traceIDField),
+			Value: proto.String(lr.TraceID().HexString()),
+		},
+		{
+			Key:   proto.String(spanIDField),
+			Value: proto.String(lr.SpanID().String()),
+		},
+	}
+
+	body := lr.Body()
+	if !body.IsNil() {
+		contents = append(contents, &sls.LogContent{
+			Key:   proto.String(logContentField),
+			Value: proto.String(tracetranslator.AttributeValueToString(body, false)),
+		})
+	}
+
+	contents = append(contents, attributeMapToLogContents(logAttributesPrefix, lr.Attributes())...)
+
+	// LogService requires a time, use the time of export for records
+	// without one.
+	t := time.Now()
+	if lr.Timestamp() != 0 {
+		t = time.Unix(0, int64(lr.Timestamp()))
+	}
+	return &sls.Log{
+		Time:     proto.Uint32(uint32(t.Unix())),
+		Contents: contents,
+	}
+}
+
+// attributeMapToLogContents flattens attrs into one log content per
+// attribute. Map and array values are stored as JSON.
+func attributeMapToLogContents(prefix string, attrs pdata.AttributeMap) []*sls.LogContent {
+	contents := make([]*sls.LogContent, 0, attrs.Len())
+	attrs.ForEach(func(k string, v pdata.AttributeValue) {
+		contents = append(contents, &sls.LogContent{
+			Key:   proto.String(prefix + k),
+			Value: proto.String(tracetranslator.AttributeValueToString(v, false)),
+		})
+	})
+	return contents
+}
@@ -0,0 +1,117 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alibabacloudlogserviceexporter
+
+import (
+	"testing"
+
+	sls "github.com/aliyun/aliyun-log-go-sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func newTestLogs() pdata.Logs {
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(1)
+	rl := ld.ResourceLogs().At(0)
+	rl.Resource().InitEmpty()
+	rl.Resource().Attributes().InsertString("service.name", "demo-service")
+	rl.InstrumentationLibraryLogs().Resize(1)
+	ill := rl.InstrumentationLibraryLogs().At(0)
+	ill.InstrumentationLibrary().InitEmpty()
+	ill.InstrumentationLibrary().SetName("demo-library")
+	ill.InstrumentationLibrary().SetVersion("v1.0.0")
+	ill.Logs().Resize(2)
+
+	lr := ill.Logs().At(0)
+	lr.SetTimestamp(pdata.TimestampUnixNano(1600000000123456789))
+	lr.SetName("demo-log")
+	lr.SetSeverityText("INFO")
+	lr.SetSeverityNumber(pdata.SeverityNumberINFO)
+	lr.SetFlags(1)
+	lr.SetTraceID(pdata.NewTraceID([]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}))
+	lr.SetSpanID(pdata.NewSpanID([]byte{1, 2, 3, 4, 5, 6, 7, 8}))
+	lr.Body().SetStringVal("request handled")
+	lr.Attributes().InsertString("http.method", "GET")
+	lr.Attributes().InsertInt("http.status_code", 200)
+
+	lr = ill.Logs().At(1)
+	lr.SetTimestamp(pdata.TimestampUnixNano(1600000001000000000))
+	lr.Body().SetStringVal("audit")
+	return ld
+}
+
+func logContentsToMap(log *sls.Log) map[string]string {
+	contents := make(map[string]string, len(log.Contents))
+	for _, c := range log.Contents {
+		contents[c.GetKey()] = c.GetValue()
+	}
+	return contents
+}
+
+func TestLogDataToLogService(t *testing.T) {
+	logsByLogstore := logDataToLogServiceData(newTestLogs(), "")
+	require.Len(t, logsByLogstore, 1)
+	logs := logsByLogstore[""]
+	require.Len(t, logs, 2)
+
+	assert.Equal(t, uint32(1600000000), logs[0].GetTime())
+	assert.Equal(t, map[string]string{
+		logTimeUnixNanoField:           "1600000000123456789",
+		logSeverityNumberField:         "9",
+		logSeverityTextField:           "INFO",
+		logNameField:                   "demo-log",
+		logFlagsField:                  "1",
+		traceIDField:                   "0102030405060708090a0b0c0d0e0f10",
+		spanIDField:                    "0102030405060708",
+		logContentField:                "request handled",
+		"attribute.http.method":        "GET",
+		"attribute.http.status_code":   "200",
+		"resource.service.name":        "demo-service",
+		logInstrumentationNameField:    "demo-library",
+		logInstrumentationVersionField: "v1.0.0",
+	}, logContentsToMap(logs[0]))
+
+	assert.Equal(t, uint32(1600000001), logs[1].GetTime())
+	assert.Equal(t, "audit", logContentsToMap(logs[1])[logContentField])
+}
+
+func TestLogDataToLogServiceLogstoreAttribute(t *testing.T) {
+	ld := newTestLogs()
+	logs := ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+	logs.At(0).Attributes().InsertString("sls.logstore", "app-logstore")
+	logs.At(1).Attributes().InsertInt("sls.logstore", 1)
+
+	logsByLogstore := logDataToLogServiceData(ld, "sls.logstore")
+	require.Len(t, logsByLogstore, 2)
+	assert.Len(t, logsByLogstore["app-logstore"], 1)
+	// Non string values are ignored.
+	assert.Len(t, logsByLogstore[""], 1)
+}
+
+func TestLogDataToLogServiceNil(t *testing.T) {
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(2)
+	ld.ResourceLogs().At(1).InstrumentationLibraryLogs().Resize(1)
+	assert.Empty(t, logDataToLogServiceData(ld, ""))
+}
+
+func TestLogRecordWithoutTimestamp(t *testing.T) {
+	lr := pdata.NewLogRecord()
+	lr.InitEmpty()
+	log := logRecordToLogService(lr)
+	assert.NotZero(t, log.GetTime())
+}
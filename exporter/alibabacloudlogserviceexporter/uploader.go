@@ -28,6 +28,8 @@ import (
 type LogServiceClient interface {
 	// SendLogs send message to LogService
 	SendLogs(logs []*sls.Log) error
+	// SendLogsToLogstore send message to the given LogService logstore
+	SendLogsToLogstore(logstore string, logs []*sls.Log) error
 }
 
 type logServiceClientImpl struct {
@@ -72,10 +74,14 @@ func NewLogServiceClient(config *Config, logger *zap.Logger) (LogServiceClient,
 
 // SendLogs send message to LogService
 func (c *logServiceClientImpl) SendLogs(logs []*sls.Log) error {
+	return c.SendLogsToLogstore(c.logstore, logs)
+}
+
+func (c *logServiceClientImpl) SendLogsToLogstore(logstore string, logs []*sls.Log) error {
 	logGroup := &sls.LogGroup{
 		Source: proto.String(c.source),
 		Topic:  proto.String(c.topic),
 		Logs:   logs,
 	}
-	return c.clientInstance.PutLogs(c.project, c.logstore, logGroup)
+	return c.clientInstance.PutLogs(c.project, logstore, logGroup)
 }
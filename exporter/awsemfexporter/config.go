@@ -16,6 +16,8 @@ package awsemfexporter
 
 import (
 	"go.opentelemetry.io/collector/config/configmodels"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsutil"
 )
 
 // Config defines configuration for AWS EMF exporter.
@@ -43,9 +45,10 @@ type Config struct {
 	Region string `mapstructure:"region"`
 	// Amazon Resource Name (ARN) of the AWS resource running the collector.
 	ResourceARN string `mapstructure:"resource_arn"`
-	// RoleARN is the IAM role used by the collector when communicating
-	// with the CloudWatch Logs service
-	RoleARN string `mapstructure:"role_arn"`
+	// CredentialsSettings defines the credentials used when communicating
+	// with the CloudWatch Logs service, e.g. a shared credentials profile
+	// or the IAM role to assume.
+	awsutil.CredentialsSettings `mapstructure:",squash"`
 	// NoVerifySSL is the option to disable TLS certificate verification.
 	NoVerifySSL bool `mapstructure:"no_verify_ssl"`
 	// MaxRetries is the maximum number of retries before abandoning an attempt to post data.
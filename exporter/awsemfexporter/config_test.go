@@ -23,6 +23,8 @@ import (
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/config/configtest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsutil"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -56,6 +58,9 @@ func TestLoadConfig(t *testing.T) {
 			ProxyAddress:          "",
 			Region:                "us-west-2",
 			ResourceARN:           "arn:aws:ec2:us-east1:123456789:instance/i-293hiuhe0u",
-			RoleARN:               "arn:aws:iam::123456789:role/monitoring-EKS-NodeInstanceRole",
+			CredentialsSettings: awsutil.CredentialsSettings{
+				RoleARN:              "arn:aws:iam::123456789:role/monitoring-EKS-NodeInstanceRole",
+				WebIdentityTokenFile: "/var/run/secrets/eks.amazonaws.com/serviceaccount/token",
+			},
 		})
 }
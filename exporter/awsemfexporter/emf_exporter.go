@@ -28,6 +28,8 @@ import (
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/obsreport"
 	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsutil"
 )
 
 type emfExporter struct {
@@ -52,7 +54,7 @@ func New(
 
 	logger := params.Logger
 	// create AWS session
-	awsConfig, session, err := GetAWSConfigSession(logger, &Conn{}, config.(*Config))
+	awsConfig, session, err := awsutil.GetAWSConfigSession(logger, &awsutil.Conn{}, newSessionSettings(config.(*Config)))
 	if err != nil {
 		return nil, err
 	}
@@ -71,6 +73,18 @@ func New(
 	return emfExporter, nil
 }
 
+func newSessionSettings(config *Config) *awsutil.SessionSettings {
+	return &awsutil.SessionSettings{
+		CredentialsSettings:   config.CredentialsSettings,
+		Region:                config.Region,
+		Endpoint:              config.Endpoint,
+		MaxRetries:            config.MaxRetries,
+		RequestTimeoutSeconds: config.RequestTimeoutSeconds,
+		NoVerifySSL:           config.NoVerifySSL,
+		ProxyAddress:          config.ProxyAddress,
+	}
+}
+
 func (emf *emfExporter) pushMetricsData(_ context.Context, md pdata.Metrics) (droppedTimeSeries int, err error) {
 	expConfig := emf.config.(*Config)
 	logGroup := "/metrics/default"
@@ -17,6 +17,7 @@ package awsemfexporter
 import (
 	"context"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws/awserr"
@@ -243,3 +244,19 @@ func TestWrapErrorIfBadRequest(t *testing.T) {
 	err = wrapErrorIfBadRequest(&awsErr)
 	assert.False(t, consumererror.IsPermanent(err))
 }
+
+func stashEnv() []string {
+	env := os.Environ()
+	os.Clearenv()
+
+	return env
+}
+
+func popEnv(env []string) {
+	os.Clearenv()
+
+	for _, e := range env {
+		p := strings.SplitN(e, "=", 2)
+		os.Setenv(p[0], p[1])
+	}
+}
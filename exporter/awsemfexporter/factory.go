@@ -20,6 +20,8 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsutil"
 )
 
 const (
@@ -51,7 +53,7 @@ func createDefaultConfig() configmodels.Exporter {
 		ProxyAddress:          "",
 		Region:                "",
 		ResourceARN:           "",
-		RoleARN:               "",
+		CredentialsSettings:   awsutil.CredentialsSettings{},
 	}
 }
 
@@ -16,10 +16,15 @@ package awsxrayexporter
 
 import (
 	"context"
+	"encoding/hex"
+	"fmt"
+	"net/http"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/xray"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/pdata"
@@ -27,6 +32,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/awsxrayexporter/translator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsutil"
 )
 
 const (
@@ -35,84 +41,225 @@ const (
 
 // NewTraceExporter creates an component.TraceExporterOld that converts to an X-Ray PutTraceSegments
 // request and then posts the request to the configured region's X-Ray endpoint.
-func NewTraceExporter(config configmodels.Exporter, logger *zap.Logger, cn connAttr) (component.TraceExporter, error) {
-	typeLog := zap.String("type", string(config.Type()))
-	nameLog := zap.String("name", config.Name())
-	awsConfig, session, err := GetAWSConfigSession(logger, cn, config.(*Config))
+func NewTraceExporter(config configmodels.Exporter, logger *zap.Logger, cn awsutil.ConnAttr) (component.TraceExporter, error) {
+	awsConfig, session, err := awsutil.GetAWSConfigSession(logger, cn, newSessionSettings(config.(*Config)))
 	if err != nil {
 		return nil, err
 	}
-	xrayClient := NewXRay(logger, awsConfig, session)
+	sender := &segmentSender{
+		config:     config.(*Config),
+		logger:     logger,
+		xrayClient: NewXRay(logger, awsConfig, session),
+	}
 	return exporterhelper.NewTraceExporter(
 		config,
-		func(ctx context.Context, td pdata.Traces) (totalDroppedSpans int, err error) {
-			logger.Debug("TraceExporter", typeLog, nameLog, zap.Int("#spans", td.SpanCount()))
-			totalDroppedSpans = 0
-			documents := make([]*string, 0, td.SpanCount())
-			for i := 0; i < td.ResourceSpans().Len(); i++ {
-				rspans := td.ResourceSpans().At(i)
-				if rspans.IsNil() {
-					continue
-				}
+		sender.pushTraceData,
+		exporterhelper.WithQueue(config.(*Config).QueueSettings),
+		exporterhelper.WithRetry(config.(*Config).RetrySettings),
+		exporterhelper.WithShutdown(func(context.Context) error {
+			return logger.Sync()
+		}),
+	)
+}
 
-				resource := rspans.Resource()
-				for j := 0; j < rspans.InstrumentationLibrarySpans().Len(); j++ {
-					ispans := rspans.InstrumentationLibrarySpans().At(j)
-					if ispans.IsNil() {
-						continue
-					}
-
-					spans := ispans.Spans()
-					for k := 0; k < spans.Len(); k++ {
-						span := spans.At(k)
-						if span.IsNil() {
-							continue
-						}
-
-						document, localErr := translator.MakeSegmentDocumentString(span, resource,
-							config.(*Config).IndexedAttributes, config.(*Config).IndexAllAttributes)
-						if localErr != nil {
-							totalDroppedSpans++
-							continue
-						}
-						documents = append(documents, &document)
-					}
-				}
+func newSessionSettings(config *Config) *awsutil.SessionSettings {
+	return &awsutil.SessionSettings{
+		CredentialsSettings:   config.CredentialsSettings,
+		Region:                config.Region,
+		Endpoint:              config.Endpoint,
+		LocalMode:             config.LocalMode,
+		MaxRetries:            config.MaxRetries,
+		NumberOfWorkers:       config.NumberOfWorkers,
+		RequestTimeoutSeconds: config.RequestTimeoutSeconds,
+		NoVerifySSL:           config.NoVerifySSL,
+		ProxyAddress:          config.ProxyAddress,
+	}
+}
+
+// segmentSender converts spans to X-Ray segment documents and sends them
+// with PutTraceSegments.
+type segmentSender struct {
+	config     *Config
+	logger     *zap.Logger
+	xrayClient XRay
+}
+
+// segmentSpan is a span whose segment document is being sent, with the
+// indexes needed to copy it into the traces to retry.
+type segmentSpan struct {
+	id                   string
+	resourceIndex        int
+	instrumentationIndex int
+	spanIndex            int
+}
+
+// pushTraceData sends the spans of td to X-Ray in chunks of maxSegmentsPerPut.
+// All chunks are attempted even when one of them fails. Spans of chunks that
+// failed with a retryable error, and segments that X-Ray returned as
+// unprocessed, are returned in a consumererror.PartialError so that only they
+// are retried.
+func (s *segmentSender) pushTraceData(ctx context.Context, td pdata.Traces) (int, error) {
+	typeLog := zap.String("type", string(s.config.Type()))
+	nameLog := zap.String("name", s.config.Name())
+	s.logger.Debug("TraceExporter", typeLog, nameLog, zap.Int("#spans", td.SpanCount()))
+
+	totalDroppedSpans := 0
+	documents := make([]*string, 0, td.SpanCount())
+	segmentSpans := make([]segmentSpan, 0, td.SpanCount())
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		rspans := td.ResourceSpans().At(i)
+		if rspans.IsNil() {
+			continue
+		}
+
+		resource := rspans.Resource()
+		for j := 0; j < rspans.InstrumentationLibrarySpans().Len(); j++ {
+			ispans := rspans.InstrumentationLibrarySpans().At(j)
+			if ispans.IsNil() {
+				continue
 			}
-			for offset := 0; offset < len(documents); offset += maxSegmentsPerPut {
-				nextOffset := offset + maxSegmentsPerPut
-				if nextOffset > td.SpanCount() {
-					nextOffset = td.SpanCount()
+
+			spans := ispans.Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				if span.IsNil() {
+					continue
 				}
-				input := xray.PutTraceSegmentsInput{TraceSegmentDocuments: documents[offset:nextOffset]}
-				logger.Debug("request: " + input.String())
-				output, localErr := xrayClient.PutTraceSegments(&input)
+
+				document, localErr := translator.MakeSegmentDocumentString(span, resource,
+					s.config.IndexedAttributes, s.config.IndexAllAttributes)
 				if localErr != nil {
-					logger.Debug("response error", zap.Error(localErr))
-					err = wrapErrorIfBadRequest(&localErr) // record error
-				}
-				if output != nil {
-					logger.Debug("response: " + output.String())
-					if output.UnprocessedTraceSegments != nil {
-						totalDroppedSpans += len(output.UnprocessedTraceSegments)
-					}
-				}
-				if err != nil {
-					break
+					totalDroppedSpans++
+					continue
 				}
+				documents = append(documents, &document)
+				segmentSpans = append(segmentSpans, segmentSpan{
+					id:                   hex.EncodeToString(span.SpanID().Bytes()),
+					resourceIndex:        i,
+					instrumentationIndex: j,
+					spanIndex:            k,
+				})
 			}
-			return totalDroppedSpans, err
-		},
-		exporterhelper.WithShutdown(func(context.Context) error {
-			return logger.Sync()
-		}),
-	)
+		}
+	}
+
+	var errs []error
+	var permanentErrs []error
+	var retrySpans []segmentSpan
+	for offset := 0; offset < len(documents); offset += maxSegmentsPerPut {
+		nextOffset := offset + maxSegmentsPerPut
+		if nextOffset > len(documents) {
+			nextOffset = len(documents)
+		}
+		input := xray.PutTraceSegmentsInput{TraceSegmentDocuments: documents[offset:nextOffset]}
+		s.logger.Debug("request: " + input.String())
+		output, localErr := s.xrayClient.PutTraceSegments(&input)
+		if localErr != nil {
+			s.logger.Debug("response error", zap.Error(localErr))
+			localErr = wrapErrorIfBadRequest(&localErr)
+			totalDroppedSpans += nextOffset - offset
+			if consumererror.IsPermanent(localErr) {
+				permanentErrs = append(permanentErrs, localErr)
+			} else {
+				errs = append(errs, localErr)
+				retrySpans = append(retrySpans, segmentSpans[offset:nextOffset]...)
+			}
+			continue
+		}
+		if output != nil {
+			s.logger.Debug("response: " + output.String())
+			if len(output.UnprocessedTraceSegments) > 0 {
+				totalDroppedSpans += len(output.UnprocessedTraceSegments)
+				errs = append(errs, unprocessedSegmentsError(output.UnprocessedTraceSegments))
+				retrySpans = append(retrySpans,
+					unprocessedSegmentSpans(segmentSpans[offset:nextOffset], output.UnprocessedTraceSegments)...)
+			}
+		}
+	}
+
+	if len(retrySpans) > 0 {
+		err := componenterror.CombineErrors(append(errs, permanentErrs...))
+		return totalDroppedSpans, consumererror.PartialTracesError(err, retryTraces(td, retrySpans))
+	}
+	if len(permanentErrs) > 0 {
+		return totalDroppedSpans, consumererror.Permanent(componenterror.CombineErrors(permanentErrs))
+	}
+	return totalDroppedSpans, componenterror.CombineErrors(errs)
+}
+
+func unprocessedSegmentsError(unprocessed []*xray.UnprocessedTraceSegment) error {
+	first := unprocessed[0]
+	return fmt.Errorf("%d trace segments were not processed, first error: %s %s",
+		len(unprocessed), aws.StringValue(first.ErrorCode), aws.StringValue(first.Message))
+}
+
+// unprocessedSegmentSpans returns the spans of chunk whose segments are in unprocessed.
+func unprocessedSegmentSpans(chunk []segmentSpan, unprocessed []*xray.UnprocessedTraceSegment) []segmentSpan {
+	ids := make(map[string]bool, len(unprocessed))
+	for _, u := range unprocessed {
+		ids[aws.StringValue(u.Id)] = true
+	}
+	spans := make([]segmentSpan, 0, len(unprocessed))
+	for _, ss := range chunk {
+		if ids[ss.id] {
+			spans = append(spans, ss)
+		}
+	}
+	return spans
+}
+
+// retryTraces copies the given spans of td, with their resource and
+// instrumentation library, into new traces.
+func retryTraces(td pdata.Traces, spans []segmentSpan) pdata.Traces {
+	retry := pdata.NewTraces()
+	rss := retry.ResourceSpans()
+	resourceIndexes := make(map[int]int)
+	instrumentationIndexes := make(map[[2]int]int)
+	for _, ss := range spans {
+		srcRs := td.ResourceSpans().At(ss.resourceIndex)
+		ri, ok := resourceIndexes[ss.resourceIndex]
+		if !ok {
+			ri = rss.Len()
+			rss.Resize(ri + 1)
+			srcRs.Resource().CopyTo(rss.At(ri).Resource())
+			resourceIndexes[ss.resourceIndex] = ri
+		}
+		rs := rss.At(ri)
+
+		srcIls := srcRs.InstrumentationLibrarySpans().At(ss.instrumentationIndex)
+		ilsKey := [2]int{ss.resourceIndex, ss.instrumentationIndex}
+		ii, ok := instrumentationIndexes[ilsKey]
+		if !ok {
+			ii = rs.InstrumentationLibrarySpans().Len()
+			rs.InstrumentationLibrarySpans().Resize(ii + 1)
+			srcIls.InstrumentationLibrary().CopyTo(rs.InstrumentationLibrarySpans().At(ii).InstrumentationLibrary())
+			instrumentationIndexes[ilsKey] = ii
+		}
+		ils := rs.InstrumentationLibrarySpans().At(ii)
+
+		si := ils.Spans().Len()
+		ils.Spans().Resize(si + 1)
+		srcIls.Spans().At(ss.spanIndex).CopyTo(ils.Spans().At(si))
+	}
+	return retry
 }
 
 func wrapErrorIfBadRequest(err *error) error {
 	_, ok := (*err).(awserr.RequestFailure)
-	if ok && (*err).(awserr.RequestFailure).StatusCode() < 500 {
+	if ok && (*err).(awserr.RequestFailure).StatusCode() < 500 && !isThrottlingError(*err) {
 		return consumererror.Permanent(*err)
 	}
 	return *err
 }
+
+// isThrottlingError checks whether err is returned because requests to X-Ray
+// are throttled, in which case they can be retried.
+func isThrottlingError(err error) bool {
+	if reqErr, ok := err.(awserr.RequestFailure); ok && reqErr.StatusCode() == http.StatusTooManyRequests {
+		return true
+	}
+	if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == xray.ErrCodeThrottledException {
+		return true
+	}
+	return false
+}
@@ -17,19 +17,45 @@ package awsxrayexporter
 import (
 	"context"
 	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"math/rand"
+	"net/http"
 	"os"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/xray"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
 	semconventions "go.opentelemetry.io/collector/translator/conventions"
 	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsutil"
 )
 
+// mockConn returns a fixed session instead of reaching out to AWS.
+type mockConn struct {
+	sn *session.Session
+}
+
+func (c *mockConn) GetEC2Region(s *session.Session) (string, error) {
+	return "us-west-2", nil
+}
+
+func (c *mockConn) NewAWSSession(logger *zap.Logger, settings *awsutil.CredentialsSettings, region string) (*session.Session, error) {
+	return c.sn, nil
+}
+
 func TestTraceExport(t *testing.T) {
 	traceExporter := initializeTraceExporter()
 	ctx := context.Background()
@@ -59,8 +85,11 @@ func initializeTraceExporter() component.TraceExporter {
 	config := factory.CreateDefaultConfig()
 	config.(*Config).Region = "us-east-1"
 	config.(*Config).LocalMode = true
+	// Send synchronously and only once so that export errors are returned.
+	config.(*Config).QueueSettings.Enabled = false
+	config.(*Config).RetrySettings.Enabled = false
 	mconn := new(mockConn)
-	mconn.sn, _ = getDefaultSession(logger)
+	mconn.sn, _ = session.NewSession()
 	traceExporter, err := NewTraceExporter(config, logger, mconn)
 	if err != nil {
 		panic(err)
@@ -193,3 +222,185 @@ func newSegmentID() []byte {
 	}
 	return r[:]
 }
+
+type mockXRay struct {
+	mu sync.Mutex
+	// putTraceSegments is called for every PutTraceSegments call with the
+	// number of the call, starting at 0.
+	putTraceSegments func(call int, input *xray.PutTraceSegmentsInput) (*xray.PutTraceSegmentsOutput, error)
+	calls            int
+	sent             []string
+}
+
+func (m *mockXRay) PutTraceSegments(input *xray.PutTraceSegmentsInput) (*xray.PutTraceSegmentsOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	call := m.calls
+	m.calls++
+	output, err := m.putTraceSegments(call, input)
+	if err != nil {
+		return output, err
+	}
+	unprocessed := make(map[string]bool)
+	for _, u := range output.UnprocessedTraceSegments {
+		unprocessed[aws.StringValue(u.Id)] = true
+	}
+	for _, doc := range input.TraceSegmentDocuments {
+		if id := segmentID(doc); !unprocessed[id] {
+			m.sent = append(m.sent, id)
+		}
+	}
+	return output, nil
+}
+
+func (m *mockXRay) PutTelemetryRecords(*xray.PutTelemetryRecordsInput) (*xray.PutTelemetryRecordsOutput, error) {
+	return &xray.PutTelemetryRecordsOutput{}, nil
+}
+
+func segmentID(document *string) string {
+	var segment struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal([]byte(*document), &segment); err != nil {
+		panic(err)
+	}
+	return segment.ID
+}
+
+func newTestSegmentSender(client XRay) *segmentSender {
+	return &segmentSender{
+		config:     NewFactory().CreateDefaultConfig().(*Config),
+		logger:     zap.NewNop(),
+		xrayClient: client,
+	}
+}
+
+func constructManySpanData(numSpans int) pdata.Traces {
+	traces := pdata.NewTraces()
+	traces.ResourceSpans().Resize(1)
+	rspans := traces.ResourceSpans().At(0)
+	constructResource().CopyTo(rspans.Resource())
+	rspans.InstrumentationLibrarySpans().Resize(1)
+	ispans := rspans.InstrumentationLibrarySpans().At(0)
+	ispans.Spans().Resize(numSpans)
+	for i := 0; i < numSpans; i++ {
+		constructHTTPServerSpan().CopyTo(ispans.Spans().At(i))
+	}
+	return traces
+}
+
+func spanIDs(td pdata.Traces) []string {
+	var ids []string
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		ilss := td.ResourceSpans().At(i).InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				ids = append(ids, hex.EncodeToString(spans.At(k).SpanID().Bytes()))
+			}
+		}
+	}
+	return ids
+}
+
+func TestPushTraceDataAttemptsAllChunks(t *testing.T) {
+	client := &mockXRay{
+		putTraceSegments: func(call int, input *xray.PutTraceSegmentsInput) (*xray.PutTraceSegmentsOutput, error) {
+			if call == 1 {
+				return nil, awserr.NewRequestFailure(
+					awserr.New(xray.ErrCodeThrottledException, "Rate exceeded", nil), http.StatusTooManyRequests, "request-id")
+			}
+			return &xray.PutTraceSegmentsOutput{}, nil
+		},
+	}
+	td := constructManySpanData(120)
+
+	dropped, err := newTestSegmentSender(client).pushTraceData(context.Background(), td)
+	require.Error(t, err)
+	assert.False(t, consumererror.IsPermanent(err))
+	assert.Equal(t, 3, client.calls)
+	assert.Equal(t, 50, dropped)
+	assert.Len(t, client.sent, 70)
+
+	partialErr, ok := err.(consumererror.PartialError)
+	require.True(t, ok, "expected a partial error, got %T", err)
+	retry := partialErr.GetTraces()
+	assert.Equal(t, spanIDs(td)[50:100], spanIDs(retry))
+	assert.Equal(t, td.ResourceSpans().At(0).Resource(), retry.ResourceSpans().At(0).Resource())
+}
+
+func TestPushTraceDataUnprocessedSegments(t *testing.T) {
+	td := constructManySpanData(3)
+	ids := spanIDs(td)
+	client := &mockXRay{
+		putTraceSegments: func(int, *xray.PutTraceSegmentsInput) (*xray.PutTraceSegmentsOutput, error) {
+			return &xray.PutTraceSegmentsOutput{
+				UnprocessedTraceSegments: []*xray.UnprocessedTraceSegment{
+					{Id: aws.String(ids[0]), ErrorCode: aws.String("ThrottledException"), Message: aws.String("Rate exceeded")},
+					{Id: aws.String(ids[2]), ErrorCode: aws.String("ThrottledException"), Message: aws.String("Rate exceeded")},
+				},
+			}, nil
+		},
+	}
+
+	dropped, err := newTestSegmentSender(client).pushTraceData(context.Background(), td)
+	assert.EqualError(t, err, "2 trace segments were not processed, first error: ThrottledException Rate exceeded")
+	assert.Equal(t, 2, dropped)
+	assert.Equal(t, []string{ids[1]}, client.sent)
+
+	partialErr, ok := err.(consumererror.PartialError)
+	require.True(t, ok, "expected a partial error, got %T", err)
+	assert.Equal(t, []string{ids[0], ids[2]}, spanIDs(partialErr.GetTraces()))
+}
+
+func TestPushTraceDataPermanentError(t *testing.T) {
+	client := &mockXRay{
+		putTraceSegments: func(call int, input *xray.PutTraceSegmentsInput) (*xray.PutTraceSegmentsOutput, error) {
+			if call == 0 {
+				return nil, awserr.NewRequestFailure(
+					awserr.New("InvalidRequestException", "invalid segment", nil), http.StatusBadRequest, "request-id")
+			}
+			return &xray.PutTraceSegmentsOutput{}, nil
+		},
+	}
+
+	dropped, err := newTestSegmentSender(client).pushTraceData(context.Background(), constructManySpanData(60))
+	require.Error(t, err)
+	assert.True(t, consumererror.IsPermanent(err))
+	assert.Equal(t, 2, client.calls)
+	assert.Equal(t, 50, dropped)
+	assert.Len(t, client.sent, 10)
+}
+
+func TestTraceExporterRetriesUnprocessedSegments(t *testing.T) {
+	client := &mockXRay{
+		putTraceSegments: func(call int, input *xray.PutTraceSegmentsInput) (*xray.PutTraceSegmentsOutput, error) {
+			if call == 0 {
+				return &xray.PutTraceSegmentsOutput{
+					UnprocessedTraceSegments: []*xray.UnprocessedTraceSegment{
+						{Id: aws.String(segmentID(input.TraceSegmentDocuments[0])), ErrorCode: aws.String("ThrottledException")},
+					},
+				}, nil
+			}
+			return &xray.PutTraceSegmentsOutput{}, nil
+		},
+	}
+	sender := newTestSegmentSender(client)
+	exp, err := exporterhelper.NewTraceExporter(
+		sender.config,
+		sender.pushTraceData,
+		exporterhelper.WithQueue(exporterhelper.QueueSettings{Enabled: false}),
+		exporterhelper.WithRetry(exporterhelper.RetrySettings{
+			Enabled:         true,
+			InitialInterval: time.Millisecond,
+			MaxInterval:     time.Millisecond,
+			MaxElapsedTime:  time.Second,
+		}),
+	)
+	require.NoError(t, err)
+
+	td := constructManySpanData(5)
+	require.NoError(t, exp.ConsumeTraces(context.Background(), td))
+	assert.Equal(t, 2, client.calls)
+	assert.ElementsMatch(t, spanIDs(td), client.sent)
+}
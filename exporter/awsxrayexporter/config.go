@@ -14,11 +14,18 @@
 
 package awsxrayexporter
 
-import "go.opentelemetry.io/collector/config/configmodels"
+import (
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsutil"
+)
 
 // Config defines configuration for AWS X-Ray exporter.
 type Config struct {
 	configmodels.ExporterSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
+	exporterhelper.QueueSettings  `mapstructure:"sending_queue"`
+	exporterhelper.RetrySettings  `mapstructure:"retry_on_failure"`
 	// Maximum number of concurrent calls to AWS X-Ray to upload documents.
 	NumberOfWorkers int `mapstructure:"num_workers"`
 	// X-Ray service endpoint to which the collector sends segment documents.
@@ -37,8 +44,9 @@ type Config struct {
 	LocalMode bool `mapstructure:"local_mode"`
 	// Amazon Resource Name (ARN) of the AWS resource running the collector.
 	ResourceARN string `mapstructure:"resource_arn"`
-	// IAM role to upload segments to a different account.
-	RoleARN string `mapstructure:"role_arn"`
+	// Credentials used to upload segments: shared credentials profile, IAM role
+	// to upload segments to a different account, web identity token, etc.
+	awsutil.CredentialsSettings `mapstructure:",squash"`
 	// By default, OpenTelemetry attributes are converted to X-Ray metadata, which are not indexed.
 	// Specify a list of attribute names to be converted to X-Ray annotations instead, which will be indexed.
 	// See annotation vs. metadata: https://docs.aws.amazon.com/xray/latest/devguide/xray-concepts.html#xray-concepts-annotations
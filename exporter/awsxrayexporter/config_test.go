@@ -17,12 +17,16 @@ package awsxrayexporter
 import (
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/config/configtest"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsutil"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -46,7 +50,18 @@ func TestLoadConfig(t *testing.T) {
 	r1 := cfg.Exporters["awsxray/customname"].(*Config)
 	assert.Equal(t, r1,
 		&Config{
-			ExporterSettings:      configmodels.ExporterSettings{TypeVal: configmodels.Type(typeStr), NameVal: "awsxray/customname"},
+			ExporterSettings: configmodels.ExporterSettings{TypeVal: configmodels.Type(typeStr), NameVal: "awsxray/customname"},
+			QueueSettings: exporterhelper.QueueSettings{
+				Enabled:      true,
+				NumConsumers: 2,
+				QueueSize:    10,
+			},
+			RetrySettings: exporterhelper.RetrySettings{
+				Enabled:         true,
+				InitialInterval: 10 * time.Second,
+				MaxInterval:     60 * time.Second,
+				MaxElapsedTime:  10 * time.Minute,
+			},
 			NumberOfWorkers:       8,
 			Endpoint:              "",
 			RequestTimeoutSeconds: 30,
@@ -56,8 +71,14 @@ func TestLoadConfig(t *testing.T) {
 			Region:                "eu-west-1",
 			LocalMode:             false,
 			ResourceARN:           "arn:aws:ec2:us-east1:123456789:instance/i-293hiuhe0u",
-			RoleARN:               "arn:aws:iam::123456789:role/monitoring-EKS-NodeInstanceRole",
-			IndexedAttributes:     []string{"indexed_attr_0", "indexed_attr_1"},
-			IndexAllAttributes:    false,
+			CredentialsSettings: awsutil.CredentialsSettings{
+				Profile:         "collector",
+				CredentialsFile: "/etc/collector/credentials",
+				RoleARN:         "arn:aws:iam::123456789:role/monitoring-EKS-NodeInstanceRole",
+				ExternalID:      "collector-external-id",
+				STSEndpoint:     "https://sts.eu-west-1.amazonaws.com",
+			},
+			IndexedAttributes:  []string{"indexed_attr_0", "indexed_attr_1"},
+			IndexAllAttributes: false,
 		})
 }
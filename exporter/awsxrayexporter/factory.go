@@ -20,6 +20,8 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsutil"
 )
 
 const (
@@ -41,6 +43,8 @@ func createDefaultConfig() configmodels.Exporter {
 			TypeVal: configmodels.Type(typeStr),
 			NameVal: typeStr,
 		},
+		QueueSettings:         exporterhelper.CreateDefaultQueueSettings(),
+		RetrySettings:         exporterhelper.CreateDefaultRetrySettings(),
 		NumberOfWorkers:       8,
 		Endpoint:              "",
 		RequestTimeoutSeconds: 30,
@@ -50,7 +54,7 @@ func createDefaultConfig() configmodels.Exporter {
 		Region:                "",
 		LocalMode:             false,
 		ResourceARN:           "",
-		RoleARN:               "",
+		CredentialsSettings:   awsutil.CredentialsSettings{},
 	}
 }
 
@@ -60,5 +64,5 @@ func createTraceExporter(
 	cfg configmodels.Exporter,
 ) (component.TraceExporter, error) {
 	eCfg := cfg.(*Config)
-	return NewTraceExporter(eCfg, params.Logger, &Conn{})
+	return NewTraceExporter(eCfg, params.Logger, &awsutil.Conn{})
 }
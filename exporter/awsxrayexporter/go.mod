@@ -4,12 +4,14 @@ go 1.14
 
 require (
 	github.com/aws/aws-sdk-go v1.34.30
+	github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsutil v0.0.0-00010101000000-000000000000
 	github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray v0.0.0-00010101000000-000000000000
 	github.com/stretchr/testify v1.6.1
 	go.opentelemetry.io/collector v0.11.1-0.20200924160956-8690937037da
 	go.uber.org/zap v1.16.0
-	golang.org/x/net v0.0.0-20200822124328-c89045814202
 	google.golang.org/grpc/examples v0.0.0-20200728194956-1c32b02682df // indirect
 )
 
 replace github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray => ./../../internal/awsxray
+
+replace github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsutil => ./../../internal/awsutil
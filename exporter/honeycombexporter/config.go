@@ -14,7 +14,11 @@
 
 package honeycombexporter
 
-import "go.opentelemetry.io/collector/config/configmodels"
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+)
 
 type Config struct {
 	configmodels.ExporterSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
@@ -30,4 +34,28 @@ type Config struct {
 	SampleRate uint `mapstructure:"sample_rate"`
 	// Debug enables more verbose logging from the Honeycomb SDK. It defaults to false.
 	Debug bool `mapstructure:"debug"`
+	// Sampler configures how spans are sampled before being sent to Honeycomb.
+	Sampler SamplerConfig `mapstructure:"sampler"`
+}
+
+// SamplerConfig defines the sampler used to decide which spans are sent to Honeycomb.
+type SamplerConfig struct {
+	// Type is the sampler to use, one of "static", "dynamic" or "attribute".
+	// It defaults to "static", which sends every span with SampleRate recorded
+	// as its sample rate.
+	Type string `mapstructure:"type"`
+	// KeyFields are the span and resource attributes whose values make up the
+	// key spans are sampled by with the "dynamic" sampler. The "status.code"
+	// field holds the status code of the span. It defaults to "service.name",
+	// "http.status_code" and "status.code".
+	KeyFields []string `mapstructure:"key_fields"`
+	// GoalThroughputPerSec is the number of spans per second the "dynamic"
+	// sampler aims to send, across all keys.
+	GoalThroughputPerSec uint `mapstructure:"goal_throughput_per_sec"`
+	// AdjustmentInterval is how often the "dynamic" sampler recomputes its
+	// sample rates.
+	AdjustmentInterval time.Duration `mapstructure:"adjustment_interval"`
+	// Weight is the weight given to the last interval when updating the moving
+	// average of the span count of each key, between 0 and 1.
+	Weight float64 `mapstructure:"weight"`
 }
@@ -17,6 +17,7 @@ package honeycombexporter
 import (
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -38,7 +39,7 @@ func TestLoadConfig(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, cfg)
 
-	assert.Equal(t, len(cfg.Exporters), 2)
+	assert.Equal(t, len(cfg.Exporters), 3)
 
 	r0 := cfg.Exporters["honeycomb"]
 	assert.Equal(t, r0, factory.CreateDefaultConfig())
@@ -50,5 +51,27 @@ func TestLoadConfig(t *testing.T) {
 		Dataset:          "test-dataset",
 		APIURL:           "https://api.testhost.io",
 		SampleRate:       1,
+		Sampler: SamplerConfig{
+			Type:                 "static",
+			GoalThroughputPerSec: 100,
+			AdjustmentInterval:   15 * time.Second,
+			Weight:               0.5,
+		},
+	})
+
+	r2 := cfg.Exporters["honeycomb/sampling"].(*Config)
+	assert.Equal(t, r2, &Config{
+		ExporterSettings: configmodels.ExporterSettings{TypeVal: configmodels.Type(typeStr), NameVal: "honeycomb/sampling"},
+		APIKey:           "test-apikey",
+		Dataset:          "test-dataset",
+		APIURL:           "https://api.honeycomb.io",
+		SampleRate:       1,
+		Sampler: SamplerConfig{
+			Type:                 "dynamic",
+			KeyFields:            []string{"service.name", "status.code"},
+			GoalThroughputPerSec: 50,
+			AdjustmentInterval:   30 * time.Second,
+			Weight:               0.25,
+		},
 	})
 }
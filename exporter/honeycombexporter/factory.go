@@ -16,6 +16,7 @@ package honeycombexporter
 
 import (
 	"context"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configmodels"
@@ -46,6 +47,12 @@ func createDefaultConfig() configmodels.Exporter {
 		APIURL:     "https://api.honeycomb.io",
 		SampleRate: 1,
 		Debug:      false,
+		Sampler: SamplerConfig{
+			Type:                 staticSamplerType,
+			GoalThroughputPerSec: 100,
+			AdjustmentInterval:   15 * time.Second,
+			Weight:               0.5,
+		},
 	}
 }
 
@@ -3,7 +3,6 @@ module github.com/open-telemetry/opentelemetry-collector-contrib/exporter/honeyc
 go 1.14
 
 require (
-	github.com/census-instrumentation/opencensus-proto v0.3.0
 	github.com/google/go-cmp v0.5.2
 	github.com/honeycombio/libhoney-go v1.14.0
 	github.com/klauspost/compress v1.11.0
@@ -12,5 +11,4 @@ require (
 	go.uber.org/zap v1.16.0
 	google.golang.org/grpc v1.32.0
 	google.golang.org/grpc/examples v0.0.0-20200728194956-1c32b02682df // indirect
-	google.golang.org/protobuf v1.25.0
 )
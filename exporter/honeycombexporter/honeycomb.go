@@ -18,15 +18,12 @@ import (
 	"context"
 	"time"
 
-	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
 	"github.com/honeycombio/libhoney-go"
 	"github.com/honeycombio/libhoney-go/transmission"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenterror"
-	"go.opentelemetry.io/collector/consumer/consumerdata"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
-	"go.opentelemetry.io/collector/translator/internaldata"
 	"go.uber.org/zap"
 )
 
@@ -38,6 +35,7 @@ const (
 // honeycombExporter is the object that sends events to honeycomb.
 type honeycombExporter struct {
 	builder *libhoney.Builder
+	sampler sampler
 	onError func(error)
 	logger  *zap.Logger
 }
@@ -66,18 +64,13 @@ type spanEvent struct {
 // TraceID and ParentID are used to identify the span with which the trace is associated
 // We are modeling Links for now as child spans rather than properties of the event.
 type link struct {
-	TraceID     string      `json:"trace.trace_id"`
-	ParentID    string      `json:"trace.parent_id,omitempty"`
-	LinkTraceID string      `json:"trace.link.trace_id"`
-	LinkSpanID  string      `json:"trace.link.span_id"`
-	SpanType    string      `json:"meta.span_type"`
-	RefType     spanRefType `json:"ref_type,omitempty"`
+	TraceID     string `json:"trace.trace_id"`
+	ParentID    string `json:"trace.parent_id,omitempty"`
+	LinkTraceID string `json:"trace.link.trace_id"`
+	LinkSpanID  string `json:"trace.link.span_id"`
+	SpanType    string `json:"meta.span_type"`
 }
 
-// spanRefType defines the relationship a Link has to a trace or a span. It can
-// either be a child of, or a follows from relationship.
-type spanRefType int64
-
 // newHoneycombTraceExporter creates and returns a new honeycombExporter. It
 // wraps the exporter in the component.TraceExporterOld helper method.
 func newHoneycombTraceExporter(cfg *Config, logger *zap.Logger) (component.TraceExporter, error) {
@@ -94,12 +87,18 @@ func newHoneycombTraceExporter(cfg *Config, logger *zap.Logger) (component.Trace
 		libhoneyConfig.Logger = &libhoney.DefaultLogger{}
 	}
 
+	sampler, err := newSampler(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	if err := libhoney.Init(libhoneyConfig); err != nil {
 		return nil, err
 	}
 	builder := libhoney.NewBuilder()
 	exporter := &honeycombExporter{
 		builder: builder,
+		sampler: sampler,
 		logger:  logger,
 		onError: func(err error) {
 			logger.Warn(err.Error())
@@ -124,64 +123,70 @@ func (e *honeycombExporter) pushTraceData(ctx context.Context, td pdata.Traces)
 	go e.RunErrorLogger(ctx, libhoney.TxResponses())
 	defer cancel()
 
-	octds := internaldata.TraceDataToOC(td)
-	for _, octd := range octds {
+	// Spans are grouped by trace so that the sampling decision is made once
+	// for each trace and applies to all of its spans and events.
+	traces := make(map[string][]*spanData)
+	var traceIDs []string
 
-		// Extract Node and Resource attributes, labels and other information.
-		// Because these exist on the TraceData, they will be added to every span.
-		traceLevelFields := getTraceLevelFields(octd.Node, octd.Resource, octd.SourceFormat)
-		addTraceLevelFields := func(ev *libhoney.Event, tlf map[string]interface{}) {
-			for k, v := range tlf {
-				ev.AddField(k, v)
-			}
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		if rs.IsNil() {
+			continue
 		}
 
-		for _, span := range octd.Spans {
-			ev := e.builder.NewEvent()
+		// Extract the resource attributes. Because they exist on the
+		// ResourceSpans, they will be added to every span.
+		resourceFields := getResourceFields(rs.Resource())
 
-			tlf := traceLevelFields
-			// If Resource present need to recalculate traceLevelFields
-			if span.Resource != nil {
-				tlf = getTraceLevelFields(octd.Node, span.Resource, octd.SourceFormat)
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			ils := ilss.At(j)
+			if ils.IsNil() {
+				continue
 			}
-			addTraceLevelFields(ev, tlf)
 
-			if len(span.GetParentSpanId()) == 0 || hasRemoteParent(span) {
-				if octd.Node != nil {
-					for k, v := range octd.Node.Attributes {
-						ev.AddField(k, v)
-					}
+			libraryFields := getInstrumentationLibraryFields(ils.InstrumentationLibrary())
+
+			spans := ils.Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				if span.IsNil() {
+					continue
 				}
-			}
 
-			if attrs := spanAttributesToMap(span.GetAttributes()); attrs != nil {
-				for k, v := range attrs {
-					ev.AddField(k, v)
+				sd := &spanData{
+					span:           span,
+					ev:             e.newSpanEvent(span, resourceFields, libraryFields),
+					resourceFields: resourceFields,
+					libraryFields:  libraryFields,
 				}
+				traceID := string(span.TraceID().Bytes())
+				if _, ok := traces[traceID]; !ok {
+					traceIDs = append(traceIDs, traceID)
+				}
+				traces[traceID] = append(traces[traceID], sd)
 			}
+		}
+	}
 
-			ev.Timestamp = timestampToTime(span.GetStartTime())
-			startTime := timestampToTime(span.GetStartTime())
-			endTime := timestampToTime(span.GetEndTime())
-
-			ev.Add(event{
-				ID:              getHoneycombSpanID(span.GetSpanId()),
-				TraceID:         getHoneycombTraceID(span.GetTraceId()),
-				ParentID:        getHoneycombSpanID(span.GetParentSpanId()),
-				Name:            truncatableStringAsString(span.GetName()),
-				DurationMilli:   float64(endTime.Sub(startTime)) / float64(time.Millisecond),
-				HasRemoteParent: hasRemoteParent(span),
-			})
+	for _, traceID := range traceIDs {
+		spans := traces[traceID]
+		// Spans dropped by the sampler are not failures, they are
+		// accounted for by the sample rate of the spans that are kept.
+		sampleRate, keep := e.sampler.sample([]byte(traceID), traceRoot(spans).ev.Fields(), len(spans))
+		if !keep {
+			goodSpans += len(spans)
+			continue
+		}
 
-			e.sendMessageEvents(octd, span, tlf)
-			e.sendSpanLinks(span)
+		for _, sd := range spans {
+			sd.ev.SampleRate = sampleRate
 
-			ev.AddField("status.code", getStatusCode(span.Status))
-			ev.AddField("status.message", getStatusMessage(span.Status))
-			ev.AddField("has_remote_parent", !span.GetSameProcessAsParentSpan().GetValue())
-			ev.AddField("child_span_count", span.GetChildSpanCount())
+			e.sendSpanEvents(sd.span, sd.resourceFields, sd.libraryFields, sampleRate)
+			e.sendSpanLinks(sd.span, sampleRate)
 
-			if err := ev.SendPresampled(); err != nil {
+			if err := sd.ev.SendPresampled(); err != nil {
 				errs = append(errs, err)
 			} else {
 				goodSpans++
@@ -192,74 +197,124 @@ func (e *honeycombExporter) pushTraceData(ctx context.Context, td pdata.Traces)
 	return td.SpanCount() - goodSpans, componenterror.CombineErrors(errs)
 }
 
+// spanData holds a span, the event it is sent as and the fields shared with
+// the events of the span.
+type spanData struct {
+	span           pdata.Span
+	ev             *libhoney.Event
+	resourceFields map[string]interface{}
+	libraryFields  map[string]interface{}
+}
+
+// newSpanEvent creates the event a span is sent as.
+func (e *honeycombExporter) newSpanEvent(span pdata.Span, resourceFields, libraryFields map[string]interface{}) *libhoney.Event {
+	ev := e.builder.NewEvent()
+	addFields(ev, resourceFields)
+	addFields(ev, libraryFields)
+	addFields(ev, attributesToFields(span.Attributes()))
+
+	ev.Timestamp = pdata.UnixNanoToTime(span.StartTime())
+	startTime := pdata.UnixNanoToTime(span.StartTime())
+	endTime := pdata.UnixNanoToTime(span.EndTime())
+
+	ev.Add(event{
+		ID:              getHoneycombSpanID(span.SpanID().Bytes()),
+		TraceID:         getHoneycombTraceID(span.TraceID().Bytes()),
+		ParentID:        getHoneycombSpanID(span.ParentSpanID().Bytes()),
+		Name:            span.Name(),
+		DurationMilli:   float64(endTime.Sub(startTime)) / float64(time.Millisecond),
+		HasRemoteParent: hasRemoteParent(span),
+	})
+
+	if kind := getSpanKind(span.Kind()); kind != "" {
+		ev.AddField(spanKindField, kind)
+	}
+	if traceState := span.TraceState(); traceState != pdata.TraceStateEmpty {
+		ev.AddField(traceStateField, string(traceState))
+	}
+	addDroppedCount(ev, droppedAttributesCountField, span.DroppedAttributesCount())
+	addDroppedCount(ev, droppedEventsCountField, span.DroppedEventsCount())
+	addDroppedCount(ev, droppedLinksCountField, span.DroppedLinksCount())
+
+	ev.AddField(statusCodeField, getStatusCode(span.Status()))
+	ev.AddField("status.message", getStatusMessage(span.Status()))
+	return ev
+}
+
+// traceRoot returns the span the sampling decision of a trace is made from:
+// its root span, or the first span whose parent is not in the batch when the
+// root span is not part of it.
+func traceRoot(spans []*spanData) *spanData {
+	ids := make(map[string]struct{}, len(spans))
+	for _, sd := range spans {
+		if len(sd.span.ParentSpanID().Bytes()) == 0 {
+			return sd
+		}
+		ids[string(sd.span.SpanID().Bytes())] = struct{}{}
+	}
+	for _, sd := range spans {
+		if _, ok := ids[string(sd.span.ParentSpanID().Bytes())]; !ok {
+			return sd
+		}
+	}
+	return spans[0]
+}
+
 // sendSpanLinks gets the list of links associated with this span and sends them as
 // separate events to Honeycomb, with a span type "link".
-func (e *honeycombExporter) sendSpanLinks(span *tracepb.Span) {
-	links := span.GetLinks()
+func (e *honeycombExporter) sendSpanLinks(span pdata.Span, sampleRate uint) {
+	links := span.Links()
 
-	if links == nil {
-		return
-	}
+	for i := 0; i < links.Len(); i++ {
+		l := links.At(i)
+		if l.IsNil() {
+			continue
+		}
 
-	for _, l := range links.GetLink() {
 		ev := e.builder.NewEvent()
+		ev.SampleRate = sampleRate
 		ev.Add(link{
-			TraceID:     getHoneycombTraceID(span.GetTraceId()),
-			ParentID:    getHoneycombSpanID(span.GetSpanId()),
-			LinkTraceID: getHoneycombTraceID(l.GetTraceId()),
-			LinkSpanID:  getHoneycombSpanID(l.GetSpanId()),
+			TraceID:     getHoneycombTraceID(span.TraceID().Bytes()),
+			ParentID:    getHoneycombSpanID(span.SpanID().Bytes()),
+			LinkTraceID: getHoneycombTraceID(l.TraceID().Bytes()),
+			LinkSpanID:  getHoneycombSpanID(l.SpanID().Bytes()),
 			SpanType:    "link",
-			RefType:     spanRefType(l.GetType()),
 		})
-		attrs := spanAttributesToMap(l.GetAttributes())
-		for k, v := range attrs {
-			ev.AddField(k, v)
+		if traceState := l.TraceState(); traceState != pdata.TraceStateEmpty {
+			ev.AddField(traceStateField, string(traceState))
 		}
+		addDroppedCount(ev, droppedAttributesCountField, l.DroppedAttributesCount())
+		addFields(ev, attributesToFields(l.Attributes()))
 		if err := ev.SendPresampled(); err != nil {
 			e.onError(err)
 		}
 	}
 }
 
-// sendMessageEvents gets the list of timeevents from the span and sends them as
+// sendSpanEvents gets the list of events from the span and sends them as
 // separate events to Honeycomb, with a span type "span_event".
-func (e *honeycombExporter) sendMessageEvents(td consumerdata.TraceData, span *tracepb.Span, traceFields map[string]interface{}) {
-	timeEvents := span.GetTimeEvents()
-	if timeEvents == nil {
-		return
-	}
+func (e *honeycombExporter) sendSpanEvents(span pdata.Span, resourceFields, libraryFields map[string]interface{}, sampleRate uint) {
+	events := span.Events()
 
-	for _, event := range timeEvents.TimeEvent {
-		annotation := event.GetAnnotation()
-		if annotation == nil {
+	for i := 0; i < events.Len(); i++ {
+		event := events.At(i)
+		if event.IsNil() {
 			continue
 		}
 
-		ts := timestampToTime(event.GetTime())
-		name := annotation.GetDescription().GetValue()
-		attrs := spanAttributesToMap(annotation.GetAttributes())
-
-		// treat trace level fields as underlays with same keyed span attributes taking precedence.
+		// treat resource and library fields as underlays with same keyed event attributes taking precedence.
 		ev := e.builder.NewEvent()
-		for k, v := range traceFields {
-			ev.AddField(k, v)
-		}
-		if len(span.GetParentSpanId()) == 0 || hasRemoteParent(span) {
-			if td.Node != nil {
-				for k, v := range td.Node.Attributes {
-					ev.AddField(k, v)
-				}
-			}
-		}
-		for k, v := range attrs {
-			ev.AddField(k, v)
-		}
-		ev.Timestamp = ts
+		addFields(ev, resourceFields)
+		addFields(ev, libraryFields)
+		addDroppedCount(ev, droppedAttributesCountField, event.DroppedAttributesCount())
+		addFields(ev, attributesToFields(event.Attributes()))
+		ev.Timestamp = pdata.UnixNanoToTime(event.Timestamp())
+		ev.SampleRate = sampleRate
 		ev.Add(spanEvent{
-			Name:       name,
-			TraceID:    getHoneycombTraceID(span.GetTraceId()),
-			ParentID:   getHoneycombSpanID(span.GetSpanId()),
-			ParentName: truncatableStringAsString(span.GetName()),
+			Name:       event.Name(),
+			TraceID:    getHoneycombTraceID(span.TraceID().Bytes()),
+			ParentID:   getHoneycombSpanID(span.SpanID().Bytes()),
+			ParentName: span.Name(),
 			SpanType:   "span_event",
 		})
 		if err := ev.SendPresampled(); err != nil {
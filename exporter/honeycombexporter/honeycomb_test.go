@@ -22,19 +22,15 @@ import (
 	"net/http/httptest"
 	"testing"
 
-	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
-	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
-	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
 	"github.com/google/go-cmp/cmp"
+	"github.com/honeycombio/libhoney-go"
 	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
-	"go.opentelemetry.io/collector/consumer/consumerdata"
 	"go.opentelemetry.io/collector/consumer/pdata"
-	"go.opentelemetry.io/collector/translator/internaldata"
+	"go.opentelemetry.io/collector/translator/conventions"
 	"go.uber.org/zap"
-	"google.golang.org/protobuf/types/known/timestamppb"
-	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 type honeycombData struct {
@@ -67,6 +63,10 @@ func testingServer(callback func(data []honeycombData)) *httptest.Server {
 }
 
 func testTraceExporter(td pdata.Traces, t *testing.T) []honeycombData {
+	return testTraceExporterWithSampler(td, SamplerConfig{}, t)
+}
+
+func testTraceExporterWithSampler(td pdata.Traces, samplerCfg SamplerConfig, t *testing.T) []honeycombData {
 	var got []honeycombData
 	server := testingServer(func(data []honeycombData) {
 		got = append(got, data...)
@@ -78,6 +78,7 @@ func testTraceExporter(td pdata.Traces, t *testing.T) []honeycombData {
 		APIURL:     server.URL,
 		Debug:      false,
 		SampleRate: 1,
+		Sampler:    samplerCfg,
 	}
 
 	params := component.ExporterCreateParams{Logger: zap.NewNop()}
@@ -93,182 +94,153 @@ func testTraceExporter(td pdata.Traces, t *testing.T) []honeycombData {
 }
 
 func TestExporter(t *testing.T) {
-	td := consumerdata.TraceData{
-		Node: &commonpb.Node{
-			ServiceInfo: &commonpb.ServiceInfo{Name: "test_service"},
-			Attributes: map[string]string{
-				"A": "B",
-			},
-		},
-		Resource: &resourcepb.Resource{
-			Type: "foobar",
-			Labels: map[string]string{
-				"B": "C",
-			},
-		},
-		Spans: []*tracepb.Span{
-			{
-				TraceId:                 []byte{0x01},
-				SpanId:                  []byte{0x02},
-				Name:                    &tracepb.TruncatableString{Value: "root"},
-				Kind:                    tracepb.Span_SERVER,
-				SameProcessAsParentSpan: &wrapperspb.BoolValue{Value: true},
-				Attributes: &tracepb.Span_Attributes{
-					AttributeMap: map[string]*tracepb.AttributeValue{
-						"span_attr_name": {
-							Value: &tracepb.AttributeValue_StringValue{
-								StringValue: &tracepb.TruncatableString{Value: "Span Attribute"},
-							},
-						},
-					},
-				},
-				Resource: &resourcepb.Resource{
-					Type: "override",
-					Labels: map[string]string{
-						"B": "D",
-					},
-				},
-				TimeEvents: &tracepb.Span_TimeEvents{
-					TimeEvent: []*tracepb.Span_TimeEvent{
-						{
-							Time: &timestamppb.Timestamp{
-								Seconds: 0,
-								Nanos:   0,
-							},
-							Value: &tracepb.Span_TimeEvent_Annotation_{
-								Annotation: &tracepb.Span_TimeEvent_Annotation{
-									Description: &tracepb.TruncatableString{Value: "Some Description"},
-									Attributes: &tracepb.Span_Attributes{
-										AttributeMap: map[string]*tracepb.AttributeValue{
-											"attribute_name": {
-												Value: &tracepb.AttributeValue_StringValue{
-													StringValue: &tracepb.TruncatableString{Value: "Hello MessageEvent"},
-												},
-											},
-										},
-									},
-								},
-							},
-						},
-					},
-				},
-			},
-			{
-				TraceId:                 []byte{0x01},
-				SpanId:                  []byte{0x03},
-				ParentSpanId:            []byte{0x02},
-				Name:                    &tracepb.TruncatableString{Value: "client"},
-				Kind:                    tracepb.Span_CLIENT,
-				SameProcessAsParentSpan: &wrapperspb.BoolValue{Value: true},
-				Links: &tracepb.Span_Links{
-					Link: []*tracepb.Span_Link{
-						{
-							TraceId: []byte{0x04},
-							SpanId:  []byte{0x05},
-							Type:    tracepb.Span_Link_PARENT_LINKED_SPAN,
-							Attributes: &tracepb.Span_Attributes{
-								AttributeMap: map[string]*tracepb.AttributeValue{
-									"span_link_attr": {
-										Value: &tracepb.AttributeValue_IntValue{
-											IntValue: 12345,
-										},
-									},
-								},
-							},
-						},
-					},
-					DroppedLinksCount: 0,
-				},
-			},
-			{
-				TraceId:                 []byte{0x01},
-				SpanId:                  []byte{0x04},
-				ParentSpanId:            []byte{0x03},
-				Name:                    &tracepb.TruncatableString{Value: "server"},
-				Kind:                    tracepb.Span_SERVER,
-				SameProcessAsParentSpan: &wrapperspb.BoolValue{Value: false},
-			},
-		},
-	}
-	got := testTraceExporter(internaldata.OCToTraceData(td), t)
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(1)
+	rs := td.ResourceSpans().At(0)
+	rs.Resource().InitEmpty()
+	rs.Resource().Attributes().InsertString(conventions.AttributeServiceName, "test_service")
+	rs.Resource().Attributes().InsertString("A", "B")
+
+	rs.InstrumentationLibrarySpans().Resize(1)
+	ils := rs.InstrumentationLibrarySpans().At(0)
+	ils.InstrumentationLibrary().InitEmpty()
+	ils.InstrumentationLibrary().SetName("test_library")
+	ils.InstrumentationLibrary().SetVersion("1.0.0")
+	ils.Spans().Resize(3)
+
+	root := ils.Spans().At(0)
+	root.SetTraceID(pdata.NewTraceID([]byte{0x01}))
+	root.SetSpanID(pdata.NewSpanID([]byte{0x02}))
+	root.SetName("root")
+	root.SetKind(pdata.SpanKindSERVER)
+	root.SetTraceState("vendor=value")
+	root.SetDroppedAttributesCount(1)
+	root.SetDroppedEventsCount(2)
+	root.SetDroppedLinksCount(3)
+	root.Attributes().InsertString("span_attr_name", "Span Attribute")
+	root.Attributes().InsertBool(conventions.OCAttributeSameProcessAsParentSpan, true)
+	root.Events().Resize(1)
+	event := root.Events().At(0)
+	event.SetName("Some Description")
+	event.SetDroppedAttributesCount(4)
+	event.Attributes().InsertString("attribute_name", "Hello MessageEvent")
+	values := pdata.NewAttributeValueArray()
+	values.ArrayVal().Append(pdata.NewAttributeValueInt(1))
+	values.ArrayVal().Append(pdata.NewAttributeValueInt(2))
+	event.Attributes().Insert("values", values)
+
+	client := ils.Spans().At(1)
+	client.SetTraceID(pdata.NewTraceID([]byte{0x01}))
+	client.SetSpanID(pdata.NewSpanID([]byte{0x03}))
+	client.SetParentSpanID(pdata.NewSpanID([]byte{0x02}))
+	client.SetName("client")
+	client.SetKind(pdata.SpanKindCLIENT)
+	client.Status().InitEmpty()
+	client.Status().SetCode(pdata.StatusCode(14))
+	client.Status().SetMessage("unavailable")
+	client.Links().Resize(1)
+	link := client.Links().At(0)
+	link.SetTraceID(pdata.NewTraceID([]byte{0x04}))
+	link.SetSpanID(pdata.NewSpanID([]byte{0x05}))
+	link.SetTraceState("vendor=link")
+	link.Attributes().InsertInt("span_link_attr", 12345)
+
+	server := ils.Spans().At(2)
+	server.SetTraceID(pdata.NewTraceID([]byte{0x01}))
+	server.SetSpanID(pdata.NewSpanID([]byte{0x04}))
+	server.SetParentSpanID(pdata.NewSpanID([]byte{0x03}))
+	server.SetName("server")
+	server.SetKind(pdata.SpanKindSERVER)
+	server.Attributes().InsertBool(conventions.OCAttributeSameProcessAsParentSpan, false)
+
+	got := testTraceExporter(td, t)
 	want := []honeycombData{
 		{
 			Data: map[string]interface{}{
-				"meta.span_type":      "link",
-				"span_link_attr":      float64(12345),
-				"trace.trace_id":      "01",
-				"trace.parent_id":     "03",
-				"trace.link.span_id":  "05",
-				"trace.link.trace_id": "04",
+				"A":                             "B",
+				"service.name":                  "test_service",
+				"service_name":                  "test_service",
+				"otel.library.name":             "test_library",
+				"otel.library.version":          "1.0.0",
+				"otel.dropped_attributes_count": float64(4),
+				"attribute_name":                "Hello MessageEvent",
+				"values":                        "[1,2]",
+				"meta.span_type":                "span_event",
+				"name":                          "Some Description",
+				"trace.parent_id":               "02",
+				"trace.parent_name":             "root",
+				"trace.trace_id":                "01",
 			},
 		},
 		{
 			Data: map[string]interface{}{
+				"A":                                      "B",
+				"service.name":                           "test_service",
+				"service_name":                           "test_service",
+				"otel.library.name":                      "test_library",
+				"otel.library.version":                   "1.0.0",
+				"otel.dropped_attributes_count":          float64(1),
+				"otel.dropped_events_count":              float64(2),
+				"otel.dropped_links_count":               float64(3),
 				"duration_ms":                            float64(0),
 				"has_remote_parent":                      false,
-				"name":                                   "client",
-				"service_name":                           "test_service",
-				"source_format":                          "otlp_trace",
+				"name":                                   "root",
+				"span.kind":                              "server",
+				"span_attr_name":                         "Span Attribute",
 				"status.code":                            float64(0),
 				"status.message":                         "OK",
-				"trace.parent_id":                        "02",
-				"trace.span_id":                          "03",
+				"trace.span_id":                          "02",
 				"trace.trace_id":                         "01",
-				"opencensus.resourcetype":                "foobar",
+				"trace.trace_state":                      "vendor=value",
 				"opencensus.same_process_as_parent_span": true,
-				"A":                                      "B",
-				"B":                                      "C",
 			},
 		},
 		{
 			Data: map[string]interface{}{
-				"duration_ms":                            float64(0),
-				"has_remote_parent":                      true,
-				"name":                                   "server",
-				"service_name":                           "test_service",
-				"source_format":                          "otlp_trace",
-				"status.code":                            float64(0),
-				"status.message":                         "OK",
-				"trace.parent_id":                        "03",
-				"trace.span_id":                          "04",
-				"trace.trace_id":                         "01",
-				"A":                                      "B",
-				"B":                                      "C",
-				"opencensus.resourcetype":                "foobar",
-				"opencensus.same_process_as_parent_span": false,
+				"meta.span_type":      "link",
+				"span_link_attr":      float64(12345),
+				"trace.trace_id":      "01",
+				"trace.parent_id":     "03",
+				"trace.link.span_id":  "05",
+				"trace.link.trace_id": "04",
+				"trace.trace_state":   "vendor=link",
 			},
 		},
 		{
 			Data: map[string]interface{}{
-				"A":                       "B",
-				"B":                       "D",
-				"attribute_name":          "Hello MessageEvent",
-				"meta.span_type":          "span_event",
-				"name":                    "Some Description",
-				"opencensus.resourcetype": "override",
-				"service_name":            "test_service",
-				"source_format":           "otlp_trace",
-				"trace.parent_id":         "02",
-				"trace.parent_name":       "root",
-				"trace.trace_id":          "01",
+				"A":                    "B",
+				"service.name":         "test_service",
+				"service_name":         "test_service",
+				"otel.library.name":    "test_library",
+				"otel.library.version": "1.0.0",
+				"duration_ms":          float64(0),
+				"has_remote_parent":    false,
+				"name":                 "client",
+				"span.kind":            "client",
+				"status.code":          float64(14),
+				"status.message":       "unavailable",
+				"trace.parent_id":      "02",
+				"trace.span_id":        "03",
+				"trace.trace_id":       "01",
 			},
 		},
 		{
 			Data: map[string]interface{}{
-				"duration_ms":                            float64(0),
-				"has_remote_parent":                      false,
-				"name":                                   "root",
+				"A":                                      "B",
+				"service.name":                           "test_service",
 				"service_name":                           "test_service",
-				"source_format":                          "otlp_trace",
-				"span_attr_name":                         "Span Attribute",
+				"otel.library.name":                      "test_library",
+				"otel.library.version":                   "1.0.0",
+				"duration_ms":                            float64(0),
+				"has_remote_parent":                      true,
+				"name":                                   "server",
+				"span.kind":                              "server",
 				"status.code":                            float64(0),
 				"status.message":                         "OK",
-				"trace.span_id":                          "02",
+				"trace.parent_id":                        "03",
+				"trace.span_id":                          "04",
 				"trace.trace_id":                         "01",
-				"A":                                      "B",
-				"B":                                      "D",
-				"opencensus.resourcetype":                "override",
-				"opencensus.same_process_as_parent_span": true,
+				"opencensus.same_process_as_parent_span": false,
 			},
 		},
 	}
@@ -278,34 +250,29 @@ func TestExporter(t *testing.T) {
 	}
 }
 
-func TestEmptyNode(t *testing.T) {
-	td := consumerdata.TraceData{
-		Node: nil,
-		Spans: []*tracepb.Span{
-			{
-				TraceId:                 []byte{0x01},
-				SpanId:                  []byte{0x02},
-				Name:                    &tracepb.TruncatableString{Value: "root"},
-				Kind:                    tracepb.Span_SERVER,
-				SameProcessAsParentSpan: &wrapperspb.BoolValue{Value: true},
-			},
-		},
-	}
+func TestEmptyResource(t *testing.T) {
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(1)
+	td.ResourceSpans().At(0).InstrumentationLibrarySpans().Resize(1)
+	spans := td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans()
+	spans.Resize(1)
+	span := spans.At(0)
+	span.SetTraceID(pdata.NewTraceID([]byte{0x01}))
+	span.SetSpanID(pdata.NewSpanID([]byte{0x02}))
+	span.SetName("root")
 
-	got := testTraceExporter(internaldata.OCToTraceData(td), t)
+	got := testTraceExporter(td, t)
 
 	want := []honeycombData{
 		{
 			Data: map[string]interface{}{
-				"duration_ms":                            float64(0),
-				"has_remote_parent":                      false,
-				"name":                                   "root",
-				"source_format":                          "otlp_trace",
-				"status.code":                            float64(0),
-				"status.message":                         "OK",
-				"trace.span_id":                          "02",
-				"trace.trace_id":                         "01",
-				"opencensus.same_process_as_parent_span": true,
+				"duration_ms":       float64(0),
+				"has_remote_parent": false,
+				"name":              "root",
+				"status.code":       float64(0),
+				"status.message":    "OK",
+				"trace.span_id":     "02",
+				"trace.trace_id":    "01",
 			},
 		},
 	}
@@ -315,120 +282,98 @@ func TestEmptyNode(t *testing.T) {
 	}
 }
 
-type testNodeCase struct {
-	name       string
-	identifier *commonpb.ProcessIdentifier
-	expected   map[string]interface{}
+func TestExporterAttributeSampler(t *testing.T) {
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(1)
+	td.ResourceSpans().At(0).InstrumentationLibrarySpans().Resize(1)
+	spans := td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans()
+	spans.Resize(2)
+
+	kept := spans.At(0)
+	kept.SetTraceID(pdata.NewTraceID([]byte{0x01}))
+	kept.SetSpanID(pdata.NewSpanID([]byte{0x02}))
+	kept.SetName("kept")
+	kept.Attributes().InsertInt("sampling.priority", 1)
+
+	dropped := spans.At(1)
+	dropped.SetTraceID(pdata.NewTraceID([]byte{0x03}))
+	dropped.SetSpanID(pdata.NewSpanID([]byte{0x04}))
+	dropped.SetName("dropped")
+	dropped.Attributes().InsertInt("sampling.priority", 0)
+	dropped.Events().Resize(1)
+	dropped.Events().At(0).SetName("dropped event")
+
+	got := testTraceExporterWithSampler(td, SamplerConfig{Type: attributeSamplerType}, t)
+
+	require.Len(t, got, 1)
+	require.Equal(t, "kept", got[0].Data["name"])
 }
 
-func TestNode(t *testing.T) {
+// keySampler drops the traces whose sampling fields hold the drop key.
+type keySampler struct {
+	calls int
+}
 
-	testcases := []testNodeCase{
-		{
-			name: "all_information",
-			identifier: &commonpb.ProcessIdentifier{
-				HostName: "my-host",
-				Pid:      123,
-				StartTimestamp: &timestamppb.Timestamp{
-					Seconds: 1599596112,
-					Nanos:   0,
-				},
-			},
-			expected: map[string]interface{}{
-				"B":                                      "C",
-				"duration_ms":                            float64(0),
-				"has_remote_parent":                      false,
-				"name":                                   "root",
-				"source_format":                          "otlp_trace",
-				"status.code":                            float64(0),
-				"status.message":                         "OK",
-				"trace.span_id":                          "02",
-				"trace.trace_id":                         "01",
-				"opencensus.resourcetype":                "container",
-				"opencensus.same_process_as_parent_span": true,
-				"opencensus.start_timestamp":             "2020-09-08T20:15:12Z",
-				"process.hostname":                       "my-host",
-				"process.pid":                            float64(123),
-				"service_name":                           "test_service",
-			},
-		},
-		{
-			name: "missing_pid_and_time",
-			identifier: &commonpb.ProcessIdentifier{
-				HostName: "my-host",
-			},
-			expected: map[string]interface{}{
-				"B":                                      "C",
-				"duration_ms":                            float64(0),
-				"has_remote_parent":                      false,
-				"name":                                   "root",
-				"source_format":                          "otlp_trace",
-				"status.code":                            float64(0),
-				"status.message":                         "OK",
-				"trace.span_id":                          "02",
-				"trace.trace_id":                         "01",
-				"opencensus.resourcetype":                "container",
-				"opencensus.same_process_as_parent_span": true,
-				"process.hostname":                       "my-host",
-				"service_name":                           "test_service",
-			},
-		},
-		{
-			name:       "nil_identifier",
-			identifier: nil,
-			expected: map[string]interface{}{
-				"B":                                      "C",
-				"duration_ms":                            float64(0),
-				"has_remote_parent":                      false,
-				"name":                                   "root",
-				"source_format":                          "otlp_trace",
-				"status.code":                            float64(0),
-				"status.message":                         "OK",
-				"trace.span_id":                          "02",
-				"trace.trace_id":                         "01",
-				"opencensus.resourcetype":                "container",
-				"opencensus.same_process_as_parent_span": true,
-				"service_name":                           "test_service",
-			},
-		},
+func (s *keySampler) sample(_ []byte, fields map[string]interface{}, _ int) (uint, bool) {
+	s.calls++
+	return 4, fields["key"] != "drop"
+}
+
+func TestExporterSamplesWholeTraces(t *testing.T) {
+	var got []honeycombData
+	server := testingServer(func(data []honeycombData) {
+		got = append(got, data...)
+	})
+	defer server.Close()
+
+	require.NoError(t, libhoney.Init(libhoney.Config{WriteKey: "test", Dataset: "test", APIHost: server.URL}))
+	s := &keySampler{}
+	exporter := &honeycombExporter{
+		builder: libhoney.NewBuilder(),
+		sampler: s,
+		logger:  zap.NewNop(),
+		onError: func(err error) { require.NoError(t, err) },
 	}
 
-	for _, test := range testcases {
-		t.Run(test.name, func(t *testing.T) {
-			td := consumerdata.TraceData{
-				Node: &commonpb.Node{
-					ServiceInfo: &commonpb.ServiceInfo{Name: "test_service"},
-					Identifier:  test.identifier,
-				},
-				Resource: &resourcepb.Resource{
-					Type: "container",
-					Labels: map[string]string{
-						"B": "C",
-					},
-				},
-				Spans: []*tracepb.Span{
-					{
-						TraceId:                 []byte{0x01},
-						SpanId:                  []byte{0x02},
-						Name:                    &tracepb.TruncatableString{Value: "root"},
-						Kind:                    tracepb.Span_SERVER,
-						SameProcessAsParentSpan: &wrapperspb.BoolValue{Value: true},
-					},
-				},
-			}
-
-			got := testTraceExporter(internaldata.OCToTraceData(td), t)
-
-			want := []honeycombData{
-				{
-					Data: test.expected,
-				},
-			}
-
-			if diff := cmp.Diff(want, got); diff != "" {
-				t.Errorf("otel span: (-want +got):\n%s", diff)
-			}
-		})
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(1)
+	td.ResourceSpans().At(0).InstrumentationLibrarySpans().Resize(1)
+	spans := td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans()
+	spans.Resize(5)
+	// Spans of a trace have different keys, the one of the root span, or of
+	// the local root when the root is missing, decides for the whole trace.
+	for i, tt := range []struct {
+		traceID byte
+		spanID  byte
+		parent  byte
+		key     string
+	}{
+		{traceID: 0x01, spanID: 0x02, parent: 0x01, key: "drop"},
+		{traceID: 0x01, spanID: 0x01, key: "keep"},
+		{traceID: 0x02, spanID: 0x03, parent: 0x04, key: "keep"},
+		{traceID: 0x02, spanID: 0x04, parent: 0x05, key: "drop"},
+		{traceID: 0x01, spanID: 0x06, parent: 0x02, key: "drop"},
+	} {
+		span := spans.At(i)
+		span.SetTraceID(pdata.NewTraceID([]byte{tt.traceID}))
+		span.SetSpanID(pdata.NewSpanID([]byte{tt.spanID}))
+		if tt.parent != 0 {
+			span.SetParentSpanID(pdata.NewSpanID([]byte{tt.parent}))
+		}
+		span.Attributes().InsertString("key", tt.key)
+		span.Events().Resize(1)
+		span.Events().At(0).SetName("event")
 	}
 
+	dropped, err := exporter.pushTraceData(context.Background(), td)
+	require.NoError(t, err)
+	assert.Equal(t, 0, dropped)
+	libhoney.Close()
+
+	assert.Equal(t, 2, s.calls)
+	// The 3 spans of the first trace and their events are sent.
+	require.Len(t, got, 6)
+	for _, d := range got {
+		assert.Equal(t, "01", d.Data["trace.trace_id"])
+	}
 }
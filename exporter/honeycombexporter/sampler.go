@@ -0,0 +1,302 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package honeycombexporter
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	staticSamplerType    = "static"
+	dynamicSamplerType   = "dynamic"
+	attributeSamplerType = "attribute"
+
+	// statusCodeField is the field holding the status code of a span.
+	statusCodeField = "status.code"
+	// samplingPriorityField is the attribute used to force a span to be kept
+	// (a priority above 0) or dropped (a priority of 0).
+	samplingPriorityField = "sampling.priority"
+	// sampleRateField is the attribute holding the rate a span was already
+	// sampled at before reaching the collector.
+	sampleRateField = "SampleRate"
+
+	// keySeparator separates the values of the key fields in a sampling key.
+	keySeparator = "•"
+	// minMovingAverage is the moving average under which a key is forgotten.
+	minMovingAverage = 0.01
+)
+
+// defaultKeyFields are the key fields of the dynamic sampler when none are configured.
+var defaultKeyFields = []string{"service.name", "http.status_code", statusCodeField}
+
+// sampler decides which traces are sent to Honeycomb.
+type sampler interface {
+	// sample returns the rate the trace with the given ID is sampled at, and
+	// whether it should be sent. It is called once per trace of a batch, with
+	// the fields of the root span of the trace, or of its local root when the
+	// root isn't part of the batch, and the number of spans of the trace in
+	// the batch. The decision applies to every span and event of the trace.
+	sample(traceID []byte, fields map[string]interface{}, spanCount int) (rate uint, keep bool)
+}
+
+// newSampler creates the sampler described by the configuration.
+func newSampler(cfg *Config) (sampler, error) {
+	static := &staticSampler{rate: cfg.SampleRate}
+	if static.rate == 0 {
+		static.rate = 1
+	}
+
+	switch cfg.Sampler.Type {
+	case "", staticSamplerType:
+		return static, nil
+	case dynamicSamplerType:
+		return newDynamicSampler(cfg.Sampler)
+	case attributeSamplerType:
+		return &attributeSampler{fallback: static}, nil
+	default:
+		return nil, fmt.Errorf("unknown sampler type %q", cfg.Sampler.Type)
+	}
+}
+
+// staticSampler sends every span, with the same sample rate recorded on each.
+type staticSampler struct {
+	rate uint
+}
+
+func (s *staticSampler) sample([]byte, map[string]interface{}, int) (uint, bool) {
+	return s.rate, true
+}
+
+// attributeSampler honors the sampling decisions made before spans reach the
+// collector, recorded in the sampling.priority and SampleRate attributes of
+// the root span. Traces without any of them are sampled by the fallback sampler.
+type attributeSampler struct {
+	fallback sampler
+}
+
+func (s *attributeSampler) sample(traceID []byte, fields map[string]interface{}, spanCount int) (uint, bool) {
+	if priority, ok := numericField(fields, samplingPriorityField); ok {
+		return 1, priority > 0
+	}
+	if rate, ok := numericField(fields, sampleRateField); ok && rate >= 1 {
+		return uint(rate), true
+	}
+	return s.fallback.sample(traceID, fields, spanCount)
+}
+
+// dynamicSampler samples traces by key, built from the values of the key
+// fields of their root span. It keeps an exponential moving average of the
+// span count of each key, and adjusts the sample rate of each key at every
+// interval so that the total number of spans sent reaches the goal throughput.
+// Sample rates are distributed by the logarithm of the count of each key, so
+// that rare keys are sampled at a lower rate than frequent ones.
+//
+// The spans of a trace may arrive in several batches, so the key and the rate
+// picked for each trace are remembered until no span of the trace was seen
+// for a whole interval.
+type dynamicSampler struct {
+	keyFields  []string
+	goalCount  float64
+	interval   time.Duration
+	weight     float64
+	now        func() time.Time
+	mu         sync.Mutex
+	nextUpdate time.Time
+	counts     map[string]float64
+	averages   map[string]float64
+	rates      map[string]uint
+	// traces holds the decisions made for the traces seen in the current
+	// interval, previousTraces the ones seen in the previous interval.
+	traces         map[string]traceDecision
+	previousTraces map[string]traceDecision
+}
+
+// traceDecision is the key and sample rate picked for a trace.
+type traceDecision struct {
+	key  string
+	rate uint
+}
+
+func newDynamicSampler(cfg SamplerConfig) (*dynamicSampler, error) {
+	keyFields := cfg.KeyFields
+	if len(keyFields) == 0 {
+		keyFields = defaultKeyFields
+	}
+	if cfg.GoalThroughputPerSec == 0 {
+		return nil, fmt.Errorf("the %s sampler requires a goal throughput", dynamicSamplerType)
+	}
+	if cfg.AdjustmentInterval <= 0 {
+		return nil, fmt.Errorf("the %s sampler requires a positive adjustment interval", dynamicSamplerType)
+	}
+	if cfg.Weight <= 0 || cfg.Weight > 1 {
+		return nil, fmt.Errorf("the %s sampler requires a weight between 0 and 1, got %v", dynamicSamplerType, cfg.Weight)
+	}
+
+	return &dynamicSampler{
+		keyFields: keyFields,
+		goalCount: float64(cfg.GoalThroughputPerSec) * cfg.AdjustmentInterval.Seconds(),
+		interval:  cfg.AdjustmentInterval,
+		weight:    cfg.Weight,
+		now:       time.Now,
+		counts:    make(map[string]float64),
+		averages:  make(map[string]float64),
+		rates:     make(map[string]uint),
+		traces:    make(map[string]traceDecision),
+	}, nil
+}
+
+func (s *dynamicSampler) sample(traceID []byte, fields map[string]interface{}, spanCount int) (uint, bool) {
+	s.mu.Lock()
+	now := s.now()
+	if s.nextUpdate.IsZero() {
+		s.nextUpdate = now.Add(s.interval)
+	} else if !now.Before(s.nextUpdate) {
+		s.updateRates()
+		s.previousTraces = s.traces
+		s.traces = make(map[string]traceDecision)
+		s.nextUpdate = now.Add(s.interval)
+	}
+	decision, ok := s.traces[string(traceID)]
+	if !ok {
+		decision, ok = s.previousTraces[string(traceID)]
+		if !ok {
+			decision.key = s.key(fields)
+			// Keys that have not been seen for a whole interval are not sampled yet.
+			decision.rate, ok = s.rates[decision.key]
+			if !ok {
+				decision.rate = 1
+			}
+		}
+		s.traces[string(traceID)] = decision
+	}
+	s.counts[decision.key] += float64(spanCount)
+	s.mu.Unlock()
+
+	return decision.rate, shouldKeepTrace(traceID, decision.rate)
+}
+
+// key builds the sampling key from the values of the key fields.
+func (s *dynamicSampler) key(fields map[string]interface{}) string {
+	values := make([]string, len(s.keyFields))
+	for i, field := range s.keyFields {
+		if v, ok := fields[field]; ok {
+			values[i] = fmt.Sprint(v)
+		}
+	}
+	return strings.Join(values, keySeparator)
+}
+
+// updateRates folds the counts of the last interval into the moving averages
+// and recomputes the sample rates from them. It must be called with the lock held.
+func (s *dynamicSampler) updateRates() {
+	// Keys that were not seen in the last interval decay towards 0.
+	for key := range s.averages {
+		if _, ok := s.counts[key]; !ok {
+			s.counts[key] = 0
+		}
+	}
+	for key, count := range s.counts {
+		average := s.weight*count + (1-s.weight)*s.averages[key]
+		if average < minMovingAverage {
+			delete(s.averages, key)
+			continue
+		}
+		s.averages[key] = average
+	}
+	s.counts = make(map[string]float64)
+	s.rates = computeSampleRates(s.averages, s.goalCount)
+}
+
+// computeSampleRates distributes the goal count between keys, in proportion
+// to the logarithm of their counts. The share of keys that don't use all of
+// it is redistributed to the following ones, in increasing order of count.
+func computeSampleRates(counts map[string]float64, goalCount float64) map[string]uint {
+	rates := make(map[string]uint, len(counts))
+
+	keys := make([]string, 0, len(counts))
+	var total, logSum float64
+	for key, count := range counts {
+		keys = append(keys, key)
+		total += count
+		logSum += math.Log10(math.Max(count, 1))
+	}
+
+	if total <= goalCount || logSum == 0 {
+		for _, key := range keys {
+			rates[key] = 1
+		}
+		return rates
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] == counts[keys[j]] {
+			return keys[i] < keys[j]
+		}
+		return counts[keys[i]] < counts[keys[j]]
+	})
+
+	goalRatio := goalCount / logSum
+	extra := 0.0
+	for i, key := range keys {
+		count := math.Max(counts[key], 1)
+		goalForKey := math.Max(1, math.Log10(count)*goalRatio)
+		extraForKey := extra / float64(len(keys)-i)
+		goalForKey += extraForKey
+		extra -= extraForKey
+
+		if count <= goalForKey {
+			rates[key] = 1
+			extra += goalForKey - count
+			continue
+		}
+		rate := math.Ceil(count / goalForKey)
+		rates[key] = uint(rate)
+		extra += goalForKey - count/rate
+	}
+	return rates
+}
+
+// shouldKeepTrace decides whether a trace is kept at the given sample rate,
+// from the hash of its ID. A trace kept at a rate is kept at all lower rates.
+func shouldKeepTrace(traceID []byte, rate uint) bool {
+	if rate <= 1 {
+		return true
+	}
+	h := fnv.New32a()
+	h.Write(traceID)
+	return uint64(h.Sum32())*uint64(rate) <= math.MaxUint32
+}
+
+// numericField returns the value of a numeric field, which may also be
+// recorded as a string.
+func numericField(fields map[string]interface{}, field string) (float64, bool) {
+	switch v := fields[field].(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
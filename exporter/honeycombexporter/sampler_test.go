@@ -0,0 +1,255 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package honeycombexporter
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSampler(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		want    sampler
+		wantErr bool
+	}{
+		{
+			name: "default",
+			cfg:  Config{SampleRate: 5},
+			want: &staticSampler{rate: 5},
+		},
+		{
+			name: "static without rate",
+			cfg:  Config{Sampler: SamplerConfig{Type: staticSamplerType}},
+			want: &staticSampler{rate: 1},
+		},
+		{
+			name: "attribute",
+			cfg:  Config{SampleRate: 2, Sampler: SamplerConfig{Type: attributeSamplerType}},
+			want: &attributeSampler{fallback: &staticSampler{rate: 2}},
+		},
+		{
+			name:    "unknown",
+			cfg:     Config{Sampler: SamplerConfig{Type: "random"}},
+			wantErr: true,
+		},
+		{
+			name:    "dynamic without goal throughput",
+			cfg:     Config{Sampler: SamplerConfig{Type: dynamicSamplerType, AdjustmentInterval: time.Second, Weight: 0.5}},
+			wantErr: true,
+		},
+		{
+			name:    "dynamic without adjustment interval",
+			cfg:     Config{Sampler: SamplerConfig{Type: dynamicSamplerType, GoalThroughputPerSec: 1, Weight: 0.5}},
+			wantErr: true,
+		},
+		{
+			name:    "dynamic with invalid weight",
+			cfg:     Config{Sampler: SamplerConfig{Type: dynamicSamplerType, GoalThroughputPerSec: 1, AdjustmentInterval: time.Second, Weight: 2}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newSampler(&tt.cfg)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAttributeSampler(t *testing.T) {
+	s := &attributeSampler{fallback: &staticSampler{rate: 3}}
+
+	tests := []struct {
+		name     string
+		fields   map[string]interface{}
+		wantRate uint
+		wantKeep bool
+	}{
+		{
+			name:     "no attributes",
+			fields:   map[string]interface{}{},
+			wantRate: 3,
+			wantKeep: true,
+		},
+		{
+			name:     "priority keep",
+			fields:   map[string]interface{}{samplingPriorityField: int64(1), sampleRateField: int64(10)},
+			wantRate: 1,
+			wantKeep: true,
+		},
+		{
+			name:     "priority drop",
+			fields:   map[string]interface{}{samplingPriorityField: int64(0)},
+			wantRate: 1,
+			wantKeep: false,
+		},
+		{
+			name:     "sample rate",
+			fields:   map[string]interface{}{sampleRateField: float64(20)},
+			wantRate: 20,
+			wantKeep: true,
+		},
+		{
+			name:     "sample rate as string",
+			fields:   map[string]interface{}{sampleRateField: "7"},
+			wantRate: 7,
+			wantKeep: true,
+		},
+		{
+			name:     "invalid sample rate",
+			fields:   map[string]interface{}{sampleRateField: "often"},
+			wantRate: 3,
+			wantKeep: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			rate, keep := s.sample([]byte{0x01}, tt.fields, 1)
+			assert.Equal(t, tt.wantRate, rate)
+			assert.Equal(t, tt.wantKeep, keep)
+		})
+	}
+}
+
+func TestDynamicSampler(t *testing.T) {
+	s, err := newDynamicSampler(SamplerConfig{
+		KeyFields:            []string{"service.name", statusCodeField},
+		GoalThroughputPerSec: 10,
+		AdjustmentInterval:   10 * time.Second,
+		Weight:               1,
+	})
+	require.NoError(t, err)
+
+	now := time.Unix(0, 0)
+	s.now = func() time.Time { return now }
+
+	frequent := map[string]interface{}{"service.name": "api", statusCodeField: int32(0)}
+	rare := map[string]interface{}{"service.name": "api", statusCodeField: int32(2)}
+
+	// Nothing is sampled until the first adjustment.
+	for i := 0; i < 10000; i++ {
+		rate, keep := s.sample([]byte{byte(i), byte(i >> 8)}, frequent, 1)
+		assert.EqualValues(t, 1, rate)
+		assert.True(t, keep)
+	}
+	for i := 0; i < 10; i++ {
+		s.sample([]byte{byte(i), 0xff}, rare, 1)
+	}
+
+	now = now.Add(10 * time.Second)
+
+	frequentRate, _ := s.sample([]byte{0x01, 0x01, 0x01}, frequent, 1)
+	rareRate, rareKeep := s.sample([]byte{0x02, 0x02, 0x02}, rare, 1)
+	assert.Greater(t, frequentRate, uint(1))
+	assert.EqualValues(t, 1, rareRate)
+	assert.True(t, rareKeep)
+
+	// The frequent key takes most of the budget left by the rare key.
+	expectedSent := 10000/float64(frequentRate) + 10
+	assert.InDelta(t, 100, expectedSent, 20)
+}
+
+func TestDynamicSamplerTraceDecision(t *testing.T) {
+	s, err := newDynamicSampler(SamplerConfig{
+		KeyFields:            []string{"service.name", statusCodeField},
+		GoalThroughputPerSec: 10,
+		AdjustmentInterval:   10 * time.Second,
+		Weight:               1,
+	})
+	require.NoError(t, err)
+
+	now := time.Unix(0, 0)
+	s.now = func() time.Time { return now }
+
+	frequent := map[string]interface{}{"service.name": "api", statusCodeField: int32(0)}
+	rare := map[string]interface{}{"service.name": "api", statusCodeField: int32(2)}
+
+	s.sample([]byte{0x00}, frequent, 10000)
+	s.sample([]byte{0x00}, rare, 10)
+	now = now.Add(10 * time.Second)
+	s.sample([]byte{0x00}, rare, 0)
+	require.Greater(t, s.rates[s.key(frequent)], uint(1))
+
+	// The rate picked for the first spans of a trace applies to the spans
+	// of the trace received later, whatever their key.
+	traceID := []byte{0x01, 0x02, 0x03}
+	rate, keep := s.sample(traceID, rare, 2)
+	assert.EqualValues(t, 1, rate)
+	assert.True(t, keep)
+	rate, keep = s.sample(traceID, frequent, 3)
+	assert.EqualValues(t, 1, rate)
+	assert.True(t, keep)
+	assert.EqualValues(t, 5, s.counts[s.key(rare)])
+	assert.EqualValues(t, 0, s.counts[s.key(frequent)])
+
+	// The decision is kept while spans of the trace keep arriving.
+	now = now.Add(10 * time.Second)
+	rate, _ = s.sample(traceID, frequent, 1)
+	assert.EqualValues(t, 1, rate)
+	now = now.Add(10 * time.Second)
+	rate, _ = s.sample(traceID, frequent, 1)
+	assert.EqualValues(t, 1, rate)
+
+	// It is forgotten once no span was seen for a whole interval.
+	now = now.Add(20 * time.Second)
+	s.sample([]byte{0x00}, frequent, 1)
+	now = now.Add(10 * time.Second)
+	s.sample([]byte{0x00}, frequent, 1)
+	_, ok := s.traces[string(traceID)]
+	assert.False(t, ok)
+	_, ok = s.previousTraces[string(traceID)]
+	assert.False(t, ok)
+}
+
+func TestComputeSampleRates(t *testing.T) {
+	assert.Equal(t, map[string]uint{"a": 1, "b": 1}, computeSampleRates(map[string]float64{"a": 10, "b": 20}, 100))
+	assert.Equal(t, map[string]uint{}, computeSampleRates(map[string]float64{}, 100))
+
+	rates := computeSampleRates(map[string]float64{"a": 1000, "b": 100000}, 100)
+	assert.Less(t, rates["a"], rates["b"])
+}
+
+func TestShouldKeepTrace(t *testing.T) {
+	assert.True(t, shouldKeepTrace([]byte{0x01}, 0))
+	assert.True(t, shouldKeepTrace([]byte{0x01}, 1))
+
+	r := rand.New(rand.NewSource(1))
+	kept := 0
+	for i := 0; i < 10000; i++ {
+		traceID := make([]byte, 16)
+		r.Read(traceID)
+		if shouldKeepTrace(traceID, 10) {
+			kept++
+			// A trace kept at a rate is kept at all lower rates.
+			assert.True(t, shouldKeepTrace(traceID, 5))
+		}
+	}
+	assert.InDelta(t, 1000, kept, 200)
+}
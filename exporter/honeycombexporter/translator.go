@@ -15,148 +15,141 @@
 package honeycombexporter
 
 import (
-	"time"
-
-	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
-	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
-	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
-// spanAttributesToMap converts an opencensus proto Span_Attributes object into a map
-// of strings to generic types usable for sending events to honeycomb.
-func spanAttributesToMap(spanAttrs *tracepb.Span_Attributes) map[string]interface{} {
-	var attrs map[string]interface{}
-
-	if spanAttrs == nil {
-		return attrs
-	}
-
-	attrMap := spanAttrs.GetAttributeMap()
-	if attrMap == nil {
-		return attrs
-	}
+// Fields added to events for the span properties without a dedicated field.
+const (
+	serviceNameField            = "service_name"
+	libraryNameField            = "otel.library.name"
+	libraryVersionField         = "otel.library.version"
+	spanKindField               = "span.kind"
+	traceStateField             = "trace.trace_state"
+	droppedAttributesCountField = "otel.dropped_attributes_count"
+	droppedEventsCountField     = "otel.dropped_events_count"
+	droppedLinksCountField      = "otel.dropped_links_count"
+)
 
-	attrs = make(map[string]interface{}, len(attrMap))
-
-	for key, attributeValue := range attrMap {
-		switch val := attributeValue.Value.(type) {
-		case *tracepb.AttributeValue_StringValue:
-			attrs[key] = attributeValueAsString(attributeValue)
-		case *tracepb.AttributeValue_BoolValue:
-			attrs[key] = val.BoolValue
-		case *tracepb.AttributeValue_IntValue:
-			attrs[key] = val.IntValue
-		case *tracepb.AttributeValue_DoubleValue:
-			attrs[key] = val.DoubleValue
-		}
-	}
-	return attrs
+// fieldAdder is implemented by libhoney events.
+type fieldAdder interface {
+	AddField(key string, val interface{})
 }
 
-// hasRemoteParent returns true if the this span is a child of a span in a different process.
-func hasRemoteParent(span *tracepb.Span) bool {
-	if sameProcess := span.GetSameProcessAsParentSpan(); sameProcess != nil {
-		return !sameProcess.Value
+// addFields adds all the fields to an event.
+func addFields(ev fieldAdder, fields map[string]interface{}) {
+	for k, v := range fields {
+		ev.AddField(k, v)
 	}
-	return false
 }
 
-// timestampToTime converts a protobuf timestamp into a time.Time.
-func timestampToTime(ts *timestamppb.Timestamp) (t time.Time) {
-	if ts == nil {
-		return
+// addDroppedCount adds a field counting dropped items, if any were dropped.
+func addDroppedCount(ev fieldAdder, field string, count uint32) {
+	if count != 0 {
+		ev.AddField(field, count)
 	}
-	return time.Unix(ts.Seconds, int64(ts.Nanos)).UTC()
 }
 
-// getStatusCode returns the status code
-func getStatusCode(status *tracepb.Status) int32 {
-	if status != nil {
-		return int32(codes.Code(status.Code))
-	}
-
-	return int32(codes.OK)
+// attributesToFields converts an attribute map into a map of strings to
+// generic types usable for sending events to honeycomb. Maps and arrays are
+// encoded as JSON strings.
+func attributesToFields(attrs pdata.AttributeMap) map[string]interface{} {
+	fields := make(map[string]interface{}, attrs.Len())
+
+	attrs.ForEach(func(key string, value pdata.AttributeValue) {
+		switch value.Type() {
+		case pdata.AttributeValueSTRING:
+			fields[key] = value.StringVal()
+		case pdata.AttributeValueBOOL:
+			fields[key] = value.BoolVal()
+		case pdata.AttributeValueINT:
+			fields[key] = value.IntVal()
+		case pdata.AttributeValueDOUBLE:
+			fields[key] = value.DoubleVal()
+		case pdata.AttributeValueMAP, pdata.AttributeValueARRAY:
+			fields[key] = tracetranslator.AttributeValueToString(value, false)
+		}
+	})
+	return fields
 }
 
-// getStatusMessage returns the status message as a string
-func getStatusMessage(status *tracepb.Status) string {
-	if status != nil {
-		if len(status.GetMessage()) > 0 {
-			return status.GetMessage()
-		}
+// getResourceFields extracts the resource fields that should be added as
+// underlays on every span of the resource. The service name is also added as
+// service_name, as the field was named before spans were translated from
+// OpenTelemetry resources.
+func getResourceFields(resource pdata.Resource) map[string]interface{} {
+	if resource.IsNil() {
+		return nil
 	}
 
-	return codes.OK.String()
+	fields := attributesToFields(resource.Attributes())
+	if serviceName, ok := fields[conventions.AttributeServiceName]; ok {
+		fields[serviceNameField] = serviceName
+	}
+	return fields
 }
 
-// getTraceLevelfFields extracts the node and resource fields from TraceData that
-// should be added as underlays on every span in a trace.
-func getTraceLevelFields(node *commonpb.Node, resource *resourcepb.Resource, sourceFormat string) map[string]interface{} {
-	fields := make(map[string]interface{})
-
-	// we want to get the service_name and some opencensus fields that are set when
-	// run as an agent from the node.
-	nodeFields := func(node *commonpb.Node, fields map[string]interface{}) {
-		if node == nil {
-			return
-		}
-		if node.ServiceInfo != nil {
-			fields["service_name"] = node.ServiceInfo.Name
-		}
-		if process := node.GetIdentifier(); process != nil {
-			if len(process.HostName) != 0 {
-				fields["process.hostname"] = process.HostName
-			}
-			if process.Pid != 0 {
-				fields["process.pid"] = process.Pid
-			}
-
-			startTime := timestampToTime(process.StartTimestamp)
-			if startTime != (time.Time{}) {
-				fields["opencensus.start_timestamp"] = startTime
-			}
-		}
+// getInstrumentationLibraryFields extracts the name and version of the
+// library spans were recorded with.
+func getInstrumentationLibraryFields(library pdata.InstrumentationLibrary) map[string]interface{} {
+	fields := make(map[string]interface{}, 2)
+	if library.IsNil() {
+		return fields
 	}
 
-	// all resource labels should be applied as trace level fields
-	resourceFields := func(resource *resourcepb.Resource, fields map[string]interface{}) {
-		if resource == nil {
-			return
-		}
-		resourceType := resource.GetType()
-		if resourceType != "" {
-			fields["opencensus.resourcetype"] = resourceType
-		}
-		for k, v := range resource.GetLabels() {
-			fields[k] = v
-		}
+	if name := library.Name(); name != "" {
+		fields[libraryNameField] = name
 	}
-
-	nodeFields(node, fields)
-	resourceFields(resource, fields)
-
-	if sourceFormat := sourceFormat; len(sourceFormat) > 0 {
-		fields["source_format"] = sourceFormat
+	if version := library.Version(); version != "" {
+		fields[libraryVersionField] = version
 	}
-
 	return fields
 }
 
-// attributeValueAsString converts a opencensus proto AttributeValue object into a string
-func attributeValueAsString(val *tracepb.AttributeValue) string {
-	if wrapper := val.GetStringValue(); wrapper != nil {
-		return wrapper.GetValue()
+// hasRemoteParent returns true if the this span is a child of a span in a different process.
+func hasRemoteParent(span pdata.Span) bool {
+	if sameProcess, ok := span.Attributes().Get(conventions.OCAttributeSameProcessAsParentSpan); ok &&
+		sameProcess.Type() == pdata.AttributeValueBOOL {
+		return !sameProcess.BoolVal()
 	}
+	return false
+}
 
+// getSpanKind returns the kind of the span, following OpenTracing conventions.
+func getSpanKind(kind pdata.SpanKind) string {
+	switch kind {
+	case pdata.SpanKindCLIENT:
+		return string(tracetranslator.OpenTracingSpanKindClient)
+	case pdata.SpanKindSERVER:
+		return string(tracetranslator.OpenTracingSpanKindServer)
+	case pdata.SpanKindPRODUCER:
+		return string(tracetranslator.OpenTracingSpanKindProducer)
+	case pdata.SpanKindCONSUMER:
+		return string(tracetranslator.OpenTracingSpanKindConsumer)
+	case pdata.SpanKindINTERNAL:
+		return string(tracetranslator.OpenTracingSpanKindInternal)
+	}
 	return ""
 }
 
-// truncatableStringAsString just returns the value part as a string. "" if s is nil
-func truncatableStringAsString(s *tracepb.TruncatableString) string {
-	if s != nil {
-		return s.GetValue()
+// getStatusCode returns the status code
+func getStatusCode(status pdata.SpanStatus) int32 {
+	if !status.IsNil() {
+		return int32(status.Code())
 	}
-	return ""
+
+	return int32(codes.OK)
+}
+
+// getStatusMessage returns the status message as a string
+func getStatusMessage(status pdata.SpanStatus) string {
+	if !status.IsNil() {
+		if len(status.Message()) > 0 {
+			return status.Message()
+		}
+	}
+
+	return codes.OK.String()
 }
@@ -16,87 +16,78 @@ package honeycombexporter
 
 import (
 	"testing"
-	"time"
 
-	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
-	"google.golang.org/protobuf/types/known/timestamppb"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
 )
 
-func TestSpanAttributesToMap(t *testing.T) {
-
-	newSpanAttr := func(key string, value *tracepb.AttributeValue, count int32) *tracepb.Span_Attributes {
-		return &tracepb.Span_Attributes{
-			AttributeMap: map[string]*tracepb.AttributeValue{
-				key: value,
-			},
-			DroppedAttributesCount: count,
-		}
-	}
-
-	spanAttrs := []*tracepb.Span_Attributes{
-		newSpanAttr("foo", &tracepb.AttributeValue{
-			Value: &tracepb.AttributeValue_StringValue{
-				StringValue: &tracepb.TruncatableString{Value: "bar"},
-			},
-		}, 0),
-		newSpanAttr("foo", &tracepb.AttributeValue{
-			Value: &tracepb.AttributeValue_IntValue{
-				IntValue: 1234,
-			},
-		}, 0),
-		newSpanAttr("foo", &tracepb.AttributeValue{
-			Value: &tracepb.AttributeValue_BoolValue{
-				BoolValue: true,
-			},
-		}, 0),
-		newSpanAttr("foo", &tracepb.AttributeValue{
-			Value: &tracepb.AttributeValue_DoubleValue{
-				DoubleValue: 0.3145,
-			},
-		}, 0),
-		nil,
-		{
-			AttributeMap:           nil,
-			DroppedAttributesCount: 0,
-		},
-	}
-
-	wantResults := []map[string]interface{}{
-		{"foo": "bar"},
-		{"foo": int64(1234)},
-		{"foo": true},
-		{"foo": 0.3145},
-		{},
-		{},
-	}
-
-	for i, attrs := range spanAttrs {
-		got := spanAttributesToMap(attrs)
-		want := wantResults[i]
-		for k := range want {
-			if interface{}(got[k]) != want[k] {
-				t.Errorf("Got: %+v, Want: %+v", got[k], want[k])
-			}
-		}
-		i++
-	}
+func TestAttributesToFields(t *testing.T) {
+	nested := pdata.NewAttributeValueMap()
+	nested.MapVal().InsertString("key", "value")
+	values := pdata.NewAttributeValueArray()
+	values.ArrayVal().Append(pdata.NewAttributeValueString("a"))
+
+	attrs := pdata.NewAttributeMap()
+	attrs.InsertString("string", "bar")
+	attrs.InsertInt("int", 1234)
+	attrs.InsertBool("bool", true)
+	attrs.InsertDouble("double", 0.3145)
+	attrs.Insert("map", nested)
+	attrs.Insert("array", values)
+
+	assert.Equal(t, map[string]interface{}{
+		"string": "bar",
+		"int":    int64(1234),
+		"bool":   true,
+		"double": 0.3145,
+		"map":    `{"key":"value"}`,
+		"array":  `["a"]`,
+	}, attributesToFields(attrs))
+
+	assert.Equal(t, map[string]interface{}{}, attributesToFields(pdata.NewAttributeMap()))
+}
+
+func TestGetResourceFields(t *testing.T) {
+	assert.Nil(t, getResourceFields(pdata.NewResource()))
+
+	resource := pdata.NewResource()
+	resource.InitEmpty()
+	resource.Attributes().InsertString(conventions.AttributeServiceName, "test_service")
+	resource.Attributes().InsertString(conventions.AttributeHostName, "my-host")
+
+	assert.Equal(t, map[string]interface{}{
+		"service.name": "test_service",
+		"service_name": "test_service",
+		"host.name":    "my-host",
+	}, getResourceFields(resource))
 }
 
-func TestTimestampToTime(t *testing.T) {
-	var t1 time.Time
-	emptyTime := timestampToTime(nil)
-	if t1 != emptyTime {
-		t.Errorf("Expected %+v, Got: %+v\n", t1, emptyTime)
-	}
-
-	t2 := time.Now()
-	seconds := t2.UnixNano() / 1000000000
-	nowTime := timestampToTime(&timestamppb.Timestamp{
-		Seconds: seconds,
-		Nanos:   int32(t2.UnixNano() - (seconds * 1000000000)),
-	})
-
-	if !t2.Equal(nowTime) {
-		t.Errorf("Expected %+v, Got %+v\n", t2, nowTime)
-	}
+func TestGetInstrumentationLibraryFields(t *testing.T) {
+	assert.Equal(t, map[string]interface{}{}, getInstrumentationLibraryFields(pdata.NewInstrumentationLibrary()))
+
+	library := pdata.NewInstrumentationLibrary()
+	library.InitEmpty()
+	library.SetName("test_library")
+	assert.Equal(t, map[string]interface{}{"otel.library.name": "test_library"}, getInstrumentationLibraryFields(library))
+
+	library.SetVersion("1.0.0")
+	assert.Equal(t, map[string]interface{}{
+		"otel.library.name":    "test_library",
+		"otel.library.version": "1.0.0",
+	}, getInstrumentationLibraryFields(library))
+}
+
+func TestGetStatus(t *testing.T) {
+	status := pdata.NewSpanStatus()
+	assert.Equal(t, int32(0), getStatusCode(status))
+	assert.Equal(t, "OK", getStatusMessage(status))
+
+	status.InitEmpty()
+	status.SetCode(pdata.StatusCode(5))
+	assert.Equal(t, int32(5), getStatusCode(status))
+	assert.Equal(t, "OK", getStatusMessage(status))
+
+	status.SetMessage("not found")
+	assert.Equal(t, "not found", getStatusMessage(status))
 }
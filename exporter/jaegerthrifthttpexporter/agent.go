@@ -0,0 +1,276 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jaegerthrifthttpexporter
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/jaegertracing/jaeger/thrift-gen/agent"
+	"github.com/jaegertracing/jaeger/thrift-gen/jaeger"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+	"go.uber.org/zap"
+)
+
+// emitBatchOverhead is the maximum size of an emitBatch message, without its
+// process and spans.
+const emitBatchOverhead = 70
+
+// newAgentTraceExporter returns a new exporter sending Jaeger Thrift batches
+// to a Jaeger agent over UDP.
+// The endpoint is the host:port of the agent, typically localhost:6831 with
+// the compact protocol and localhost:6832 with the binary protocol.
+// The maxPacketSize is the maximum size of the UDP packets, batches are split
+// to fit in them.
+func newAgentTraceExporter(
+	config configmodels.Exporter,
+	logger *zap.Logger,
+	endpoint string,
+	protocol string,
+	maxPacketSize int,
+) (component.TraceExporter, error) {
+	s, err := newJaegerThriftAgentSender(logger, endpoint, protocol, maxPacketSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return exporterhelper.NewTraceExporter(
+		config,
+		s.pushTraceData,
+		exporterhelper.WithShutdown(s.shutdown))
+}
+
+func newJaegerThriftAgentSender(
+	logger *zap.Logger,
+	endpoint string,
+	protocol string,
+	maxPacketSize int,
+) (*jaegerThriftAgentSender, error) {
+	var protocolFactory thrift.TProtocolFactory
+	switch protocol {
+	case agentProtocolCompact:
+		protocolFactory = thrift.NewTCompactProtocolFactory()
+	case agentProtocolBinary:
+		protocolFactory = thrift.NewTBinaryProtocolFactoryDefault()
+	default:
+		return nil, fmt.Errorf("unknown agent protocol %q", protocol)
+	}
+
+	conn, err := net.Dial("udp", endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	buffer := thrift.NewTMemoryBufferLen(maxPacketSize)
+	return &jaegerThriftAgentSender{
+		logger:          logger,
+		conn:            conn,
+		maxPacketSize:   maxPacketSize,
+		protocolFactory: protocolFactory,
+		buffer:          buffer,
+		client:          agent.NewAgentClientFactory(buffer, protocolFactory),
+	}, nil
+}
+
+// jaegerThriftAgentSender forwards spans encoded in the jaeger thrift format
+// to a Jaeger agent, in emitBatch messages sent over UDP.
+type jaegerThriftAgentSender struct {
+	logger          *zap.Logger
+	conn            net.Conn
+	maxPacketSize   int
+	protocolFactory thrift.TProtocolFactory
+
+	// mu protects the buffer the client serializes messages into.
+	mu     sync.Mutex
+	buffer *thrift.TMemoryBuffer
+	client *agent.AgentClient
+}
+
+func (s *jaegerThriftAgentSender) pushTraceData(
+	ctx context.Context,
+	td pdata.Traces,
+) (droppedSpans int, err error) {
+	tBatches, err := tracesToJaegerThrift(td)
+	if err != nil {
+		return td.SpanCount(), consumererror.Permanent(err)
+	}
+
+	sentSpans := 0
+	for i, tBatch := range tBatches {
+		batches, dropped, err := s.splitBatch(tBatch)
+		if err != nil {
+			return td.SpanCount() - sentSpans, consumererror.Permanent(err)
+		}
+		if dropped > 0 {
+			s.logger.Warn("Dropped spans too large to fit in a UDP packet",
+				zap.Int("dropped_spans", dropped),
+				zap.Int("max_packet_size", s.maxPacketSize))
+			droppedSpans += dropped
+		}
+
+		for j, batch := range batches {
+			if err := s.emitBatch(ctx, batch); err != nil {
+				if consumererror.IsPermanent(err) {
+					return td.SpanCount() - sentSpans, err
+				}
+				// Only the spans not sent yet are retried, the batches already
+				// emitted must not be sent twice.
+				unsent := unsentTraces(td, append(batches[j:], tBatches[i+1:]...))
+				return droppedSpans + unsent.SpanCount(), consumererror.PartialTracesError(err, unsent)
+			}
+			sentSpans += len(batch.Spans)
+		}
+	}
+
+	return droppedSpans, nil
+}
+
+// spanKey identifies a span by its trace and span IDs.
+type spanKey struct {
+	traceIDHigh, traceIDLow, spanID int64
+}
+
+// unsentTraces copies the spans of td that are in the given batches, with
+// their resource and instrumentation library, into new traces.
+func unsentTraces(td pdata.Traces, batches []*jaeger.Batch) pdata.Traces {
+	unsentIDs := make(map[spanKey]bool)
+	for _, batch := range batches {
+		for _, span := range batch.Spans {
+			unsentIDs[spanKey{span.TraceIdHigh, span.TraceIdLow, span.SpanId}] = true
+		}
+	}
+
+	unsent := pdata.NewTraces()
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		if rs.IsNil() {
+			continue
+		}
+		unsentRs := -1
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			ils := ilss.At(j)
+			if ils.IsNil() {
+				continue
+			}
+			unsentIls := -1
+			spans := ils.Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				if span.IsNil() || !unsentIDs[spanKeyOf(span)] {
+					continue
+				}
+				if unsentRs < 0 {
+					unsentRs = unsent.ResourceSpans().Len()
+					unsent.ResourceSpans().Resize(unsentRs + 1)
+					rs.Resource().CopyTo(unsent.ResourceSpans().At(unsentRs).Resource())
+				}
+				unsentIlss := unsent.ResourceSpans().At(unsentRs).InstrumentationLibrarySpans()
+				if unsentIls < 0 {
+					unsentIls = unsentIlss.Len()
+					unsentIlss.Resize(unsentIls + 1)
+					ils.InstrumentationLibrary().CopyTo(unsentIlss.At(unsentIls).InstrumentationLibrary())
+				}
+				unsentSpans := unsentIlss.At(unsentIls).Spans()
+				unsentSpans.Resize(unsentSpans.Len() + 1)
+				span.CopyTo(unsentSpans.At(unsentSpans.Len() - 1))
+			}
+		}
+	}
+	return unsent
+}
+
+// spanKeyOf returns the key of a span, computed the same way as the IDs of
+// its jaeger span.
+func spanKeyOf(span pdata.Span) spanKey {
+	traceIDHigh, traceIDLow, _ := tracetranslator.BytesToInt64TraceID(span.TraceID().Bytes())
+	spanID, _ := tracetranslator.BytesToInt64SpanID(span.SpanID().Bytes())
+	return spanKey{traceIDHigh, traceIDLow, spanID}
+}
+
+// splitBatch splits a batch into batches whose emitBatch messages fit in a
+// UDP packet. Spans that don't fit in a packet on their own are dropped.
+func (s *jaegerThriftAgentSender) splitBatch(batch *jaeger.Batch) (batches []*jaeger.Batch, droppedSpans int, err error) {
+	processSize, err := s.serializedSize(batch.Process)
+	if err != nil {
+		return nil, 0, err
+	}
+	maxSpansSize := s.maxPacketSize - emitBatchOverhead - processSize
+
+	var current *jaeger.Batch
+	currentSize := 0
+	for _, span := range batch.Spans {
+		spanSize, err := s.serializedSize(span)
+		if err != nil {
+			return nil, 0, err
+		}
+		if spanSize > maxSpansSize {
+			droppedSpans++
+			continue
+		}
+
+		if current == nil || currentSize+spanSize > maxSpansSize {
+			current = &jaeger.Batch{Process: batch.Process}
+			currentSize = 0
+			batches = append(batches, current)
+		}
+		current.Spans = append(current.Spans, span)
+		currentSize += spanSize
+	}
+
+	return batches, droppedSpans, nil
+}
+
+// serializedSize returns the size of a thrift struct serialized with the
+// protocol of the agent.
+func (s *jaegerThriftAgentSender) serializedSize(obj thrift.TStruct) (int, error) {
+	buffer := thrift.NewTMemoryBuffer()
+	if err := obj.Write(s.protocolFactory.GetProtocol(buffer)); err != nil {
+		return 0, err
+	}
+	return buffer.Len(), nil
+}
+
+// emitBatch sends a batch to the agent in an emitBatch message.
+func (s *jaegerThriftAgentSender) emitBatch(ctx context.Context, batch *jaeger.Batch) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.buffer.Reset()
+	if err := s.client.EmitBatch(ctx, batch); err != nil {
+		return err
+	}
+	if s.buffer.Len() > s.maxPacketSize {
+		return consumererror.Permanent(fmt.Errorf(
+			"batch does not fit in a UDP packet: size %d, max %d, spans %d",
+			s.buffer.Len(), s.maxPacketSize, len(batch.Spans)))
+	}
+
+	_, err := s.conn.Write(s.buffer.Bytes())
+	return err
+}
+
+func (s *jaegerThriftAgentSender) shutdown(context.Context) error {
+	return s.conn.Close()
+}
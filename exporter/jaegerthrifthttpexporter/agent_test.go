@@ -0,0 +1,193 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jaegerthrifthttpexporter
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/jaegertracing/jaeger/thrift-gen/agent"
+	"github.com/jaegertracing/jaeger/thrift-gen/jaeger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	"go.uber.org/zap"
+)
+
+// newTestAgent listens for UDP packets and returns the connection and the
+// address to send packets to.
+func newTestAgent(t *testing.T) (net.PacketConn, string) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn, conn.LocalAddr().String()
+}
+
+// readBatches reads count emitBatch messages from the connection.
+func readBatches(t *testing.T, conn net.PacketConn, protocolFactory thrift.TProtocolFactory, count int) []*jaeger.Batch {
+	var batches []*jaeger.Batch
+	packet := make([]byte, 65535)
+	for i := 0; i < count; i++ {
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+		n, _, err := conn.ReadFrom(packet)
+		require.NoError(t, err)
+
+		buffer := thrift.NewTMemoryBuffer()
+		buffer.Write(packet[:n])
+		protocol := protocolFactory.GetProtocol(buffer)
+
+		name, _, _, err := protocol.ReadMessageBegin()
+		require.NoError(t, err)
+		require.Equal(t, "emitBatch", name)
+
+		args := agent.NewAgentEmitBatchArgs()
+		require.NoError(t, args.Read(protocol))
+		batches = append(batches, args.Batch)
+	}
+	return batches
+}
+
+func newAgentTestTraces(spanCount int) pdata.Traces {
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(1)
+	rs := td.ResourceSpans().At(0)
+	rs.Resource().InitEmpty()
+	rs.Resource().Attributes().InsertString(conventions.AttributeServiceName, "test-service")
+	rs.InstrumentationLibrarySpans().Resize(1)
+	spans := rs.InstrumentationLibrarySpans().At(0).Spans()
+	spans.Resize(spanCount)
+	for i := 0; i < spanCount; i++ {
+		span := spans.At(i)
+		span.SetTraceID(testTraceID)
+		span.SetSpanID(pdata.NewSpanID([]byte{0, 0, 0, 0, 0, 0, 0, byte(i + 1)}))
+		span.SetName("operation")
+	}
+	return td
+}
+
+func TestAgentExporter(t *testing.T) {
+	tests := []struct {
+		protocol        string
+		protocolFactory thrift.TProtocolFactory
+	}{
+		{protocol: agentProtocolCompact, protocolFactory: thrift.NewTCompactProtocolFactory()},
+		{protocol: agentProtocolBinary, protocolFactory: thrift.NewTBinaryProtocolFactoryDefault()},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.protocol, func(t *testing.T) {
+			conn, endpoint := newTestAgent(t)
+
+			exp, err := newAgentTraceExporter(&configmodels.ExporterSettings{}, zap.NewNop(), endpoint, tt.protocol, defaultMaxPacketSize)
+			require.NoError(t, err)
+
+			require.NoError(t, exp.ConsumeTraces(context.Background(), newAgentTestTraces(3)))
+
+			batches := readBatches(t, conn, tt.protocolFactory, 1)
+			assert.Equal(t, "test-service", batches[0].Process.ServiceName)
+			assert.Len(t, batches[0].Spans, 3)
+
+			require.NoError(t, exp.Shutdown(context.Background()))
+		})
+	}
+}
+
+func TestAgentSenderSplitsBatches(t *testing.T) {
+	conn, endpoint := newTestAgent(t)
+
+	s, err := newJaegerThriftAgentSender(zap.NewNop(), endpoint, agentProtocolCompact, 200)
+	require.NoError(t, err)
+	defer s.shutdown(context.Background())
+
+	td := newAgentTestTraces(10)
+	// This span does not fit in a packet on its own.
+	td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(5).
+		Attributes().InsertString("large", strings.Repeat("x", 200))
+
+	dropped, err := s.pushTraceData(context.Background(), td)
+	require.NoError(t, err)
+	assert.Equal(t, 1, dropped)
+
+	var spans int
+	for spans < 9 {
+		batches := readBatches(t, conn, thrift.NewTCompactProtocolFactory(), 1)
+		assert.Equal(t, "test-service", batches[0].Process.ServiceName)
+		assert.Less(t, len(batches[0].Spans), 9)
+		spans += len(batches[0].Spans)
+	}
+	assert.Equal(t, 9, spans)
+}
+
+// failingConn accepts the given number of writes and fails the next ones.
+type failingConn struct {
+	net.Conn
+	writes int
+}
+
+func (c *failingConn) Write(b []byte) (int, error) {
+	if c.writes == 0 {
+		return 0, errors.New("write failed")
+	}
+	c.writes--
+	return c.Conn.Write(b)
+}
+
+func TestAgentSenderRetriesUnsentSpans(t *testing.T) {
+	conn, endpoint := newTestAgent(t)
+
+	s, err := newJaegerThriftAgentSender(zap.NewNop(), endpoint, agentProtocolCompact, 200)
+	require.NoError(t, err)
+	defer s.shutdown(context.Background())
+	s.conn = &failingConn{Conn: s.conn, writes: 1}
+
+	td := newAgentTestTraces(10)
+	// This span does not fit in a packet on its own.
+	td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).
+		Attributes().InsertString("large", strings.Repeat("x", 200))
+
+	dropped, err := s.pushTraceData(context.Background(), td)
+	require.Error(t, err)
+	assert.False(t, consumererror.IsPermanent(err))
+	partialErr, ok := err.(consumererror.PartialError)
+	require.True(t, ok)
+
+	sent := readBatches(t, conn, thrift.NewTCompactProtocolFactory(), 1)[0].Spans
+	require.NotEmpty(t, sent)
+	unsent := partialErr.GetTraces()
+	// The dropped span is neither sent nor retried.
+	assert.Equal(t, 9-len(sent), unsent.SpanCount())
+	assert.Equal(t, 1+unsent.SpanCount(), dropped)
+
+	// The retried spans are the ones following the sent ones, with their resource.
+	rs := unsent.ResourceSpans().At(0)
+	serviceName, _ := rs.Resource().Attributes().Get(conventions.AttributeServiceName)
+	assert.Equal(t, "test-service", serviceName.StringVal())
+	assert.Equal(t, pdata.NewSpanID([]byte{0, 0, 0, 0, 0, 0, 0, byte(len(sent) + 2)}),
+		rs.InstrumentationLibrarySpans().At(0).Spans().At(0).SpanID())
+}
+
+func TestNewAgentSenderUnknownProtocol(t *testing.T) {
+	_, err := newJaegerThriftAgentSender(zap.NewNop(), "localhost:6831", "json", defaultMaxPacketSize)
+	assert.EqualError(t, err, `unknown agent protocol "json"`)
+}
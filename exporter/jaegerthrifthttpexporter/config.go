@@ -24,6 +24,11 @@ import (
 type Config struct {
 	configmodels.ExporterSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
 
+	// Transport is how trace data is sent, either "http" to send it to a
+	// Jaeger collector, or "agent" to send it to a Jaeger agent over UDP. The
+	// default value is "http".
+	Transport string `mapstructure:"transport"`
+
 	// URL is the URL to send the Jaeger trace data to (e.g.:
 	// http://some.url:14268/api/traces).
 	URL string `mapstructure:"url"`
@@ -35,4 +40,19 @@ type Config struct {
 	// Headers are a set of headers to be added to the HTTP request sending
 	// trace data.
 	Headers map[string]string `mapstructure:"headers"`
+
+	// AgentEndpoint is the host:port of the Jaeger agent to send trace data
+	// to with the "agent" transport. The default value is localhost:6831.
+	AgentEndpoint string `mapstructure:"agent_endpoint"`
+
+	// AgentProtocol is the Thrift protocol used to encode trace data sent to
+	// the Jaeger agent, either "compact" or "binary". The default value is
+	// "compact". The Jaeger agent listens on port 6831 for the compact
+	// protocol and on port 6832 for the binary protocol.
+	AgentProtocol string `mapstructure:"agent_protocol"`
+
+	// MaxPacketSize is the maximum size of the UDP packets sent to the Jaeger
+	// agent. Batches are split to fit in them, and spans too large to fit in a
+	// packet on their own are dropped. The default value is 65000 bytes.
+	MaxPacketSize int `mapstructure:"max_packet_size"`
 }
@@ -54,16 +54,33 @@ func TestLoadConfig(t *testing.T) {
 			TypeVal: configmodels.Type(typeStr),
 			NameVal: expectedName,
 		},
-		URL: "http://some.other.location/api/traces",
+		Transport: "http",
+		URL:       "http://some.other.location/api/traces",
 		Headers: map[string]string{
 			"added-entry": "added value",
 			"dot.test":    "test",
 		},
-		Timeout: 2 * time.Second,
+		Timeout:       2 * time.Second,
+		AgentEndpoint: "localhost:6831",
+		AgentProtocol: "compact",
+		MaxPacketSize: 65000,
 	}
 	assert.Equal(t, &expectedCfg, e1)
 
 	te, err := factory.CreateTraceExporter(context.Background(), component.ExporterCreateParams{}, e1)
 	require.NoError(t, err)
 	require.NotNil(t, te)
+
+	e2 := cfg.Exporters["jaeger_thrift/agent"]
+	assert.Equal(t, &Config{
+		ExporterSettings: configmodels.ExporterSettings{
+			TypeVal: configmodels.Type(typeStr),
+			NameVal: "jaeger_thrift/agent",
+		},
+		Transport:     "agent",
+		Timeout:       defaultHTTPTimeout,
+		AgentEndpoint: "localhost:6832",
+		AgentProtocol: "binary",
+		MaxPacketSize: 1500,
+	}, e2)
 }
@@ -16,19 +16,33 @@ package jaegerthrifthttpexporter
 
 import (
 	"errors"
+
+	"github.com/jaegertracing/jaeger/thrift-gen/jaeger"
+)
+
+const (
+	transportHTTP  = "http"
+	transportAgent = "agent"
+
+	agentProtocolCompact = "compact"
+	agentProtocolBinary  = "binary"
+
+	defaultAgentEndpoint = "localhost:6831"
+	defaultMaxPacketSize = 65000
 )
 
 const (
 	// Jaeger Tags
-	ocTimeEventUnknownType    = "oc.timeevent.unknown.type"
-	ocSameProcessAsParentSpan = "oc.sameprocessasparentspan"
-	opencensusLanguage        = "opencensus.language"
-	opencensusExporterVersion = "opencensus.exporterversion"
-	opencensusCoreLibVersion  = "opencensus.corelibversion"
-	opencensusResourceType    = "opencensus.resourcetype"
+	otelLibraryName            = "otel.library.name"
+	otelLibraryVersion         = "otel.library.version"
+	otelDroppedAttributesCount = "otel.dropped_attributes_count"
+	otelDroppedEventsCount     = "otel.dropped_events_count"
+	otelDroppedLinksCount      = "otel.dropped_links_count"
 )
 
 var (
-	errZeroTraceID = errors.New("OC span has an all zeros trace ID")
-	errZeroSpanID  = errors.New("OC span has an all zeros span ID")
+	unknownProcess = &jaeger.Process{ServiceName: "unknown-service-name"}
+
+	errZeroTraceID = errors.New("span has an all zeros trace ID")
+	errZeroSpanID  = errors.New("span has an all zeros span ID")
 )
@@ -13,5 +13,5 @@
 // limitations under the License.
 
 // Package jaegerthrifthttpexporter implements an exporter that sends trace data
-// to a Jaeger collector Thrift over HTTP endpoint.
+// to a Jaeger collector Thrift over HTTP endpoint, or to a Jaeger agent over UDP.
 package jaegerthrifthttpexporter
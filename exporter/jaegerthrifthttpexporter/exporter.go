@@ -29,7 +29,6 @@ import (
 	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
-	"go.opentelemetry.io/collector/translator/internaldata"
 )
 
 // Default timeout for http request in seconds
@@ -77,14 +76,12 @@ func (s *jaegerThriftHTTPSender) pushTraceData(
 	_ context.Context,
 	td pdata.Traces,
 ) (droppedSpans int, err error) {
-	octds := internaldata.TraceDataToOC(td)
-	for _, octd := range octds {
-
-		tBatch, err := oCProtoToJaegerThrift(octd)
-		if err != nil {
-			return td.SpanCount(), consumererror.Permanent(err)
-		}
+	tBatches, err := tracesToJaegerThrift(td)
+	if err != nil {
+		return td.SpanCount(), consumererror.Permanent(err)
+	}
 
+	for _, tBatch := range tBatches {
 		body, err := serializeThrift(tBatch)
 		if err != nil {
 			return td.SpanCount(), err
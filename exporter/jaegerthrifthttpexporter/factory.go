@@ -43,17 +43,33 @@ func createDefaultConfig() configmodels.Exporter {
 			TypeVal: configmodels.Type(typeStr),
 			NameVal: typeStr,
 		},
-		Timeout: defaultHTTPTimeout,
+		Transport:     transportHTTP,
+		Timeout:       defaultHTTPTimeout,
+		AgentEndpoint: defaultAgentEndpoint,
+		AgentProtocol: agentProtocolCompact,
+		MaxPacketSize: defaultMaxPacketSize,
 	}
 }
 
 func createTraceExporter(
 	_ context.Context,
-	_ component.ExporterCreateParams,
+	params component.ExporterCreateParams,
 	config configmodels.Exporter,
 ) (component.TraceExporter, error) {
 
 	expCfg := config.(*Config)
+	switch expCfg.Transport {
+	case "", transportHTTP:
+	case transportAgent:
+		return createAgentTraceExporter(params, expCfg)
+	default:
+		return nil, fmt.Errorf(
+			"%q config requires \"transport\" to be %q or %q",
+			expCfg.Name(),
+			transportHTTP,
+			transportAgent)
+	}
+
 	_, err := url.ParseRequestURI(expCfg.URL)
 	if err != nil {
 		// TODO: Improve error message, see #215
@@ -73,3 +89,30 @@ func createTraceExporter(
 
 	return newTraceExporter(config, expCfg.URL, expCfg.Headers, expCfg.Timeout)
 }
+
+func createAgentTraceExporter(
+	params component.ExporterCreateParams,
+	expCfg *Config,
+) (component.TraceExporter, error) {
+	if expCfg.AgentProtocol != agentProtocolCompact && expCfg.AgentProtocol != agentProtocolBinary {
+		return nil, fmt.Errorf(
+			"%q config requires \"agent_protocol\" to be %q or %q",
+			expCfg.Name(),
+			agentProtocolCompact,
+			agentProtocolBinary)
+	}
+
+	if expCfg.MaxPacketSize <= emitBatchOverhead {
+		return nil, fmt.Errorf(
+			"%q config requires a value greater than %d for \"max_packet_size\"",
+			expCfg.Name(),
+			emitBatchOverhead)
+	}
+
+	return newAgentTraceExporter(
+		expCfg,
+		params.Logger,
+		expCfg.AgentEndpoint,
+		expCfg.AgentProtocol,
+		expCfg.MaxPacketSize)
+}
@@ -112,6 +112,45 @@ func TestFactory_CreateTraceExporterFails(t *testing.T) {
 			},
 			errorMessage: "\"jaeger_thrift\" config requires a positive value for \"timeout\"",
 		},
+		{
+			name: "unknown_transport",
+			config: &Config{
+				ExporterSettings: configmodels.ExporterSettings{
+					TypeVal: configmodels.Type(typeStr),
+					NameVal: typeStr,
+				},
+				Transport: "grpc",
+			},
+			errorMessage: "\"jaeger_thrift\" config requires \"transport\" to be \"http\" or \"agent\"",
+		},
+		{
+			name: "unknown_agent_protocol",
+			config: &Config{
+				ExporterSettings: configmodels.ExporterSettings{
+					TypeVal: configmodels.Type(typeStr),
+					NameVal: typeStr,
+				},
+				Transport:     "agent",
+				AgentEndpoint: "localhost:6831",
+				AgentProtocol: "json",
+				MaxPacketSize: 65000,
+			},
+			errorMessage: "\"jaeger_thrift\" config requires \"agent_protocol\" to be \"compact\" or \"binary\"",
+		},
+		{
+			name: "small_max_packet_size",
+			config: &Config{
+				ExporterSettings: configmodels.ExporterSettings{
+					TypeVal: configmodels.Type(typeStr),
+					NameVal: typeStr,
+				},
+				Transport:     "agent",
+				AgentEndpoint: "localhost:6831",
+				AgentProtocol: "compact",
+				MaxPacketSize: 10,
+			},
+			errorMessage: "\"jaeger_thrift\" config requires a value greater than 70 for \"max_packet_size\"",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
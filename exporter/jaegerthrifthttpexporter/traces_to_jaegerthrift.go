@@ -0,0 +1,317 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jaegerthrifthttpexporter
+
+import (
+	"fmt"
+
+	"github.com/jaegertracing/jaeger/thrift-gen/jaeger"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+// tracesToJaegerThrift translates trace data into Jaeger Thrift batches, one
+// for each resource.
+func tracesToJaegerThrift(td pdata.Traces) ([]*jaeger.Batch, error) {
+	resourceSpans := td.ResourceSpans()
+	batches := make([]*jaeger.Batch, 0, resourceSpans.Len())
+
+	for i := 0; i < resourceSpans.Len(); i++ {
+		rs := resourceSpans.At(i)
+		if rs.IsNil() {
+			continue
+		}
+
+		batch, err := resourceSpansToJaegerThrift(rs)
+		if err != nil {
+			return nil, err
+		}
+		batches = append(batches, batch)
+	}
+
+	return batches, nil
+}
+
+func resourceSpansToJaegerThrift(rs pdata.ResourceSpans) (*jaeger.Batch, error) {
+	batch := &jaeger.Batch{
+		Process: resourceToJaegerThriftProcess(rs.Resource()),
+	}
+
+	ilss := rs.InstrumentationLibrarySpans()
+	for i := 0; i < ilss.Len(); i++ {
+		ils := ilss.At(i)
+		if ils.IsNil() {
+			continue
+		}
+
+		spans := ils.Spans()
+		for j := 0; j < spans.Len(); j++ {
+			span := spans.At(j)
+			if span.IsNil() {
+				continue
+			}
+
+			jSpan, err := spanToJaegerThrift(span, ils.InstrumentationLibrary())
+			if err != nil {
+				return nil, err
+			}
+			batch.Spans = append(batch.Spans, jSpan)
+		}
+	}
+
+	return batch, nil
+}
+
+func resourceToJaegerThriftProcess(resource pdata.Resource) *jaeger.Process {
+	if resource.IsNil() || resource.Attributes().Len() == 0 {
+		// Jaeger requires a non-nil Process
+		return unknownProcess
+	}
+
+	attrs := resource.Attributes()
+	process := &jaeger.Process{
+		ServiceName: unknownProcess.ServiceName,
+	}
+	if serviceName, ok := attrs.Get(conventions.AttributeServiceName); ok {
+		process.ServiceName = serviceName.StringVal()
+	}
+
+	attrs.ForEach(func(key string, attr pdata.AttributeValue) {
+		if key == conventions.AttributeServiceName {
+			return
+		}
+		process.Tags = append(process.Tags, attributeToJaegerThriftTag(key, attr))
+	})
+
+	return process
+}
+
+func spanToJaegerThrift(span pdata.Span, library pdata.InstrumentationLibrary) (*jaeger.Span, error) {
+	traceIDHigh, traceIDLow, err := tracetranslator.BytesToInt64TraceID(span.TraceID().Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("span has invalid trace ID: %w", err)
+	}
+	if traceIDLow == 0 && traceIDHigh == 0 {
+		return nil, errZeroTraceID
+	}
+
+	spanID, err := tracetranslator.BytesToInt64SpanID(span.SpanID().Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("span has invalid span ID: %w", err)
+	}
+	if spanID == 0 {
+		return nil, errZeroSpanID
+	}
+
+	// The parent span ID is empty for root spans: only attempt conversion if set.
+	var parentSpanID int64
+	if len(span.ParentSpanID().Bytes()) != 0 {
+		parentSpanID, err = tracetranslator.BytesToInt64SpanID(span.ParentSpanID().Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("span has invalid parent span ID: %w", err)
+		}
+	}
+
+	jReferences, err := spanLinksToJaegerThriftReferences(span.Links())
+	if err != nil {
+		return nil, fmt.Errorf("error converting span links to Jaeger references: %w", err)
+	}
+
+	startTime := unixNanoToEpochMicroseconds(span.StartTime())
+	return &jaeger.Span{
+		TraceIdLow:    traceIDLow,
+		TraceIdHigh:   traceIDHigh,
+		SpanId:        spanID,
+		ParentSpanId:  parentSpanID,
+		OperationName: span.Name(),
+		References:    jReferences,
+		StartTime:     startTime,
+		Duration:      unixNanoToEpochMicroseconds(span.EndTime()) - startTime,
+		Tags:          spanToJaegerThriftTags(span, library),
+		Logs:          spanEventsToJaegerThriftLogs(span.Events()),
+	}, nil
+}
+
+// spanToJaegerThriftTags builds the tags of a span from its attributes, the
+// instrumentation library it was recorded with and the fields that don't have
+// a counterpart in Jaeger. Tags derived from fields of the span are only added
+// if not already set as attributes.
+func spanToJaegerThriftTags(span pdata.Span, library pdata.InstrumentationLibrary) []*jaeger.Tag {
+	attrs := span.Attributes()
+	jTags := appendJaegerThriftTagsFromAttributes(nil, attrs)
+
+	appendStringTag := func(key, value string) {
+		if _, ok := attrs.Get(key); !ok && value != "" {
+			jTags = append(jTags, &jaeger.Tag{Key: key, VType: jaeger.TagType_STRING, VStr: &value})
+		}
+	}
+	appendCountTag := func(key string, count uint32) {
+		if _, ok := attrs.Get(key); !ok && count != 0 {
+			value := int64(count)
+			jTags = append(jTags, &jaeger.Tag{Key: key, VType: jaeger.TagType_LONG, VLong: &value})
+		}
+	}
+
+	if !library.IsNil() {
+		appendStringTag(otelLibraryName, library.Name())
+		appendStringTag(otelLibraryVersion, library.Version())
+	}
+
+	appendStringTag(tracetranslator.TagSpanKind, spanKindToOpenTracing(span.Kind()))
+
+	// Only add status tags if neither status.code and status.message are set in the span attributes.
+	status := span.Status()
+	_, codeFound := attrs.Get(tracetranslator.TagStatusCode)
+	_, msgFound := attrs.Get(tracetranslator.TagStatusMsg)
+	if !status.IsNil() && !codeFound && !msgFound {
+		code := int64(status.Code())
+		jTags = append(jTags, &jaeger.Tag{Key: tracetranslator.TagStatusCode, VType: jaeger.TagType_LONG, VLong: &code})
+		appendStringTag(tracetranslator.TagStatusMsg, status.Message())
+		if status.Code() != pdata.StatusCodeOk {
+			isError := true
+			if _, ok := attrs.Get(tracetranslator.TagError); !ok {
+				jTags = append(jTags, &jaeger.Tag{Key: tracetranslator.TagError, VType: jaeger.TagType_BOOL, VBool: &isError})
+			}
+		}
+	}
+
+	appendStringTag(tracetranslator.TagW3CTraceState, string(span.TraceState()))
+	appendCountTag(otelDroppedAttributesCount, span.DroppedAttributesCount())
+	appendCountTag(otelDroppedEventsCount, span.DroppedEventsCount())
+	appendCountTag(otelDroppedLinksCount, span.DroppedLinksCount())
+
+	return jTags
+}
+
+// spanLinksToJaegerThriftReferences converts span links to FOLLOWS_FROM
+// references, the parent span is referenced by the ParentSpanId of the span.
+// Jaeger references have no attributes, link attributes are dropped.
+func spanLinksToJaegerThriftReferences(links pdata.SpanLinkSlice) ([]*jaeger.SpanRef, error) {
+	if links.Len() == 0 {
+		return nil, nil
+	}
+
+	jRefs := make([]*jaeger.SpanRef, 0, links.Len())
+	for i := 0; i < links.Len(); i++ {
+		link := links.At(i)
+		if link.IsNil() {
+			continue
+		}
+
+		traceIDHigh, traceIDLow, err := tracetranslator.BytesToInt64TraceID(link.TraceID().Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("link has invalid trace ID: %w", err)
+		}
+
+		spanID, err := tracetranslator.BytesToInt64SpanID(link.SpanID().Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("link has invalid span ID: %w", err)
+		}
+
+		jRefs = append(jRefs, &jaeger.SpanRef{
+			TraceIdLow:  traceIDLow,
+			TraceIdHigh: traceIDHigh,
+			SpanId:      spanID,
+			RefType:     jaeger.SpanRefType_FOLLOWS_FROM,
+		})
+	}
+
+	return jRefs, nil
+}
+
+func spanEventsToJaegerThriftLogs(events pdata.SpanEventSlice) []*jaeger.Log {
+	if events.Len() == 0 {
+		return nil
+	}
+
+	jLogs := make([]*jaeger.Log, 0, events.Len())
+	for i := 0; i < events.Len(); i++ {
+		event := events.At(i)
+		if event.IsNil() {
+			continue
+		}
+
+		var jFields []*jaeger.Tag
+		if name := event.Name(); name != "" {
+			jFields = append(jFields, &jaeger.Tag{Key: tracetranslator.TagMessage, VType: jaeger.TagType_STRING, VStr: &name})
+		}
+		jFields = appendJaegerThriftTagsFromAttributes(jFields, event.Attributes())
+		if dropped := int64(event.DroppedAttributesCount()); dropped != 0 {
+			jFields = append(jFields, &jaeger.Tag{Key: otelDroppedAttributesCount, VType: jaeger.TagType_LONG, VLong: &dropped})
+		}
+
+		jLogs = append(jLogs, &jaeger.Log{
+			Timestamp: unixNanoToEpochMicroseconds(event.Timestamp()),
+			Fields:    jFields,
+		})
+	}
+
+	return jLogs
+}
+
+func appendJaegerThriftTagsFromAttributes(jTags []*jaeger.Tag, attrs pdata.AttributeMap) []*jaeger.Tag {
+	attrs.ForEach(func(key string, attr pdata.AttributeValue) {
+		jTags = append(jTags, attributeToJaegerThriftTag(key, attr))
+	})
+	return jTags
+}
+
+func attributeToJaegerThriftTag(key string, attr pdata.AttributeValue) *jaeger.Tag {
+	jTag := &jaeger.Tag{Key: key}
+	switch attr.Type() {
+	case pdata.AttributeValueINT:
+		i := attr.IntVal()
+		jTag.VLong = &i
+		jTag.VType = jaeger.TagType_LONG
+	case pdata.AttributeValueBOOL:
+		b := attr.BoolVal()
+		jTag.VBool = &b
+		jTag.VType = jaeger.TagType_BOOL
+	case pdata.AttributeValueDOUBLE:
+		d := attr.DoubleVal()
+		jTag.VDouble = &d
+		jTag.VType = jaeger.TagType_DOUBLE
+	default:
+		// Jaeger-to-internal maps binary tags to string attributes and encodes them as
+		// base64 strings. Blindingly attempting to decode base64 seems too much.
+		// Maps and arrays are encoded as JSON.
+		str := tracetranslator.AttributeValueToString(attr, false)
+		jTag.VStr = &str
+		jTag.VType = jaeger.TagType_STRING
+	}
+	return jTag
+}
+
+// spanKindToOpenTracing follows OpenTracing conventions to set the span kind value as a tag.
+func spanKindToOpenTracing(kind pdata.SpanKind) string {
+	switch kind {
+	case pdata.SpanKindCLIENT:
+		return string(tracetranslator.OpenTracingSpanKindClient)
+	case pdata.SpanKindSERVER:
+		return string(tracetranslator.OpenTracingSpanKindServer)
+	case pdata.SpanKindPRODUCER:
+		return string(tracetranslator.OpenTracingSpanKindProducer)
+	case pdata.SpanKindCONSUMER:
+		return string(tracetranslator.OpenTracingSpanKindConsumer)
+	case pdata.SpanKindINTERNAL:
+		return string(tracetranslator.OpenTracingSpanKindInternal)
+	}
+	return ""
+}
+
+func unixNanoToEpochMicroseconds(ts pdata.TimestampUnixNano) int64 {
+	return int64(ts) / 1e3
+}
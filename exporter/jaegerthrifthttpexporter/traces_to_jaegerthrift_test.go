@@ -0,0 +1,272 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jaegerthrifthttpexporter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jaegertracing/jaeger/thrift-gen/jaeger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+var (
+	testTraceID = pdata.NewTraceID([]byte{0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 2})
+	testSpanID  = pdata.NewSpanID([]byte{0, 0, 0, 0, 0, 0, 0, 3})
+)
+
+func strPtr(s string) *string { return &s }
+func int64Ptr(i int64) *int64 { return &i }
+func boolPtr(b bool) *bool    { return &b }
+
+func newTestTraces() (pdata.Traces, pdata.Span) {
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(1)
+	rs := td.ResourceSpans().At(0)
+	rs.Resource().InitEmpty()
+	rs.InstrumentationLibrarySpans().Resize(1)
+	ils := rs.InstrumentationLibrarySpans().At(0)
+	ils.Spans().Resize(1)
+	span := ils.Spans().At(0)
+	span.SetTraceID(testTraceID)
+	span.SetSpanID(testSpanID)
+	return td, span
+}
+
+func TestTracesToJaegerThrift(t *testing.T) {
+	td, span := newTestTraces()
+
+	rs := td.ResourceSpans().At(0)
+	rs.Resource().Attributes().InsertString(conventions.AttributeServiceName, "checkout")
+	rs.Resource().Attributes().InsertString(conventions.AttributeHostName, "host-1")
+
+	library := rs.InstrumentationLibrarySpans().At(0).InstrumentationLibrary()
+	library.InitEmpty()
+	library.SetName("io.opentelemetry.http")
+	library.SetVersion("1.2.3")
+
+	span.SetParentSpanID(pdata.NewSpanID([]byte{0, 0, 0, 0, 0, 0, 0, 4}))
+	span.SetName("GET /cart")
+	span.SetKind(pdata.SpanKindSERVER)
+	span.SetStartTime(pdata.TimestampUnixNano(1000000000))
+	span.SetEndTime(pdata.TimestampUnixNano(1002000000))
+	span.SetTraceState("vendor=value")
+	span.SetDroppedAttributesCount(1)
+	span.SetDroppedEventsCount(2)
+	span.SetDroppedLinksCount(3)
+	span.Attributes().InsertInt("http.status_code", 503)
+	span.Attributes().InsertBool("retry", true)
+	span.Attributes().InsertDouble("ratio", 0.5)
+	span.Status().InitEmpty()
+	span.Status().SetCode(pdata.StatusCode(14))
+	span.Status().SetMessage("unavailable")
+
+	event := pdata.NewSpanEvent()
+	event.InitEmpty()
+	event.SetName("retry")
+	event.SetTimestamp(pdata.TimestampUnixNano(1001000000))
+	event.SetDroppedAttributesCount(4)
+	values := pdata.NewAttributeValueArray()
+	values.ArrayVal().Append(pdata.NewAttributeValueString("a"))
+	values.ArrayVal().Append(pdata.NewAttributeValueString("b"))
+	event.Attributes().Insert("values", values)
+	span.Events().Append(event)
+
+	link := pdata.NewSpanLink()
+	link.InitEmpty()
+	link.SetTraceID(testTraceID)
+	link.SetSpanID(pdata.NewSpanID([]byte{0, 0, 0, 0, 0, 0, 0, 5}))
+	span.Links().Append(link)
+
+	got, err := tracesToJaegerThrift(td)
+	require.NoError(t, err)
+
+	want := []*jaeger.Batch{
+		{
+			Process: &jaeger.Process{
+				ServiceName: "checkout",
+				Tags: []*jaeger.Tag{
+					{Key: conventions.AttributeHostName, VType: jaeger.TagType_STRING, VStr: strPtr("host-1")},
+				},
+			},
+			Spans: []*jaeger.Span{
+				{
+					TraceIdHigh:   1,
+					TraceIdLow:    2,
+					SpanId:        3,
+					ParentSpanId:  4,
+					OperationName: "GET /cart",
+					References: []*jaeger.SpanRef{
+						{TraceIdHigh: 1, TraceIdLow: 2, SpanId: 5, RefType: jaeger.SpanRefType_FOLLOWS_FROM},
+					},
+					StartTime: 1000000,
+					Duration:  2000,
+					Tags: []*jaeger.Tag{
+						{Key: "http.status_code", VType: jaeger.TagType_LONG, VLong: int64Ptr(503)},
+						{Key: "retry", VType: jaeger.TagType_BOOL, VBool: boolPtr(true)},
+						{Key: "ratio", VType: jaeger.TagType_DOUBLE, VDouble: func() *float64 { v := 0.5; return &v }()},
+						{Key: otelLibraryName, VType: jaeger.TagType_STRING, VStr: strPtr("io.opentelemetry.http")},
+						{Key: otelLibraryVersion, VType: jaeger.TagType_STRING, VStr: strPtr("1.2.3")},
+						{Key: tracetranslator.TagSpanKind, VType: jaeger.TagType_STRING, VStr: strPtr("server")},
+						{Key: tracetranslator.TagStatusCode, VType: jaeger.TagType_LONG, VLong: int64Ptr(14)},
+						{Key: tracetranslator.TagStatusMsg, VType: jaeger.TagType_STRING, VStr: strPtr("unavailable")},
+						{Key: tracetranslator.TagError, VType: jaeger.TagType_BOOL, VBool: boolPtr(true)},
+						{Key: tracetranslator.TagW3CTraceState, VType: jaeger.TagType_STRING, VStr: strPtr("vendor=value")},
+						{Key: otelDroppedAttributesCount, VType: jaeger.TagType_LONG, VLong: int64Ptr(1)},
+						{Key: otelDroppedEventsCount, VType: jaeger.TagType_LONG, VLong: int64Ptr(2)},
+						{Key: otelDroppedLinksCount, VType: jaeger.TagType_LONG, VLong: int64Ptr(3)},
+					},
+					Logs: []*jaeger.Log{
+						{
+							Timestamp: 1001000,
+							Fields: []*jaeger.Tag{
+								{Key: tracetranslator.TagMessage, VType: jaeger.TagType_STRING, VStr: strPtr("retry")},
+								{Key: "values", VType: jaeger.TagType_STRING, VStr: strPtr(`["a","b"]`)},
+								{Key: otelDroppedAttributesCount, VType: jaeger.TagType_LONG, VLong: int64Ptr(4)},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("tracesToJaegerThrift() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSpanToJaegerThriftStatusTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		attrs    map[string]pdata.AttributeValue
+		code     pdata.StatusCode
+		message  string
+		wantTags []*jaeger.Tag
+	}{
+		{
+			name: "ok status",
+			wantTags: []*jaeger.Tag{
+				{Key: tracetranslator.TagStatusCode, VType: jaeger.TagType_LONG, VLong: int64Ptr(0)},
+			},
+		},
+		{
+			name:    "error status with message",
+			code:    pdata.StatusCode(12),
+			message: "Forbidden",
+			wantTags: []*jaeger.Tag{
+				{Key: tracetranslator.TagStatusCode, VType: jaeger.TagType_LONG, VLong: int64Ptr(12)},
+				{Key: tracetranslator.TagStatusMsg, VType: jaeger.TagType_STRING, VStr: strPtr("Forbidden")},
+				{Key: tracetranslator.TagError, VType: jaeger.TagType_BOOL, VBool: boolPtr(true)},
+			},
+		},
+		{
+			name:    "status set as attribute",
+			attrs:   map[string]pdata.AttributeValue{tracetranslator.TagStatusCode: pdata.NewAttributeValueInt(13)},
+			code:    pdata.StatusCode(12),
+			message: "Forbidden",
+			wantTags: []*jaeger.Tag{
+				{Key: tracetranslator.TagStatusCode, VType: jaeger.TagType_LONG, VLong: int64Ptr(13)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, span := newTestTraces()
+			span.Attributes().InitFromMap(tt.attrs)
+			span.Status().InitEmpty()
+			span.Status().SetCode(tt.code)
+			span.Status().SetMessage(tt.message)
+
+			got := spanToJaegerThriftTags(span, pdata.NewInstrumentationLibrary())
+			assert.Equal(t, tt.wantTags, got)
+		})
+	}
+}
+
+func TestResourceToJaegerThriftProcess(t *testing.T) {
+	resource := pdata.NewResource()
+	assert.Equal(t, unknownProcess, resourceToJaegerThriftProcess(resource))
+
+	resource.InitEmpty()
+	assert.Equal(t, unknownProcess, resourceToJaegerThriftProcess(resource))
+
+	resource.Attributes().InsertInt("pid", 42)
+	assert.Equal(t, &jaeger.Process{
+		ServiceName: unknownProcess.ServiceName,
+		Tags: []*jaeger.Tag{
+			{Key: "pid", VType: jaeger.TagType_LONG, VLong: int64Ptr(42)},
+		},
+	}, resourceToJaegerThriftProcess(resource))
+}
+
+func TestTracesToJaegerThriftInvalidIDs(t *testing.T) {
+	tests := []struct {
+		name         string
+		traceID      pdata.TraceID
+		spanID       pdata.SpanID
+		wantErr      error // nil means that we check for the wrapped error
+		wrappedError error
+	}{
+		{
+			name:         "empty trace ID",
+			traceID:      pdata.NewTraceID(nil),
+			spanID:       testSpanID,
+			wrappedError: tracetranslator.ErrNilTraceID,
+		},
+		{
+			name:    "zero trace ID",
+			traceID: pdata.NewTraceID(make([]byte, 16)),
+			spanID:  testSpanID,
+			wantErr: errZeroTraceID,
+		},
+		{
+			name:         "empty span ID",
+			traceID:      testTraceID,
+			spanID:       pdata.NewSpanID(nil),
+			wrappedError: tracetranslator.ErrNilSpanID,
+		},
+		{
+			name:    "zero span ID",
+			traceID: testTraceID,
+			spanID:  pdata.NewSpanID(make([]byte, 8)),
+			wantErr: errZeroSpanID,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			td, span := newTestTraces()
+			span.SetTraceID(tt.traceID)
+			span.SetSpanID(tt.spanID)
+
+			_, err := tracesToJaegerThrift(td)
+			require.Error(t, err)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+			} else {
+				assert.True(t, errors.Is(err, tt.wrappedError), "%v does not wrap %v", err, tt.wrappedError)
+			}
+		})
+	}
+}
@@ -31,7 +31,8 @@ func NewFactory() component.ExporterFactory {
 		typeStr,
 		createDefaultConfig,
 		exporterhelper.WithTraces(createTraceExporter),
-		exporterhelper.WithMetrics(createMetricsExporter))
+		exporterhelper.WithMetrics(createMetricsExporter),
+		exporterhelper.WithLogs(createLogsExporter))
 }
 
 func createDefaultConfig() configmodels.Exporter {
@@ -71,3 +72,17 @@ func createMetricsExporter(
 
 	return exporterhelper.NewMetricsExporter(cfg, exp.pushMetricData, exporterhelper.WithShutdown(exp.Shutdown))
 }
+
+// CreateLogsExporter creates a New Relic logs exporter for this configuration.
+func createLogsExporter(
+	_ context.Context,
+	params component.ExporterCreateParams,
+	cfg configmodels.Exporter,
+) (component.LogsExporter, error) {
+	exp, err := newLogsExporter(params.Logger, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return exporterhelper.NewLogsExporter(cfg, exp.pushLogData, exporterhelper.WithShutdown(exp.Shutdown))
+}
@@ -49,4 +49,17 @@ func TestCreateExporter(t *testing.T) {
 	me, err := createMetricsExporter(context.Background(), params, nrConfig)
 	assert.Nil(t, err)
 	assert.NotNil(t, me, "failed to create metrics exporter")
+
+	le, err := createLogsExporter(context.Background(), params, nrConfig)
+	assert.Nil(t, err)
+	assert.NotNil(t, le, "failed to create logs exporter")
+}
+
+func TestCreateLogsExporterNoAPIKey(t *testing.T) {
+	cfg := createDefaultConfig()
+	params := component.ExporterCreateParams{Logger: zap.NewNop()}
+
+	le, err := createLogsExporter(context.Background(), params, cfg)
+	assert.Equal(t, errAPIKeyUnset, err)
+	assert.Nil(t, le)
 }
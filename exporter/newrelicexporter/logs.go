@@ -0,0 +1,258 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package newrelicexporter
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultLogsURL = "https://log-api.newrelic.com/log/v1"
+
+	logLevelKey          = "log.level"
+	logSeverityNumberKey = "log.severity_number"
+	logNameKey           = "name"
+	traceIDKey           = "trace.id"
+	spanIDKey            = "span.id"
+)
+
+var errAPIKeyUnset = errors.New("APIKey is required")
+
+// logsExporter exports OpenTelemetry Collector logs to the New Relic Log API.
+type logsExporter struct {
+	logger           *zap.Logger
+	client           *http.Client
+	url              string
+	apiKey           string
+	commonAttributes map[string]interface{}
+}
+
+// logsCommon is the common block of a New Relic Log API payload.
+type logsCommon struct {
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// logEntry is a single log in a New Relic Log API payload.
+type logEntry struct {
+	// Timestamp is in milliseconds since the epoch. New Relic uses the time
+	// of receipt when it is omitted.
+	Timestamp  int64                  `json:"timestamp,omitempty"`
+	Message    string                 `json:"message,omitempty"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// logsBatch is a group of logs sharing a common block.
+type logsBatch struct {
+	Common logsCommon `json:"common"`
+	Logs   []logEntry `json:"logs"`
+}
+
+func newLogsExporter(l *zap.Logger, c configmodels.Exporter) (*logsExporter, error) {
+	nrConfig, ok := c.(*Config)
+	if !ok {
+		return nil, fmt.Errorf("invalid config: %#v", c)
+	}
+	if nrConfig.APIKey == "" {
+		return nil, errAPIKeyUnset
+	}
+
+	url := defaultLogsURL
+	if nrConfig.LogsURLOverride != "" {
+		url = nrConfig.LogsURLOverride
+	}
+
+	return &logsExporter{
+		logger:           l,
+		client:           &http.Client{Timeout: nrConfig.Timeout},
+		url:              url,
+		apiKey:           nrConfig.APIKey,
+		commonAttributes: nrConfig.CommonAttributes,
+	}, nil
+}
+
+func (e *logsExporter) pushLogData(ctx context.Context, ld pdata.Logs) (int, error) {
+	logs := logsToNewRelic(ld)
+	if len(logs) == 0 {
+		return 0, nil
+	}
+
+	batch := logsBatch{
+		Common: logsCommon{Attributes: e.commonAttributes},
+		Logs:   logs,
+	}
+	if err := e.send(ctx, []logsBatch{batch}); err != nil {
+		return ld.LogRecordCount(), err
+	}
+	return 0, nil
+}
+
+func (e *logsExporter) send(ctx context.Context, batches []logsBatch) error {
+	body, err := json.Marshal(batches)
+	if err != nil {
+		return fmt.Errorf("error marshaling logs: %v", err)
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err = gz.Write(body); err != nil {
+		return fmt.Errorf("error compressing logs: %v", err)
+	}
+	if err = gz.Close(); err != nil {
+		return fmt.Errorf("error compressing logs: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, &buf)
+	if err != nil {
+		return fmt.Errorf("error creating request: %v", err)
+	}
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("Content-Encoding", "gzip")
+	req.Header.Add("Api-Key", e.apiKey)
+	req.Header.Add("User-Agent", product+"/"+version)
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending logs: %v", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("error sending logs: unexpected status code %d", resp.StatusCode)
+	}
+	e.logger.Debug("logs sent to New Relic", zap.Int("status_code", resp.StatusCode))
+	return nil
+}
+
+func (e *logsExporter) Shutdown(context.Context) error {
+	e.client.CloseIdleConnections()
+	return nil
+}
+
+// logsToNewRelic converts all log records in ld into New Relic logs.
+func logsToNewRelic(ld pdata.Logs) []logEntry {
+	logs := make([]logEntry, 0, ld.LogRecordCount())
+
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		if rl.IsNil() {
+			continue
+		}
+
+		var resourceAttrs map[string]interface{}
+		if res := rl.Resource(); !res.IsNil() {
+			resourceAttrs = attributeMapToNewRelic(res.Attributes())
+		}
+
+		ills := rl.InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			ill := ills.At(j)
+			if ill.IsNil() {
+				continue
+			}
+			records := ill.Logs()
+			for k := 0; k < records.Len(); k++ {
+				lr := records.At(k)
+				if lr.IsNil() {
+					continue
+				}
+				logs = append(logs, logRecordToNewRelic(resourceAttrs, lr))
+			}
+		}
+	}
+	return logs
+}
+
+// logRecordToNewRelic converts lr into a New Relic log. The record attributes
+// are merged over the resource attributes, the same way metric labels are
+// merged in MergeAttributes.
+func logRecordToNewRelic(resourceAttrs map[string]interface{}, lr pdata.LogRecord) logEntry {
+	attrs := make(map[string]interface{}, len(resourceAttrs)+lr.Attributes().Len()+7)
+
+	for k, v := range resourceAttrs {
+		attrs[k] = v
+	}
+	if lr.Name() != "" {
+		attrs[logNameKey] = lr.Name()
+	}
+	if lr.SeverityText() != "" {
+		attrs[logLevelKey] = lr.SeverityText()
+	}
+	if lr.SeverityNumber() != pdata.SeverityNumberUNDEFINED {
+		attrs[logSeverityNumberKey] = int32(lr.SeverityNumber())
+	}
+	if traceID := lr.TraceID().Bytes(); len(traceID) > 0 {
+		attrs[traceIDKey] = lr.TraceID().HexString()
+	}
+	if spanID := lr.SpanID(); len(spanID) > 0 {
+		attrs[spanIDKey] = spanID.String()
+	}
+	lr.Attributes().ForEach(func(k string, v pdata.AttributeValue) {
+		attrs[k] = attributeValueToNewRelic(v)
+	})
+
+	// (overrides any existing)
+	attrs[collectorNameKey] = name
+	attrs[collectorVersionKey] = version
+
+	entry := logEntry{
+		Timestamp:  int64(lr.Timestamp()) / 1e6,
+		Attributes: attrs,
+	}
+	if body := lr.Body(); !body.IsNil() {
+		entry.Message = tracetranslator.AttributeValueToString(body, false)
+	}
+	return entry
+}
+
+func attributeMapToNewRelic(attrMap pdata.AttributeMap) map[string]interface{} {
+	attrs := make(map[string]interface{}, attrMap.Len())
+	attrMap.ForEach(func(k string, v pdata.AttributeValue) {
+		attrs[k] = attributeValueToNewRelic(v)
+	})
+	return attrs
+}
+
+// attributeValueToNewRelic converts v into a value accepted by New Relic.
+// Maps and arrays are not supported as attribute values and are sent as
+// their JSON representation.
+func attributeValueToNewRelic(v pdata.AttributeValue) interface{} {
+	switch v.Type() {
+	case pdata.AttributeValueSTRING:
+		return v.StringVal()
+	case pdata.AttributeValueINT:
+		return v.IntVal()
+	case pdata.AttributeValueDOUBLE:
+		return v.DoubleVal()
+	case pdata.AttributeValueBOOL:
+		return v.BoolVal()
+	default:
+		return tracetranslator.AttributeValueToString(v, false)
+	}
+}
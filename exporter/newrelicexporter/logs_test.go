@@ -0,0 +1,173 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package newrelicexporter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+func newTestLogs() pdata.Logs {
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(1)
+	rl := ld.ResourceLogs().At(0)
+	rl.Resource().InitEmpty()
+	rl.Resource().Attributes().InsertString("service.name", "test-service")
+	rl.Resource().Attributes().InsertString("host.name", "resource-host")
+	rl.InstrumentationLibraryLogs().Resize(1)
+	logs := rl.InstrumentationLibraryLogs().At(0).Logs()
+	logs.Resize(1)
+	lr := logs.At(0)
+	lr.SetTimestamp(pdata.TimestampUnixNano(1600000000123456789))
+	lr.SetName("test-log")
+	lr.SetSeverityText("ERROR")
+	lr.SetSeverityNumber(pdata.SeverityNumberERROR)
+	lr.SetTraceID(pdata.NewTraceID([]byte{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1}))
+	lr.SetSpanID(pdata.NewSpanID([]byte{0, 0, 0, 0, 0, 0, 0, 1}))
+	lr.Body().SetStringVal("something happened")
+	lr.Attributes().InsertString("host.name", "record-host")
+	lr.Attributes().InsertInt("retries", 3)
+	lr.Attributes().InsertBool(collectorNameKey, true)
+	return ld
+}
+
+func TestExportLogData(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m := &Mock{make([]Data, 0, 1)}
+	srv := m.Server()
+	defer srv.Close()
+
+	f := NewFactory()
+	c := f.CreateDefaultConfig().(*Config)
+	c.APIKey, c.LogsURLOverride = "1", srv.URL
+	c.CommonAttributes = map[string]interface{}{"environment": "test"}
+	params := component.ExporterCreateParams{Logger: zap.NewNop()}
+	exp, err := f.CreateLogsExporter(context.Background(), params, c)
+	require.NoError(t, err)
+	require.NoError(t, exp.ConsumeLogs(ctx, newTestLogs()))
+	require.NoError(t, exp.Shutdown(ctx))
+
+	require.Len(t, m.Data, 1)
+	assert.Equal(t, map[string]string{"environment": "test"}, m.Data[0].Common.Attributes)
+	assert.Equal(t, []Log{
+		{
+			Timestamp: 1600000000123,
+			Message:   "something happened",
+			Attributes: map[string]interface{}{
+				"service.name":        "test-service",
+				"host.name":           "record-host",
+				"retries":             float64(3),
+				"name":                "test-log",
+				"log.level":           "ERROR",
+				"log.severity_number": float64(pdata.SeverityNumberERROR),
+				"trace.id":            "01010101010101010101010101010101",
+				"span.id":             "0000000000000001",
+				collectorNameKey:      name,
+				collectorVersionKey:   version,
+			},
+		},
+	}, m.Logs())
+}
+
+func TestExportLogDataEmpty(t *testing.T) {
+	called := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer srv.Close()
+
+	c := createDefaultConfig().(*Config)
+	c.APIKey, c.LogsURLOverride = "1", srv.URL
+	exp, err := newLogsExporter(zap.NewNop(), c)
+	require.NoError(t, err)
+
+	dropped, err := exp.pushLogData(context.Background(), pdata.NewLogs())
+	require.NoError(t, err)
+	assert.Equal(t, 0, dropped)
+	assert.False(t, called)
+}
+
+func TestExportLogDataErrorStatus(t *testing.T) {
+	var apiKey string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiKey = r.Header.Get("Api-Key")
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	c := createDefaultConfig().(*Config)
+	c.APIKey, c.LogsURLOverride = "a1b2c3d4", srv.URL
+	exp, err := newLogsExporter(zap.NewNop(), c)
+	require.NoError(t, err)
+
+	dropped, err := exp.pushLogData(context.Background(), newTestLogs())
+	assert.EqualError(t, err, "error sending logs: unexpected status code 403")
+	assert.Equal(t, 1, dropped)
+	assert.Equal(t, "a1b2c3d4", apiKey)
+}
+
+func TestLogRecordToNewRelicBody(t *testing.T) {
+	tests := []struct {
+		name string
+		set  func(pdata.AttributeValue)
+		want string
+	}{
+		{
+			name: "string",
+			set:  func(v pdata.AttributeValue) { v.SetStringVal("message") },
+			want: "message",
+		},
+		{
+			name: "int",
+			set:  func(v pdata.AttributeValue) { v.SetIntVal(42) },
+			want: "42",
+		},
+		{
+			name: "map",
+			set: func(v pdata.AttributeValue) {
+				m := pdata.NewAttributeMap()
+				m.InsertString("key", "value")
+				v.SetMapVal(m)
+			},
+			want: `{"key":"value"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			lr := pdata.NewLogRecord()
+			lr.InitEmpty()
+			tt.set(lr.Body())
+			entry := logRecordToNewRelic(nil, lr)
+			assert.Equal(t, tt.want, entry.Message)
+			assert.Equal(t, int64(0), entry.Timestamp)
+			assert.Equal(t, map[string]interface{}{
+				collectorNameKey:    name,
+				collectorVersionKey: version,
+			}, entry.Attributes)
+		})
+	}
+}
@@ -0,0 +1,249 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/getsentry/sentry-go"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// Span event and attribute names of exceptions, as defined by the OpenTelemetry semantic conventions.
+// See https://github.com/open-telemetry/opentelemetry-specification/blob/master/specification/trace/semantic_conventions/exceptions.md
+const (
+	exceptionEventName       = "exception"
+	exceptionTypeKey         = "exception.type"
+	exceptionMessageKey      = "exception.message"
+	exceptionStacktraceKey   = "exception.stacktrace"
+	exceptionAttributePrefix = "exception."
+)
+
+var (
+	// File "/app/main.py", line 10, in handler
+	pythonFrameRegex = regexp.MustCompile(`^\s*File "(.+)", line (\d+), in (.+)$`)
+	// /app/main.go:10 +0x1d
+	goFileRegex = regexp.MustCompile(`^\s+(.+\.go):(\d+)(?: \+0x[0-9a-f]+)?$`)
+	// at com.example.Handler.handle(Handler.java:10)
+	javaFrameRegex = regexp.MustCompile(`^\s*at ([^\s(]+)\.([^\s.(]+)\(([^:)]*)(?::(\d+))?\)$`)
+	// at handle (/app/handler.js:10:15) or at /app/handler.js:10:15
+	nodeFrameRegex = regexp.MustCompile(`^\s*at (?:(.+?) \()?(.+?):(\d+):(\d+)\)?$`)
+)
+
+// stacktraceParser parses the frames of a stacktrace in a language specific
+// format. Frames are returned from the outermost to the innermost call, as
+// Sentry expects them.
+type stacktraceParser struct {
+	platform string
+	parse    func(lines []string) []sentry.Frame
+}
+
+// stacktraceParsers are tried in order until one of them finds frames.
+var stacktraceParsers = []stacktraceParser{
+	{platform: "python", parse: parsePythonStacktrace},
+	{platform: "go", parse: parseGoStacktrace},
+	{platform: "java", parse: parseJavaStacktrace},
+	{platform: "node", parse: parseNodeStacktrace},
+}
+
+// isExceptionEvent determines if a span event records an exception.
+func isExceptionEvent(event pdata.SpanEvent) bool {
+	return !event.IsNil() && event.Name() == exceptionEventName
+}
+
+// errorFromExceptionEvent converts an exception span event into a Sentry error
+// event. The error is linked to the span the exception was recorded on and
+// carries its tags, along with the attributes of the event.
+func errorFromExceptionEvent(event pdata.SpanEvent, span *sentry.Span) *sentry.Event {
+	attrs := event.Attributes()
+
+	tags := make(map[string]string, len(span.Tags))
+	for k, v := range span.Tags {
+		tags[k] = v
+	}
+	for k, v := range generateTagsFromAttributes(attrs) {
+		if !strings.HasPrefix(k, exceptionAttributePrefix) {
+			tags[k] = v
+		}
+	}
+
+	exception := sentry.Exception{
+		Type:  stringAttribute(attrs, exceptionTypeKey),
+		Value: stringAttribute(attrs, exceptionMessageKey),
+	}
+
+	errorEvent := sentry.NewEvent()
+
+	if stacktrace := stringAttribute(attrs, exceptionStacktraceKey); stacktrace != "" {
+		platform, frames := parseStacktrace(stacktrace)
+		if len(frames) > 0 {
+			exception.Stacktrace = &sentry.Stacktrace{Frames: frames}
+			errorEvent.Platform = platform
+		}
+	}
+
+	errorEvent.Contexts["trace"] = sentry.TraceContext{
+		TraceID: span.TraceID,
+		SpanID:  span.SpanID,
+		Op:      span.Op,
+		Status:  span.Status,
+	}
+
+	errorEvent.Level = sentry.LevelError
+	errorEvent.Exception = []sentry.Exception{exception}
+
+	errorEvent.Sdk.Name = otelSentryExporterName
+	errorEvent.Sdk.Version = otelSentryExporterVersion
+
+	errorEvent.Tags = tags
+	errorEvent.Timestamp = unixNanoToTime(event.Timestamp())
+	errorEvent.Transaction = span.Description
+
+	return errorEvent
+}
+
+func stringAttribute(attrs pdata.AttributeMap, key string) string {
+	if v, ok := attrs.Get(key); ok && v.Type() == pdata.AttributeValueSTRING {
+		return v.StringVal()
+	}
+	return ""
+}
+
+// parseStacktrace detects the format of a stacktrace and parses its frames.
+func parseStacktrace(stacktrace string) (platform string, frames []sentry.Frame) {
+	lines := strings.Split(strings.ReplaceAll(stacktrace, "\r\n", "\n"), "\n")
+	for _, parser := range stacktraceParsers {
+		if frames := parser.parse(lines); len(frames) > 0 {
+			return parser.platform, frames
+		}
+	}
+	return "", nil
+}
+
+// parsePythonStacktrace parses tracebacks, which already list the most
+// recent call last.
+func parsePythonStacktrace(lines []string) []sentry.Frame {
+	var frames []sentry.Frame
+	for i, line := range lines {
+		m := pythonFrameRegex.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		lineno, _ := strconv.Atoi(m[2])
+		frame := sentry.Frame{
+			AbsPath:  m[1],
+			Filename: m[1],
+			Lineno:   lineno,
+			Function: m[3],
+		}
+		// The source line follows the frame, unless it is unavailable.
+		if i+1 < len(lines) && pythonFrameRegex.FindStringSubmatch(lines[i+1]) == nil &&
+			strings.HasPrefix(lines[i+1], "    ") {
+			frame.ContextLine = strings.TrimSpace(lines[i+1])
+		}
+		frames = append(frames, frame)
+	}
+	return frames
+}
+
+// parseGoStacktrace parses goroutine dumps, as produced by panics or
+// runtime/debug.Stack, where each function line is followed by its file.
+func parseGoStacktrace(lines []string) []sentry.Frame {
+	var frames []sentry.Frame
+	for i := 1; i < len(lines); i++ {
+		m := goFileRegex.FindStringSubmatch(lines[i])
+		if m == nil {
+			continue
+		}
+		function := strings.TrimSpace(lines[i-1])
+		if j := strings.LastIndex(function, "("); j > 0 {
+			function = function[:j]
+		}
+		module, function := splitGoFunction(function)
+		lineno, _ := strconv.Atoi(m[2])
+		frames = append(frames, sentry.Frame{
+			AbsPath:  m[1],
+			Filename: m[1],
+			Lineno:   lineno,
+			Module:   module,
+			Function: function,
+		})
+	}
+	return reverseFrames(frames)
+}
+
+// splitGoFunction splits a fully qualified function name, like
+// github.com/org/repo/pkg.(*T).Method, into its package and function.
+func splitGoFunction(name string) (pkg, function string) {
+	start := strings.LastIndex(name, "/") + 1
+	if i := strings.Index(name[start:], "."); i >= 0 {
+		return name[:start+i], name[start+i+1:]
+	}
+	return "", name
+}
+
+// parseJavaStacktrace parses the frames of the top level exception, frames
+// of its causes are ignored.
+func parseJavaStacktrace(lines []string) []sentry.Frame {
+	var frames []sentry.Frame
+	for _, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "Caused by:") {
+			break
+		}
+		m := javaFrameRegex.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		lineno, _ := strconv.Atoi(m[4])
+		frames = append(frames, sentry.Frame{
+			Module:   m[1],
+			Function: m[2],
+			Filename: m[3],
+			Lineno:   lineno,
+		})
+	}
+	return reverseFrames(frames)
+}
+
+// parseNodeStacktrace parses V8 stacktraces.
+func parseNodeStacktrace(lines []string) []sentry.Frame {
+	var frames []sentry.Frame
+	for _, line := range lines {
+		m := nodeFrameRegex.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		lineno, _ := strconv.Atoi(m[3])
+		colno, _ := strconv.Atoi(m[4])
+		frames = append(frames, sentry.Frame{
+			Function: m[1],
+			AbsPath:  m[2],
+			Filename: m[2],
+			Lineno:   lineno,
+			Colno:    colno,
+		})
+	}
+	return reverseFrames(frames)
+}
+
+// reverseFrames reverses frames listed from the innermost call.
+func reverseFrames(frames []sentry.Frame) []sentry.Frame {
+	for i, j := 0, len(frames)-1; i < j; i, j = i+1, j-1 {
+		frames[i], frames[j] = frames[j], frames[i]
+	}
+	return frames
+}
@@ -0,0 +1,212 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryexporter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+const (
+	javaStacktrace = `java.lang.IllegalStateException: connection closed
+	at com.example.db.Connection.query(Connection.java:42)
+	at com.example.Handler.handle(Handler.java:10)
+	at java.base/java.lang.Thread.run(Unknown Source)
+Caused by: java.io.IOException: broken pipe
+	at com.example.db.Socket.write(Socket.java:7)`
+
+	pythonStacktrace = `Traceback (most recent call last):
+  File "/app/main.py", line 10, in handler
+    query(db)
+  File "/app/db.py", line 42, in query
+    raise ValueError("connection closed")
+ValueError: connection closed`
+
+	goStacktrace = `goroutine 1 [running]:
+github.com/example/app/db.(*Conn).Query(0xc000010000)
+	/app/db/conn.go:42 +0x1d
+main.handler()
+	/app/main.go:10 +0x25`
+
+	nodeStacktrace = `Error: connection closed
+    at query (/app/db.js:42:11)
+    at /app/handler.js:10:5`
+)
+
+func TestParseStacktrace(t *testing.T) {
+	tests := []struct {
+		name       string
+		stacktrace string
+		platform   string
+		frames     []sentry.Frame
+	}{
+		{
+			name:       "java",
+			stacktrace: javaStacktrace,
+			platform:   "java",
+			frames: []sentry.Frame{
+				{Module: "java.base/java.lang.Thread", Function: "run", Filename: "Unknown Source"},
+				{Module: "com.example.Handler", Function: "handle", Filename: "Handler.java", Lineno: 10},
+				{Module: "com.example.db.Connection", Function: "query", Filename: "Connection.java", Lineno: 42},
+			},
+		},
+		{
+			name:       "python",
+			stacktrace: pythonStacktrace,
+			platform:   "python",
+			frames: []sentry.Frame{
+				{Function: "handler", Filename: "/app/main.py", AbsPath: "/app/main.py", Lineno: 10, ContextLine: "query(db)"},
+				{Function: "query", Filename: "/app/db.py", AbsPath: "/app/db.py", Lineno: 42, ContextLine: `raise ValueError("connection closed")`},
+			},
+		},
+		{
+			name:       "go",
+			stacktrace: goStacktrace,
+			platform:   "go",
+			frames: []sentry.Frame{
+				{Module: "main", Function: "handler", Filename: "/app/main.go", AbsPath: "/app/main.go", Lineno: 10},
+				{Module: "github.com/example/app/db", Function: "(*Conn).Query", Filename: "/app/db/conn.go", AbsPath: "/app/db/conn.go", Lineno: 42},
+			},
+		},
+		{
+			name:       "node",
+			stacktrace: nodeStacktrace,
+			platform:   "node",
+			frames: []sentry.Frame{
+				{Filename: "/app/handler.js", AbsPath: "/app/handler.js", Lineno: 10, Colno: 5},
+				{Function: "query", Filename: "/app/db.js", AbsPath: "/app/db.js", Lineno: 42, Colno: 11},
+			},
+		},
+		{
+			name:       "unknown format",
+			stacktrace: "something went wrong",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			platform, frames := parseStacktrace(tt.stacktrace)
+			assert.Equal(t, tt.platform, platform)
+			if diff := cmp.Diff(tt.frames, frames); diff != "" {
+				t.Errorf("frames mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func newExceptionEvent(attrs map[string]string) pdata.SpanEvent {
+	event := pdata.NewSpanEvent()
+	event.InitEmpty()
+	event.SetName(exceptionEventName)
+	event.SetTimestamp(pdata.TimestampUnixNano(1000000000))
+	for k, v := range attrs {
+		event.Attributes().InsertString(k, v)
+	}
+	return event
+}
+
+func TestErrorFromExceptionEvent(t *testing.T) {
+	event := newExceptionEvent(map[string]string{
+		exceptionTypeKey:       "ValueError",
+		exceptionMessageKey:    "connection closed",
+		exceptionStacktraceKey: pythonStacktrace,
+		"retry":                "false",
+	})
+
+	span := &sentry.Span{
+		TraceID:     "01020304050607080807060504030201",
+		SpanID:      "0102030405060708",
+		Op:          "db",
+		Description: "SELECT * FROM users",
+		Status:      "internal_error",
+		Tags:        map[string]string{"service.name": "app"},
+	}
+
+	errorEvent := errorFromExceptionEvent(event, span)
+
+	assert.Equal(t, sentry.LevelError, errorEvent.Level)
+	assert.Equal(t, "python", errorEvent.Platform)
+	assert.Equal(t, "SELECT * FROM users", errorEvent.Transaction)
+	assert.Equal(t, unixNanoToTime(1000000000), errorEvent.Timestamp)
+	assert.Equal(t, otelSentryExporterName, errorEvent.Sdk.Name)
+	assert.Equal(t, map[string]string{"service.name": "app", "retry": "false"}, errorEvent.Tags)
+	assert.Equal(t, sentry.TraceContext{
+		TraceID: span.TraceID,
+		SpanID:  span.SpanID,
+		Op:      span.Op,
+		Status:  span.Status,
+	}, errorEvent.Contexts["trace"])
+
+	require.Len(t, errorEvent.Exception, 1)
+	assert.Equal(t, "ValueError", errorEvent.Exception[0].Type)
+	assert.Equal(t, "connection closed", errorEvent.Exception[0].Value)
+	require.NotNil(t, errorEvent.Exception[0].Stacktrace)
+	assert.Len(t, errorEvent.Exception[0].Stacktrace.Frames, 2)
+
+	// The span tags must not be modified.
+	assert.Equal(t, map[string]string{"service.name": "app"}, span.Tags)
+}
+
+func TestPushTraceDataWithExceptions(t *testing.T) {
+	traces := pdata.NewTraces()
+	resourceSpans := traces.ResourceSpans()
+	resourceSpans.Resize(1)
+	resourceSpans.At(0).InstrumentationLibrarySpans().Resize(1)
+	spans := resourceSpans.At(0).InstrumentationLibrarySpans().At(0).Spans()
+	spans.Resize(2)
+
+	root := spans.At(0)
+	root.SetTraceID(pdata.NewTraceID([]byte{1, 2, 3, 4, 5, 6, 7, 8, 8, 7, 6, 5, 4, 3, 2, 1}))
+	root.SetSpanID([]byte{1, 2, 3, 4, 5, 6, 7, 8})
+	root.SetName("GET /users")
+
+	child := spans.At(1)
+	child.SetTraceID(pdata.NewTraceID([]byte{1, 2, 3, 4, 5, 6, 7, 8, 8, 7, 6, 5, 4, 3, 2, 1}))
+	child.SetSpanID([]byte{8, 7, 6, 5, 4, 3, 2, 1})
+	child.SetParentSpanID([]byte{1, 2, 3, 4, 5, 6, 7, 8})
+	child.SetName("query")
+	child.Events().Append(newExceptionEvent(map[string]string{
+		exceptionTypeKey:    "ValueError",
+		exceptionMessageKey: "connection closed",
+	}))
+	log := pdata.NewSpanEvent()
+	log.InitEmpty()
+	log.SetName("log")
+	child.Events().Append(log)
+
+	transport := &mockTransport{}
+	s := &SentryExporter{
+		transport: transport,
+	}
+
+	_, err := s.pushTraceData(context.Background(), traces)
+	require.NoError(t, err)
+	require.True(t, transport.called)
+	require.Len(t, transport.events, 2)
+
+	transaction, errorEvent := transport.events[0], transport.events[1]
+	assert.Equal(t, "transaction", transaction.Type)
+	assert.Equal(t, sentry.LevelError, errorEvent.Level)
+	assert.Equal(t, transaction.Transaction, errorEvent.Transaction)
+	assert.Equal(t, "0807060504030201", errorEvent.Contexts["trace"].(sentry.TraceContext).SpanID)
+	assert.Equal(t, transaction.Contexts["trace"].(sentry.TraceContext).TraceID, errorEvent.Contexts["trace"].(sentry.TraceContext).TraceID)
+}
@@ -61,8 +61,8 @@ type SentryExporter struct {
 	transport transport
 }
 
-// pushTraceData takes an incoming OpenTelemetry trace, converts them into Sentry spans and transactions
-// and sends them using Sentry's transport.
+// pushTraceData takes an incoming OpenTelemetry trace, converts them into Sentry spans, transactions
+// and errors and sends them using Sentry's transport.
 func (s *SentryExporter) pushTraceData(_ context.Context, td pdata.Traces) (droppedSpans int, err error) {
 	// For a ResourceSpan, InstrumentationLibrarySpan and Span struct if IsNil()
 	// is "true", all other methods will cause a runtime error.
@@ -77,6 +77,9 @@ func (s *SentryExporter) pushTraceData(_ context.Context, td pdata.Traces) (drop
 	idMap := make(map[string]string)
 	// Maps root span id to a transaction.
 	transactionMap := make(map[string]*sentry.Event)
+	// Errors created from exception span events, along with the span they were recorded on.
+	var errorEvents []*sentry.Event
+	errorSpans := make(map[*sentry.Event]*sentry.Span)
 
 	for i := 0; i < resourceSpans.Len(); i++ {
 		rs := resourceSpans.At(i)
@@ -104,6 +107,15 @@ func (s *SentryExporter) pushTraceData(_ context.Context, td pdata.Traces) (drop
 
 				sentrySpan := convertToSentrySpan(otelSpan, library, resourceTags)
 
+				events := otelSpan.Events()
+				for l := 0; l < events.Len(); l++ {
+					if event := events.At(l); isExceptionEvent(event) {
+						sentryError := errorFromExceptionEvent(event, sentrySpan)
+						errorEvents = append(errorEvents, sentryError)
+						errorSpans[sentryError] = sentrySpan
+					}
+				}
+
 				// If a span is a root span, we consider it the start of a Sentry transaction.
 				// We should then create a new transaction for that root span, and keep track of it.
 				//
@@ -124,7 +136,7 @@ func (s *SentryExporter) pushTraceData(_ context.Context, td pdata.Traces) (drop
 		}
 	}
 
-	if len(transactionMap) == 0 {
+	if len(transactionMap) == 0 && len(errorEvents) == 0 {
 		return 0, nil
 	}
 
@@ -134,7 +146,15 @@ func (s *SentryExporter) pushTraceData(_ context.Context, td pdata.Traces) (drop
 
 	transactions := generateTransactions(transactionMap, orphanSpans)
 
-	s.transport.SendTransactions(transactions)
+	// Errors are named after the transaction their span belongs to, so that Sentry
+	// can link them together. Errors of orphan spans keep the name of their own span.
+	for _, sentryError := range errorEvents {
+		if rootSpanID, ok := idMap[errorSpans[sentryError].SpanID]; ok {
+			sentryError.Transaction = transactionMap[rootSpanID].Transaction
+		}
+	}
+
+	s.transport.SendEvents(append(transactions, errorEvents...))
 
 	return 0, nil
 }
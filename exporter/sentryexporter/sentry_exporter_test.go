@@ -503,12 +503,12 @@ func TestGenerateTransactions(t *testing.T) {
 }
 
 type mockTransport struct {
-	called       bool
-	transactions []*sentry.Event
+	called bool
+	events []*sentry.Event
 }
 
-func (t *mockTransport) SendTransactions(transactions []*sentry.Event) {
-	t.transactions = transactions
+func (t *mockTransport) SendEvents(events []*sentry.Event) {
+	t.events = events
 	t.called = true
 }
 
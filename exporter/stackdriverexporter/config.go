@@ -29,6 +29,13 @@ type Config struct {
 	Endpoint                      string                   `mapstructure:"endpoint"`
 	NumOfWorkers                  int                      `mapstructure:"number_of_workers"`
 	SkipCreateMetricDescriptor    bool                     `mapstructure:"skip_create_metric_descriptor"`
+	// ProjectIDAttribute is the name of a resource attribute holding the
+	// destination project of that resource's data. Data of resources without
+	// it is sent to ProjectID.
+	ProjectIDAttribute string `mapstructure:"project_attribute"`
+	// MaxProjects is the maximum number of projects data is sent to when
+	// ProjectIDAttribute is set. Data of other projects is dropped.
+	MaxProjects int `mapstructure:"max_projects"`
 	// Only has effect if Endpoint is not ""
 	UseInsecure bool `mapstructure:"use_insecure"`
 	// Timeout for all API calls. If not set, defaults to 12 seconds.
@@ -55,6 +55,8 @@ func TestLoadConfig(t *testing.T) {
 			Endpoint:                   "test-endpoint",
 			NumOfWorkers:               3,
 			SkipCreateMetricDescriptor: true,
+			ProjectIDAttribute:         "gcp.project.id",
+			MaxProjects:                10,
 			UseInsecure:                true,
 			TimeoutSettings: exporterhelper.TimeoutSettings{
 				Timeout: 20 * time.Second,
@@ -29,6 +29,9 @@ const (
 	// The value of "type" key in configuration.
 	typeStr        = "stackdriver"
 	defaultTimeout = 12 * time.Second // Consistent with Cloud Monitoring's timeout
+	// defaultMaxProjects bounds the exporters created for the projects found
+	// in resource attributes, each of them has its own connections.
+	defaultMaxProjects = 100
 )
 
 var once sync.Once
@@ -45,6 +48,7 @@ func NewFactory() component.ExporterFactory {
 		createDefaultConfig,
 		exporterhelper.WithTraces(createTraceExporter),
 		exporterhelper.WithMetrics(createMetricsExporter),
+		exporterhelper.WithLogs(createLogsExporter),
 	)
 }
 
@@ -57,6 +61,7 @@ func createDefaultConfig() configmodels.Exporter {
 		},
 		TimeoutSettings: exporterhelper.TimeoutSettings{Timeout: defaultTimeout},
 		UserAgent:       "opentelemetry-collector-contrib {{version}}",
+		MaxProjects:     defaultMaxProjects,
 	}
 }
 
@@ -77,3 +82,12 @@ func createMetricsExporter(
 	eCfg := cfg.(*Config)
 	return newStackdriverMetricsExporter(eCfg, params.ApplicationStartInfo.Version)
 }
+
+// createLogsExporter creates a logs exporter based on this config.
+func createLogsExporter(
+	_ context.Context,
+	params component.ExporterCreateParams,
+	cfg configmodels.Exporter) (component.LogsExporter, error) {
+	eCfg := cfg.(*Config)
+	return newStackdriverLogsExporter(eCfg, params.ApplicationStartInfo.Version)
+}
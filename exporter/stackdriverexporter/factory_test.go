@@ -53,4 +53,10 @@ func TestCreateExporter(t *testing.T) {
 	}, eCfg)
 	assert.Nil(t, err)
 	assert.NotNil(t, me, "failed to create metrics exporter")
+
+	le, err := factory.CreateLogsExporter(ctx, component.ExporterCreateParams{
+		Logger: zap.NewNop(),
+	}, eCfg)
+	assert.Nil(t, err)
+	assert.NotNil(t, le, "failed to create logs exporter")
 }
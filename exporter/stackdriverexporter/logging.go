@@ -0,0 +1,303 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stackdriverexporter
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"go.opencensus.io/resource"
+	"go.opencensus.io/resource/resourcekeys"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.opentelemetry.io/collector/translator/conventions"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/option"
+	gtransport "google.golang.org/api/transport/grpc"
+	monitoredrespb "google.golang.org/genproto/googleapis/api/monitoredres"
+	logtypepb "google.golang.org/genproto/googleapis/logging/type"
+	loggingpb "google.golang.org/genproto/googleapis/logging/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultLoggingEndpoint = "logging.googleapis.com:443"
+	loggingWriteScope      = "https://www.googleapis.com/auth/logging.write"
+	// defaultLogName is used for log records without a name.
+	defaultLogName = "opentelemetry-collector"
+)
+
+// logsExporter writes log records to Cloud Logging.
+type logsExporter struct {
+	projectIDAttribute string
+	defaultProjectID   string
+	conn               *grpc.ClientConn
+	client             loggingpb.LoggingServiceV2Client
+	mapper             *resourceMapper
+}
+
+func (le *logsExporter) Shutdown(context.Context) error {
+	return le.conn.Close()
+}
+
+func newStackdriverLogsExporter(cfg *Config, version string) (component.LogsExporter, error) {
+	ctx := context.Background()
+
+	projectID := cfg.ProjectID
+	if projectID == "" {
+		creds, err := google.FindDefaultCredentials(ctx, loggingWriteScope)
+		if err != nil {
+			return nil, fmt.Errorf("error finding default credentials for Stackdriver Logging exporter: %w", err)
+		}
+		if creds.ProjectID == "" {
+			return nil, errors.New("no project found with application default credentials")
+		}
+		projectID = creds.ProjectID
+	}
+
+	copts, err := generateClientOptions(cfg, version)
+	if err != nil {
+		return nil, err
+	}
+	// Defaults go first so that they can be overridden by the configured options.
+	copts = append([]option.ClientOption{
+		option.WithEndpoint(defaultLoggingEndpoint),
+		option.WithScopes(loggingWriteScope),
+	}, copts...)
+
+	conn, err := gtransport.Dial(ctx, copts...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Stackdriver Logging exporter: %w", err)
+	}
+	lExp := &logsExporter{
+		projectIDAttribute: cfg.ProjectIDAttribute,
+		defaultProjectID:   projectID,
+		conn:               conn,
+		client:             loggingpb.NewLoggingServiceV2Client(conn),
+		mapper:             &resourceMapper{mappings: cfg.ResourceMappings},
+	}
+
+	return exporterhelper.NewLogsExporter(
+		cfg,
+		lExp.pushLogs,
+		exporterhelper.WithShutdown(lExp.Shutdown),
+		exporterhelper.WithTimeout(cfg.TimeoutSettings))
+}
+
+// pushLogs writes the log records in ld with one WriteLogEntries call per
+// destination project.
+func (le *logsExporter) pushLogs(ctx context.Context, ld pdata.Logs) (int, error) {
+	var errs []error
+	dropped := 0
+
+	for _, entries := range le.logsToEntries(ld) {
+		_, err := le.client.WriteLogEntries(ctx, &loggingpb.WriteLogEntriesRequest{
+			Entries: entries,
+		})
+		if err != nil {
+			dropped += len(entries)
+			errs = append(errs, err)
+		}
+	}
+	return dropped, componenterror.CombineErrors(errs)
+}
+
+// logsToEntries converts the log records in ld into log entries, grouped by
+// destination project.
+func (le *logsExporter) logsToEntries(ld pdata.Logs) map[string][]*loggingpb.LogEntry {
+	entriesByProject := make(map[string][]*loggingpb.LogEntry)
+
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		if rl.IsNil() {
+			continue
+		}
+
+		projectID := resourceProjectID(rl.Resource(), le.projectIDAttribute, le.defaultProjectID)
+		mr := le.mapper.mapResource(pdataResourceToOC(rl.Resource()))
+		ills := rl.InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			ill := ills.At(j)
+			if ill.IsNil() {
+				continue
+			}
+			logs := ill.Logs()
+			for k := 0; k < logs.Len(); k++ {
+				lr := logs.At(k)
+				if lr.IsNil() {
+					continue
+				}
+				entriesByProject[projectID] = append(entriesByProject[projectID], logRecordToEntry(projectID, mr, lr))
+			}
+		}
+	}
+	return entriesByProject
+}
+
+func logRecordToEntry(projectID string, mr *monitoredrespb.MonitoredResource, lr pdata.LogRecord) *loggingpb.LogEntry {
+	logName := lr.Name()
+	if logName == "" {
+		logName = defaultLogName
+	}
+
+	entry := &loggingpb.LogEntry{
+		LogName:  fmt.Sprintf("projects/%s/logs/%s", projectID, url.PathEscape(logName)),
+		Resource: mr,
+		Severity: severityNumberToLogSeverity(lr.SeverityNumber()),
+	}
+	if lr.Timestamp() != 0 {
+		entry.Timestamp = timestamppb.New(time.Unix(0, int64(lr.Timestamp())))
+	}
+
+	if traceID := lr.TraceID().Bytes(); len(traceID) > 0 {
+		entry.Trace = fmt.Sprintf("projects/%s/traces/%s", projectID, hex.EncodeToString(traceID))
+	}
+	if spanID := lr.SpanID(); len(spanID) > 0 {
+		entry.SpanId = spanID.String()
+	}
+	// The lowest bit of the W3C trace flags is the sampled flag.
+	entry.TraceSampled = lr.Flags()&1 == 1
+
+	if lr.Attributes().Len() > 0 {
+		entry.Labels = make(map[string]string, lr.Attributes().Len())
+		lr.Attributes().ForEach(func(k string, v pdata.AttributeValue) {
+			entry.Labels[k] = tracetranslator.AttributeValueToString(v, false)
+		})
+	}
+
+	body := lr.Body()
+	switch {
+	case body.IsNil():
+	case body.Type() == pdata.AttributeValueMAP:
+		entry.Payload = &loggingpb.LogEntry_JsonPayload{
+			JsonPayload: attributeMapToStruct(body.MapVal()),
+		}
+	default:
+		entry.Payload = &loggingpb.LogEntry_TextPayload{
+			TextPayload: tracetranslator.AttributeValueToString(body, false),
+		}
+	}
+
+	return entry
+}
+
+// inferredResourceTypes lists, in priority order, the label whose presence
+// implies an OpenCensus resource type when none is set explicitly.
+var inferredResourceTypes = []struct {
+	label        string
+	resourceType string
+}{
+	{conventions.AttributeContainerName, resourcekeys.ContainerType},
+	{conventions.AttributeK8sPod, resourcekeys.K8SType},
+	{conventions.AttributeHostName, resourcekeys.HostType},
+	{conventions.AttributeCloudProvider, resourcekeys.CloudType},
+}
+
+// pdataResourceToOC builds the OpenCensus resource expected by resourceMapper
+// from the attributes of r.
+func pdataResourceToOC(r pdata.Resource) *resource.Resource {
+	res := &resource.Resource{}
+	if r.IsNil() {
+		return res
+	}
+
+	attrs := r.Attributes()
+	res.Labels = make(map[string]string, attrs.Len())
+	attrs.ForEach(func(k string, v pdata.AttributeValue) {
+		val := tracetranslator.AttributeValueToString(v, false)
+		if k == conventions.OCAttributeResourceType {
+			res.Type = val
+			return
+		}
+		res.Labels[k] = val
+	})
+
+	if res.Type == "" {
+		for _, it := range inferredResourceTypes {
+			if _, ok := res.Labels[it.label]; ok {
+				res.Type = it.resourceType
+				break
+			}
+		}
+	}
+	return res
+}
+
+// severityNumberToLogSeverity maps OpenTelemetry severity numbers onto the
+// Cloud Logging severity levels.
+func severityNumberToLogSeverity(sn pdata.SeverityNumber) logtypepb.LogSeverity {
+	switch {
+	case sn == pdata.SeverityNumberUNDEFINED:
+		return logtypepb.LogSeverity_DEFAULT
+	case sn <= pdata.SeverityNumberDEBUG4:
+		return logtypepb.LogSeverity_DEBUG
+	case sn == pdata.SeverityNumberINFO:
+		return logtypepb.LogSeverity_INFO
+	case sn <= pdata.SeverityNumberINFO4:
+		return logtypepb.LogSeverity_NOTICE
+	case sn <= pdata.SeverityNumberWARN4:
+		return logtypepb.LogSeverity_WARNING
+	case sn <= pdata.SeverityNumberERROR4:
+		return logtypepb.LogSeverity_ERROR
+	case sn <= pdata.SeverityNumberFATAL2:
+		return logtypepb.LogSeverity_CRITICAL
+	case sn == pdata.SeverityNumberFATAL3:
+		return logtypepb.LogSeverity_ALERT
+	default:
+		return logtypepb.LogSeverity_EMERGENCY
+	}
+}
+
+func attributeMapToStruct(m pdata.AttributeMap) *structpb.Struct {
+	s := &structpb.Struct{Fields: make(map[string]*structpb.Value, m.Len())}
+	m.ForEach(func(k string, v pdata.AttributeValue) {
+		s.Fields[k] = attributeValueToStructValue(v)
+	})
+	return s
+}
+
+func attributeValueToStructValue(v pdata.AttributeValue) *structpb.Value {
+	switch v.Type() {
+	case pdata.AttributeValueSTRING:
+		return structpb.NewStringValue(v.StringVal())
+	case pdata.AttributeValueINT:
+		return structpb.NewNumberValue(float64(v.IntVal()))
+	case pdata.AttributeValueDOUBLE:
+		return structpb.NewNumberValue(v.DoubleVal())
+	case pdata.AttributeValueBOOL:
+		return structpb.NewBoolValue(v.BoolVal())
+	case pdata.AttributeValueMAP:
+		return structpb.NewStructValue(attributeMapToStruct(v.MapVal()))
+	case pdata.AttributeValueARRAY:
+		arr := v.ArrayVal()
+		values := make([]*structpb.Value, 0, arr.Len())
+		for i := 0; i < arr.Len(); i++ {
+			values = append(values, attributeValueToStructValue(arr.At(i)))
+		}
+		return structpb.NewListValue(&structpb.ListValue{Values: values})
+	default:
+		return structpb.NewNullValue()
+	}
+}
@@ -0,0 +1,207 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stackdriverexporter
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/resource"
+	"go.opencensus.io/resource/resourcekeys"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"google.golang.org/api/option"
+	monitoredrespb "google.golang.org/genproto/googleapis/api/monitoredres"
+	logtypepb "google.golang.org/genproto/googleapis/logging/type"
+	loggingpb "google.golang.org/genproto/googleapis/logging/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type mockLoggingServer struct {
+	loggingpb.LoggingServiceV2Server
+
+	reqCh chan *loggingpb.WriteLogEntriesRequest
+}
+
+func (ms *mockLoggingServer) WriteLogEntries(ctx context.Context, req *loggingpb.WriteLogEntriesRequest) (*loggingpb.WriteLogEntriesResponse, error) {
+	go func() { ms.reqCh <- req }()
+	return &loggingpb.WriteLogEntriesResponse{}, nil
+}
+
+func TestStackdriverLogsExport(t *testing.T) {
+	srv := grpc.NewServer()
+
+	reqCh := make(chan *loggingpb.WriteLogEntriesRequest)
+	loggingpb.RegisterLoggingServiceV2Server(srv, &mockLoggingServer{reqCh: reqCh})
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer lis.Close()
+
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	sde, err := newStackdriverLogsExporter(&Config{
+		ProjectID:          "idk",
+		ProjectIDAttribute: "gcp.project.id",
+		Endpoint:           lis.Addr().String(),
+		UseInsecure:        true,
+		GetClientOptions: func() []option.ClientOption {
+			return []option.ClientOption{option.WithoutAuthentication()}
+		},
+		ResourceMappings: []ResourceMapping{
+			{
+				SourceType: "source.resource",
+				TargetType: "target-resource",
+				LabelMappings: []LabelMapping{
+					{SourceKey: "source.label", TargetKey: "target_label"},
+				},
+			},
+		},
+	}, "v0.0.1")
+	require.NoError(t, err)
+	defer func() { require.NoError(t, sde.Shutdown(context.Background())) }()
+
+	testTime := time.Now()
+
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(2)
+	rl := ld.ResourceLogs().At(0)
+	rl.Resource().InitEmpty()
+	rl.Resource().Attributes().InsertString("opencensus.resourcetype", "source.resource")
+	rl.Resource().Attributes().InsertString("source.label", "value")
+	rl.InstrumentationLibraryLogs().Resize(1)
+	logs := rl.InstrumentationLibraryLogs().At(0).Logs()
+	logs.Resize(2)
+
+	lr := logs.At(0)
+	lr.SetName("app/log")
+	lr.SetTimestamp(pdata.TimestampUnixNano(testTime.UnixNano()))
+	lr.SetSeverityNumber(pdata.SeverityNumberWARN)
+	lr.SetTraceID(pdata.NewTraceID([]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}))
+	lr.SetSpanID(pdata.NewSpanID([]byte{1, 2, 3, 4, 5, 6, 7, 8}))
+	lr.SetFlags(1)
+	lr.Attributes().InsertInt("attempt", 2)
+	lr.Body().SetStringVal("hello")
+
+	lr = logs.At(1)
+	body := pdata.NewAttributeMap()
+	body.InsertString("message", "structured")
+	body.InsertInt("count", 3)
+	lr.Body().SetMapVal(body)
+
+	// Entries of resources with a project attribute are written separately.
+	rl = ld.ResourceLogs().At(1)
+	rl.Resource().InitEmpty()
+	rl.Resource().Attributes().InsertString("gcp.project.id", "tenant")
+	rl.InstrumentationLibraryLogs().Resize(1)
+	logs = rl.InstrumentationLibraryLogs().At(0).Logs()
+	logs.Resize(1)
+	logs.At(0).SetTraceID(pdata.NewTraceID([]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}))
+	logs.At(0).Body().SetStringVal("tenant log")
+
+	require.NoError(t, sde.ConsumeLogs(context.Background(), ld))
+
+	reqs := map[string]*loggingpb.WriteLogEntriesRequest{}
+	for i := 0; i < 2; i++ {
+		r := <-reqCh
+		require.NotEmpty(t, r.Entries)
+		reqs[r.Entries[0].GetTextPayload()] = r
+	}
+
+	tr := reqs["tenant log"]
+	require.NotNil(t, tr)
+	require.Len(t, tr.Entries, 1)
+	assert.Equal(t, "projects/tenant/logs/"+defaultLogName, tr.Entries[0].LogName)
+	assert.Equal(t, "projects/tenant/traces/0102030405060708090a0b0c0d0e0f10", tr.Entries[0].Trace)
+
+	r := reqs["hello"]
+	require.NotNil(t, r)
+	require.Len(t, r.Entries, 2)
+
+	e := r.Entries[0]
+	assert.Equal(t, "projects/idk/logs/app%2Flog", e.LogName)
+	assert.Equal(t, &monitoredrespb.MonitoredResource{
+		Type:   "target-resource",
+		Labels: map[string]string{"target_label": "value"},
+	}, e.Resource)
+	assert.Equal(t, timestamppb.New(testTime), e.Timestamp)
+	assert.Equal(t, logtypepb.LogSeverity_WARNING, e.Severity)
+	assert.Equal(t, "projects/idk/traces/0102030405060708090a0b0c0d0e0f10", e.Trace)
+	assert.Equal(t, "0102030405060708", e.SpanId)
+	assert.True(t, e.TraceSampled)
+	assert.Equal(t, map[string]string{"attempt": "2"}, e.Labels)
+	assert.Equal(t, "hello", e.GetTextPayload())
+
+	e = r.Entries[1]
+	assert.Equal(t, "projects/idk/logs/"+defaultLogName, e.LogName)
+	assert.Equal(t, logtypepb.LogSeverity_DEFAULT, e.Severity)
+	assert.Empty(t, e.Trace)
+	assert.False(t, e.TraceSampled)
+	assert.Equal(t, map[string]*structpb.Value{
+		"message": structpb.NewStringValue("structured"),
+		"count":   structpb.NewNumberValue(3),
+	}, e.GetJsonPayload().GetFields())
+}
+
+func TestSeverityNumberToLogSeverity(t *testing.T) {
+	tests := []struct {
+		in   pdata.SeverityNumber
+		want logtypepb.LogSeverity
+	}{
+		{pdata.SeverityNumberUNDEFINED, logtypepb.LogSeverity_DEFAULT},
+		{pdata.SeverityNumberTRACE, logtypepb.LogSeverity_DEBUG},
+		{pdata.SeverityNumberDEBUG4, logtypepb.LogSeverity_DEBUG},
+		{pdata.SeverityNumberINFO, logtypepb.LogSeverity_INFO},
+		{pdata.SeverityNumberINFO2, logtypepb.LogSeverity_NOTICE},
+		{pdata.SeverityNumberWARN3, logtypepb.LogSeverity_WARNING},
+		{pdata.SeverityNumberERROR, logtypepb.LogSeverity_ERROR},
+		{pdata.SeverityNumberFATAL, logtypepb.LogSeverity_CRITICAL},
+		{pdata.SeverityNumberFATAL3, logtypepb.LogSeverity_ALERT},
+		{pdata.SeverityNumberFATAL4, logtypepb.LogSeverity_EMERGENCY},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, severityNumberToLogSeverity(tt.in), "severity number %d", tt.in)
+	}
+}
+
+func TestPdataResourceToOC(t *testing.T) {
+	empty := pdata.NewResource()
+	assert.Equal(t, &resource.Resource{}, pdataResourceToOC(empty))
+
+	explicit := pdata.NewResource()
+	explicit.InitEmpty()
+	explicit.Attributes().InsertString("opencensus.resourcetype", "source.resource")
+	explicit.Attributes().InsertString("source.label", "value")
+	explicit.Attributes().InsertInt("source.count", 3)
+	assert.Equal(t, &resource.Resource{
+		Type:   "source.resource",
+		Labels: map[string]string{"source.label": "value", "source.count": "3"},
+	}, pdataResourceToOC(explicit))
+
+	inferred := pdata.NewResource()
+	inferred.InitEmpty()
+	inferred.Attributes().InsertString("k8s.pod.name", "pod")
+	inferred.Attributes().InsertString("host.name", "host")
+	assert.Equal(t, &resource.Resource{
+		Type:   resourcekeys.K8SType,
+		Labels: map[string]string{"k8s.pod.name": "pod", "host.name": "host"},
+	}, pdataResourceToOC(inferred))
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 
 	"contrib.go.opencensus.io/exporter/stackdriver"
 	cloudtrace "github.com/GoogleCloudPlatform/opentelemetry-operations-go/exporter/trace"
@@ -31,19 +32,41 @@ import (
 	"go.opentelemetry.io/collector/translator/internaldata"
 	traceexport "go.opentelemetry.io/otel/sdk/export/trace"
 	"google.golang.org/api/option"
+	"google.golang.org/api/transport"
 	"google.golang.org/grpc"
 )
 
-const name = "stackdriver"
+const (
+	name = "stackdriver"
+	// cloudPlatformScope covers both Cloud Trace and Cloud Monitoring, it is
+	// requested for the credentials shared by the exporters of all projects.
+	cloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+)
 
-// traceExporter is a wrapper struct of OT cloud trace exporter
+// traceExporter is a wrapper struct of OT cloud trace exporters, one per
+// destination project.
 type traceExporter struct {
-	texporter *cloudtrace.Exporter
+	projectIDAttribute string
+	defaultProjectID   string
+	// topts are the options the exporter of each project is created with.
+	topts       []cloudtrace.Option
+	maxProjects int
+
+	mu         sync.Mutex
+	texporters map[string]*cloudtrace.Exporter
 }
 
-// metricsExporter is a wrapper struct of OC stackdriver exporter
+// metricsExporter is a wrapper struct of OC stackdriver exporters, one per
+// destination project.
 type metricsExporter struct {
-	mexporter *stackdriver.Exporter
+	projectIDAttribute string
+	defaultProjectID   string
+	// options are the options the exporter of each project is created with.
+	options     stackdriver.Options
+	maxProjects int
+
+	mu         sync.Mutex
+	mexporters map[string]*stackdriver.Exporter
 }
 
 func (*traceExporter) Name() string {
@@ -55,16 +78,95 @@ func (*metricsExporter) Name() string {
 }
 
 func (te *traceExporter) Shutdown(context.Context) error {
-	te.texporter.Flush()
+	te.mu.Lock()
+	defer te.mu.Unlock()
+	for _, exp := range te.texporters {
+		exp.Flush()
+	}
 	return nil
 }
 
 func (me *metricsExporter) Shutdown(context.Context) error {
-	me.mexporter.Flush()
-	me.mexporter.StopMetricsExporter()
+	me.mu.Lock()
+	defer me.mu.Unlock()
+	for _, exp := range me.mexporters {
+		exp.Flush()
+		exp.StopMetricsExporter()
+	}
 	return nil
 }
 
+// exporterForProject returns the cloud trace exporter for projectID, creating
+// it on first use.
+func (te *traceExporter) exporterForProject(projectID string) (*cloudtrace.Exporter, error) {
+	te.mu.Lock()
+	defer te.mu.Unlock()
+	if exp, ok := te.texporters[projectID]; ok {
+		return exp, nil
+	}
+	if len(te.texporters) >= te.maxProjects {
+		return nil, errTooManyProjects(projectID, te.maxProjects)
+	}
+
+	topts := make([]cloudtrace.Option, 0, len(te.topts)+1)
+	topts = append(topts, cloudtrace.WithProjectID(projectID))
+	topts = append(topts, te.topts...)
+	exp, err := cloudtrace.NewExporter(topts...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Stackdriver Trace exporter: %w", err)
+	}
+	te.texporters[projectID] = exp
+	return exp, nil
+}
+
+// exporterForProject returns the stackdriver exporter for projectID, creating
+// it on first use.
+func (me *metricsExporter) exporterForProject(projectID string) (*stackdriver.Exporter, error) {
+	me.mu.Lock()
+	defer me.mu.Unlock()
+	if exp, ok := me.mexporters[projectID]; ok {
+		return exp, nil
+	}
+	if len(me.mexporters) >= me.maxProjects {
+		return nil, errTooManyProjects(projectID, me.maxProjects)
+	}
+
+	options := me.options
+	options.ProjectID = projectID
+	exp, err := stackdriver.NewExporter(options)
+	if err != nil {
+		return nil, fmt.Errorf("cannot configure Stackdriver metric exporter: %w", err)
+	}
+	me.mexporters[projectID] = exp
+	return exp, nil
+}
+
+// errTooManyProjects is returned for the data of a new project once the
+// exporters of maxProjects projects were created.
+func errTooManyProjects(projectID string, maxProjects int) error {
+	return fmt.Errorf("cannot export data to project %q, data is already sent to the maximum of %d projects", projectID, maxProjects)
+}
+
+// maxProjects returns the maximum number of projects data is sent to.
+func maxProjects(cfg *Config) int {
+	if cfg.MaxProjects <= 0 {
+		return defaultMaxProjects
+	}
+	return cfg.MaxProjects
+}
+
+// resourceProjectID returns the value of the projectIDAttribute resource
+// attribute, or defaultProjectID if it is not set.
+func resourceProjectID(resource pdata.Resource, projectIDAttribute, defaultProjectID string) string {
+	if projectIDAttribute == "" || resource.IsNil() {
+		return defaultProjectID
+	}
+	if v, ok := resource.Attributes().Get(projectIDAttribute); ok && v.Type() == pdata.AttributeValueSTRING && v.StringVal() != "" {
+		return v.StringVal()
+	}
+	return defaultProjectID
+}
+
 func generateClientOptions(cfg *Config, version string) ([]option.ClientOption, error) {
 	userAgent := strings.ReplaceAll(cfg.UserAgent, "{{version}}", version)
 	var copts []option.ClientOption
@@ -93,24 +195,58 @@ func generateClientOptions(cfg *Config, version string) ([]option.ClientOption,
 	return copts, nil
 }
 
+// withSharedCredentials resolves the credentials from copts, or the application
+// default credentials, once so that the clients of all projects use them rather
+// than each looking them up again. copts are returned as is if no credentials
+// are found, each client then reports the error itself.
+func withSharedCredentials(cfg *Config, copts []option.ClientOption) []option.ClientOption {
+	if cfg.UseInsecure {
+		return copts
+	}
+	creds, err := transport.Creds(context.Background(), append([]option.ClientOption{option.WithScopes(cloudPlatformScope)}, copts...)...)
+	if err != nil {
+		return copts
+	}
+	return append(copts, option.WithCredentials(creds))
+}
+
+// traceWorkersPerProject splits num_of_workers across the projects traces can
+// be sent to, as the exporter of each project runs its own upload workers.
+// Each project gets at least one worker. Zero keeps the default of the exporter.
+func traceWorkersPerProject(cfg *Config) int {
+	if cfg.NumOfWorkers <= 0 {
+		return 0
+	}
+	projects := 1
+	if cfg.ProjectIDAttribute != "" {
+		projects = maxProjects(cfg)
+	}
+	return (cfg.NumOfWorkers + projects - 1) / projects
+}
+
 func newStackdriverTraceExporter(cfg *Config, version string) (component.TraceExporter, error) {
 	topts := []cloudtrace.Option{
-		cloudtrace.WithProjectID(cfg.ProjectID),
 		cloudtrace.WithTimeout(cfg.Timeout),
 	}
 	copts, err := generateClientOptions(cfg, version)
 	if err != nil {
 		return nil, err
 	}
-	topts = append(topts, cloudtrace.WithTraceClientOptions(copts))
-	if cfg.NumOfWorkers > 0 {
-		topts = append(topts, cloudtrace.WithMaxNumberOfWorkers(cfg.NumOfWorkers))
+	topts = append(topts, cloudtrace.WithTraceClientOptions(withSharedCredentials(cfg, copts)))
+	if workers := traceWorkersPerProject(cfg); workers > 0 {
+		topts = append(topts, cloudtrace.WithMaxNumberOfWorkers(workers))
 	}
-	exp, err := cloudtrace.NewExporter(topts...)
-	if err != nil {
-		return nil, fmt.Errorf("error creating Stackdriver Trace exporter: %w", err)
+	tExp := &traceExporter{
+		projectIDAttribute: cfg.ProjectIDAttribute,
+		defaultProjectID:   cfg.ProjectID,
+		topts:              topts,
+		maxProjects:        maxProjects(cfg),
+		texporters:         make(map[string]*cloudtrace.Exporter),
+	}
+	// Invalid options fail the collector startup rather than the first export.
+	if _, err = tExp.exporterForProject(cfg.ProjectID); err != nil {
+		return nil, err
 	}
-	tExp := &traceExporter{texporter: exp}
 
 	return exporterhelper.NewTraceExporter(
 		cfg,
@@ -122,8 +258,6 @@ func newStackdriverTraceExporter(cfg *Config, version string) (component.TraceEx
 }
 
 func newStackdriverMetricsExporter(cfg *Config, version string) (component.MetricsExporter, error) {
-	// TODO:  For each ProjectID, create a different exporter
-	// or at least a unique Stackdriver client per ProjectID.
 	options := stackdriver.Options{
 		// If the project ID is an empty string, it will be set by default based on
 		// the project this is running on in GCP.
@@ -141,9 +275,12 @@ func newStackdriverMetricsExporter(cfg *Config, version string) (component.Metri
 	if err != nil {
 		return nil, err
 	}
+	copts = withSharedCredentials(cfg, copts)
 	options.TraceClientOptions = copts
 	options.MonitoringClientOptions = copts
 
+	// The workers of a stackdriver exporter only run while it pushes metrics,
+	// which is done for one project at a time.
 	if cfg.NumOfWorkers > 0 {
 		options.NumberOfWorkers = cfg.NumOfWorkers
 	}
@@ -157,11 +294,16 @@ func newStackdriverMetricsExporter(cfg *Config, version string) (component.Metri
 		options.MapResource = rm.mapResource
 	}
 
-	sde, serr := stackdriver.NewExporter(options)
-	if serr != nil {
-		return nil, fmt.Errorf("cannot configure Stackdriver metric exporter: %w", serr)
+	mExp := &metricsExporter{
+		projectIDAttribute: cfg.ProjectIDAttribute,
+		defaultProjectID:   cfg.ProjectID,
+		options:            options,
+		maxProjects:        maxProjects(cfg),
+		mexporters:         make(map[string]*stackdriver.Exporter),
+	}
+	if _, err = mExp.exporterForProject(cfg.ProjectID); err != nil {
+		return nil, err
 	}
-	mExp := &metricsExporter{mexporter: sde}
 
 	return exporterhelper.NewMetricsExporter(
 		cfg,
@@ -172,15 +314,34 @@ func newStackdriverMetricsExporter(cfg *Config, version string) (component.Metri
 		exporterhelper.WithTimeout(exporterhelper.TimeoutSettings{Timeout: 0}))
 }
 
-// pushMetrics calls StackdriverExporter.PushMetricsProto on each element of the given metrics
+// pushMetrics calls StackdriverExporter.PushMetricsProto on each element of the given metrics,
+// using the exporter of the element's project.
 func (me *metricsExporter) pushMetrics(ctx context.Context, m pdata.Metrics) (int, error) {
 	var errors []error
 	var totalDropped int
 
+	// The projects are read from the pdata resources, as the conversion moves
+	// some resource attributes to the OpenCensus node. MetricsToOC returns an
+	// element per non nil ResourceMetrics, in order.
+	var projectIDs []string
+	rms := m.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		if rm := rms.At(i); !rm.IsNil() {
+			projectIDs = append(projectIDs, resourceProjectID(rm.Resource(), me.projectIDAttribute, me.defaultProjectID))
+		}
+	}
+
 	mds := internaldata.MetricsToOC(m)
-	for _, md := range mds {
+	for i, md := range mds {
 		points := numPoints(md)
-		dropped, err := me.mexporter.PushMetricsProto(ctx, md.Node, md.Resource, md.Metrics)
+		exp, err := me.exporterForProject(projectIDs[i])
+		if err != nil {
+			recordPointCount(ctx, 0, points, err)
+			totalDropped += points
+			errors = append(errors, err)
+			continue
+		}
+		dropped, err := exp.PushMetricsProto(ctx, md.Node, md.Resource, md.Metrics)
 		recordPointCount(ctx, points-dropped, dropped, err)
 		totalDropped += dropped
 		if err != nil {
@@ -195,26 +356,36 @@ func (me *metricsExporter) pushMetrics(ctx context.Context, m pdata.Metrics) (in
 	return totalDropped, nil
 }
 
-// pushTraces calls texporter.ExportSpan for each span in the given traces
+// pushTraces calls ExportSpan for each span in the given traces, using the
+// exporter of the span's project.
 func (te *traceExporter) pushTraces(ctx context.Context, td pdata.Traces) (int, error) {
 	var errs []error
 	resourceSpans := td.ResourceSpans()
 	numSpans := td.SpanCount()
 	goodSpans := 0
-	spans := make([]*traceexport.SpanData, 0, numSpans)
+	spansByProject := make(map[string][]*traceexport.SpanData)
 
 	for i := 0; i < resourceSpans.Len(); i++ {
-		sd, err := pdataResourceSpansToOTSpanData(resourceSpans.At(i))
-		if err == nil {
-			spans = append(spans, sd...)
-		} else {
+		rs := resourceSpans.At(i)
+		sd, err := pdataResourceSpansToOTSpanData(rs)
+		if err != nil {
 			errs = append(errs, err)
+			continue
 		}
+		projectID := resourceProjectID(rs.Resource(), te.projectIDAttribute, te.defaultProjectID)
+		spansByProject[projectID] = append(spansByProject[projectID], sd...)
 	}
 
-	for _, span := range spans {
-		te.texporter.ExportSpan(ctx, span)
-		goodSpans++
+	for projectID, spans := range spansByProject {
+		exp, err := te.exporterForProject(projectID)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, span := range spans {
+			exp.ExportSpan(ctx, span)
+			goodSpans++
+		}
 	}
 
 	return numSpans - goodSpans, componenterror.CombineErrors(errs)
@@ -21,6 +21,7 @@ import (
 	"testing"
 	"time"
 
+	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
 	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
 	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
 	"github.com/stretchr/testify/assert"
@@ -29,7 +30,9 @@ import (
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/testutil/metricstestutil"
 	"go.opentelemetry.io/collector/translator/internaldata"
+	"golang.org/x/oauth2"
 	"google.golang.org/api/option"
+	"google.golang.org/api/transport"
 	cloudmetricpb "google.golang.org/genproto/googleapis/api/metric"
 	cloudtracepb "google.golang.org/genproto/googleapis/devtools/cloudtrace/v2"
 	cloudmonitoringpb "google.golang.org/genproto/googleapis/monitoring/v3"
@@ -199,3 +202,170 @@ func TestStackdriverMetricExport(t *testing.T) {
 	require.Len(t, tr.TimeSeries[0].Points, 1)
 	assert.Equal(t, float64(123), tr.TimeSeries[0].Points[0].Value.GetDoubleValue())
 }
+
+func TestStackdriverMetricExportPerProject(t *testing.T) {
+	srv := grpc.NewServer()
+
+	timeSeriesReqCh := make(chan *requestWithMetadata)
+	cloudmonitoringpb.RegisterMetricServiceServer(srv, &mockMetricServer{timeSeriesReqCh: timeSeriesReqCh})
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer lis.Close()
+
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	sde, err := newStackdriverMetricsExporter(&Config{
+		ProjectID:                  "idk",
+		ProjectIDAttribute:         "service.name",
+		Endpoint:                   lis.Addr().String(),
+		UseInsecure:                true,
+		SkipCreateMetricDescriptor: true,
+	}, "v0.0.1")
+	require.NoError(t, err)
+	defer func() { require.NoError(t, sde.Shutdown(context.Background())) }()
+
+	// service.name is part of the OpenCensus node rather than of its resource.
+	md := consumerdata.MetricsData{
+		Node: &commonpb.Node{ServiceInfo: &commonpb.ServiceInfo{Name: "tenant"}},
+		Metrics: []*metricspb.Metric{
+			metricstestutil.Gauge(
+				"test_gauge",
+				[]string{"k0"},
+				metricstestutil.Timeseries(time.Now(), []string{"v0"}, metricstestutil.Double(time.Now(), 123))),
+		},
+	}
+	assert.NoError(t, sde.ConsumeMetrics(context.Background(), internaldata.OCToMetrics(md)))
+
+	trm := <-timeSeriesReqCh
+	assert.Equal(t, "projects/tenant", trm.req.(*cloudmonitoringpb.CreateTimeSeriesRequest).Name)
+}
+
+func TestStackdriverTraceExportPerProject(t *testing.T) {
+	srv := grpc.NewServer()
+
+	reqCh := make(chan *cloudtracepb.BatchWriteSpansRequest)
+
+	cloudtracepb.RegisterTraceServiceServer(srv, &testServer{reqCh: reqCh})
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer lis.Close()
+
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	sde, err := newStackdriverTraceExporter(
+		&Config{ProjectID: "idk", ProjectIDAttribute: "gcp.project.id", Endpoint: lis.Addr().String(), UseInsecure: true},
+		"v0.0.1",
+	)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, sde.Shutdown(context.Background())) }()
+
+	traces := pdata.NewTraces()
+	traces.ResourceSpans().Resize(2)
+	for i, projectID := range []string{"", "tenant"} {
+		rspans := traces.ResourceSpans().At(i)
+		rspans.Resource().InitEmpty()
+		if projectID != "" {
+			rspans.Resource().Attributes().InsertString("gcp.project.id", projectID)
+		}
+		rspans.InstrumentationLibrarySpans().Resize(1)
+		ispans := rspans.InstrumentationLibrarySpans().At(0)
+		ispans.Spans().Resize(1)
+		span := ispans.Spans().At(0)
+		span.SetName("foobar")
+		span.SetTraceID(pdata.NewTraceID([]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, byte(i)}))
+		span.SetSpanID(pdata.NewSpanID([]byte{1, 2, 3, 4, 5, 6, 7, byte(i)}))
+		span.SetStartTime(pdata.TimestampUnixNano(time.Now().UnixNano()))
+	}
+	require.NoError(t, sde.ConsumeTraces(context.Background(), traces))
+
+	var names []string
+	for i := 0; i < 2; i++ {
+		r := <-reqCh
+		require.Len(t, r.Spans, 1)
+		names = append(names, r.Spans[0].Name)
+	}
+	assert.ElementsMatch(t, []string{
+		"projects/idk/traces/0102030405060708090a0b0c0d0e0f00/spans/0102030405060700",
+		"projects/tenant/traces/0102030405060708090a0b0c0d0e0f01/spans/0102030405060701",
+	}, names)
+}
+
+func TestStackdriverTraceExportMaxProjects(t *testing.T) {
+	srv := grpc.NewServer()
+
+	reqCh := make(chan *cloudtracepb.BatchWriteSpansRequest)
+
+	cloudtracepb.RegisterTraceServiceServer(srv, &testServer{reqCh: reqCh})
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer lis.Close()
+
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	sde, err := newStackdriverTraceExporter(
+		&Config{ProjectID: "idk", ProjectIDAttribute: "gcp.project.id", MaxProjects: 1, Endpoint: lis.Addr().String(), UseInsecure: true},
+		"v0.0.1",
+	)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, sde.Shutdown(context.Background())) }()
+
+	traces := pdata.NewTraces()
+	traces.ResourceSpans().Resize(1)
+	rspans := traces.ResourceSpans().At(0)
+	rspans.Resource().InitEmpty()
+	rspans.Resource().Attributes().InsertString("gcp.project.id", "tenant")
+	rspans.InstrumentationLibrarySpans().Resize(1)
+	ispans := rspans.InstrumentationLibrarySpans().At(0)
+	ispans.Spans().Resize(1)
+	span := ispans.Spans().At(0)
+	span.SetName("foobar")
+	span.SetTraceID(pdata.NewTraceID([]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}))
+	span.SetSpanID(pdata.NewSpanID([]byte{1, 2, 3, 4, 5, 6, 7, 8}))
+
+	// The exporter of the default project uses up the only slot.
+	err = sde.ConsumeTraces(context.Background(), traces)
+	assert.EqualError(t, err, `cannot export data to project "tenant", data is already sent to the maximum of 1 projects`)
+}
+
+func TestTraceWorkersPerProject(t *testing.T) {
+	assert.Equal(t, 0, traceWorkersPerProject(&Config{}))
+	assert.Equal(t, 10, traceWorkersPerProject(&Config{NumOfWorkers: 10, MaxProjects: 5}))
+	assert.Equal(t, 2, traceWorkersPerProject(&Config{NumOfWorkers: 10, MaxProjects: 5, ProjectIDAttribute: "gcp.project.id"}))
+	assert.Equal(t, 1, traceWorkersPerProject(&Config{NumOfWorkers: 10, ProjectIDAttribute: "gcp.project.id"}))
+}
+
+func TestWithSharedCredentials(t *testing.T) {
+	copts := []option.ClientOption{option.WithEndpoint("localhost:1234")}
+	assert.Equal(t, copts, withSharedCredentials(&Config{UseInsecure: true}, copts))
+
+	// The credentials are resolved from the client options once and passed to every client.
+	copts = append(copts, option.WithTokenSource(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"})))
+	shared := withSharedCredentials(&Config{}, copts)
+	require.Len(t, shared, len(copts)+1)
+	creds, err := transport.Creds(context.Background(), shared[len(copts)])
+	require.NoError(t, err)
+	token, err := creds.TokenSource.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "token", token.AccessToken)
+}
+
+func TestResourceProjectID(t *testing.T) {
+	resource := pdata.NewResource()
+	assert.Equal(t, "default", resourceProjectID(resource, "gcp.project.id", "default"))
+
+	resource.InitEmpty()
+	assert.Equal(t, "default", resourceProjectID(resource, "gcp.project.id", "default"))
+
+	resource.Attributes().InsertInt("gcp.project.id", 1)
+	assert.Equal(t, "default", resourceProjectID(resource, "gcp.project.id", "default"))
+
+	resource.Attributes().UpsertString("gcp.project.id", "tenant")
+	assert.Equal(t, "tenant", resourceProjectID(resource, "gcp.project.id", "default"))
+	assert.Equal(t, "default", resourceProjectID(resource, "", "default"))
+}
@@ -0,0 +1,62 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dockerobserver
+
+import (
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// Config defines configuration for docker observer.
+type Config struct {
+	configmodels.ExtensionSettings `mapstructure:",squash"`
+
+	// The URL of the docker server. Default is "unix:///var/run/docker.sock".
+	Endpoint string `mapstructure:"endpoint"`
+
+	// The maximum amount of time to wait for docker API responses. Default is 5s.
+	Timeout time.Duration `mapstructure:"timeout"`
+
+	// CacheSyncInterval determines how often the full list of containers is
+	// fetched again, to recover from any missed event. Default is 60m.
+	CacheSyncInterval time.Duration `mapstructure:"cache_sync_interval"`
+
+	// UseHostnameIfPresent uses the container hostname, if set, instead of the
+	// container IP address as the endpoint host. Default is false.
+	UseHostnameIfPresent bool `mapstructure:"use_hostname_if_present"`
+
+	// UseHostBindings uses the host IP and port a container port is bound to,
+	// if any, instead of the container IP and port. Default is false.
+	UseHostBindings bool `mapstructure:"use_host_bindings"`
+
+	// IgnoreNonHostBindings ignores the container ports which are not bound to
+	// a host port. Default is false.
+	IgnoreNonHostBindings bool `mapstructure:"ignore_non_host_bindings"`
+}
+
+func (config Config) Validate() error {
+	if config.Endpoint == "" {
+		return errors.New("config.Endpoint must be specified")
+	}
+	if config.Timeout <= 0 {
+		return errors.New("config.Timeout must be positive")
+	}
+	if config.CacheSyncInterval <= 0 {
+		return errors.New("config.CacheSyncInterval must be positive")
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dockerobserver
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configtest"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.ExampleComponents()
+	assert.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Extensions[typeStr] = factory
+	cfg, err := configtest.LoadConfigFile(t, path.Join(".", "testdata", "config.yaml"), factories)
+
+	require.Nil(t, err)
+	require.NotNil(t, cfg)
+
+	require.Len(t, cfg.Extensions, 2)
+
+	ext0 := cfg.Extensions["docker_observer"]
+	assert.Equal(t, factory.CreateDefaultConfig(), ext0)
+
+	ext1 := cfg.Extensions["docker_observer/all_settings"]
+	assert.Equal(t,
+		&Config{
+			ExtensionSettings: configmodels.ExtensionSettings{
+				TypeVal: "docker_observer",
+				NameVal: "docker_observer/all_settings",
+			},
+			Endpoint:              "unix:///var/run/docker.sock",
+			Timeout:               20 * time.Second,
+			CacheSyncInterval:     5 * time.Minute,
+			UseHostnameIfPresent:  true,
+			UseHostBindings:       true,
+			IgnoreNonHostBindings: true,
+		},
+		ext1)
+}
+
+func TestValidateConfig(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	assert.NoError(t, cfg.Validate())
+
+	cfg.CacheSyncInterval = -time.Second
+	assert.EqualError(t, cfg.Validate(), "config.CacheSyncInterval must be positive")
+
+	cfg.CacheSyncInterval = 0
+	assert.EqualError(t, cfg.Validate(), "config.CacheSyncInterval must be positive")
+
+	cfg.Timeout = -time.Second
+	assert.EqualError(t, cfg.Validate(), "config.Timeout must be positive")
+
+	cfg.Timeout = 0
+	assert.EqualError(t, cfg.Validate(), "config.Timeout must be positive")
+
+	cfg.Endpoint = ""
+	assert.EqualError(t, cfg.Validate(), "config.Endpoint must be specified")
+}
@@ -0,0 +1,400 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dockerobserver
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	dtypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
+	dfilters "github.com/docker/docker/api/types/filters"
+	docker "github.com/docker/docker/client"
+	"github.com/docker/go-connections/nat"
+	"go.opentelemetry.io/collector/component"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
+)
+
+const (
+	dockerAPIVersion = "v1.22"
+	userAgent        = "OpenTelemetry-Collector Docker Observer/v0.0.1"
+)
+
+// dockerClient is the subset of the docker client API used by the observer.
+type dockerClient interface {
+	ContainerList(ctx context.Context, options dtypes.ContainerListOptions) ([]dtypes.Container, error)
+	ContainerInspect(ctx context.Context, container string) (dtypes.ContainerJSON, error)
+	Events(ctx context.Context, options dtypes.EventsOptions) (<-chan events.Message, <-chan error)
+}
+
+type dockerObserver struct {
+	logger *zap.Logger
+	config *Config
+	client dockerClient
+	cancel context.CancelFunc
+
+	lock      sync.Mutex
+	listeners []observer.Notify
+	// endpoints are the endpoints of each running container, keyed by container ID.
+	endpoints map[string][]observer.Endpoint
+}
+
+var _ component.ServiceExtension = (*dockerObserver)(nil)
+
+// newObserver creates a new docker observer extension.
+func newObserver(logger *zap.Logger, config *Config) (component.ServiceExtension, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	client, err := docker.NewClientWithOpts(
+		docker.WithHost(config.Endpoint),
+		docker.WithVersion(dockerAPIVersion),
+		docker.WithHTTPHeaders(map[string]string{"User-Agent": userAgent}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not create docker client: %w", err)
+	}
+
+	return newObserverWithClient(logger, config, client), nil
+}
+
+func newObserverWithClient(logger *zap.Logger, config *Config, client dockerClient) *dockerObserver {
+	return &dockerObserver{
+		logger:    logger,
+		config:    config,
+		client:    client,
+		cancel:    func() {},
+		endpoints: map[string][]observer.Endpoint{},
+	}
+}
+
+func (d *dockerObserver) Start(context.Context, component.Host) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	d.cancel = cancel
+
+	go d.watch(ctx)
+	return nil
+}
+
+func (d *dockerObserver) Shutdown(context.Context) error {
+	d.cancel()
+	return nil
+}
+
+// ListAndWatch notifies listener with the current endpoints and sends
+// subsequent changes.
+func (d *dockerObserver) ListAndWatch(listener observer.Notify) {
+	d.lock.Lock()
+	d.listeners = append(d.listeners, listener)
+	var endpoints []observer.Endpoint
+	for _, containerEndpoints := range d.endpoints {
+		endpoints = append(endpoints, containerEndpoints...)
+	}
+	d.lock.Unlock()
+
+	if len(endpoints) > 0 {
+		listener.OnAdd(endpoints)
+	}
+}
+
+// watch lists the running containers and keeps track of their changes
+// through the docker events API until ctx is cancelled. The full list of
+// containers is synced again every CacheSyncInterval.
+func (d *dockerObserver) watch(ctx context.Context) {
+	since := time.Now()
+	if err := d.syncContainers(ctx); err != nil {
+		d.logger.Error("Could not list docker containers", zap.Error(err))
+	}
+	go d.containerEventLoop(ctx, since)
+
+	ticker := time.NewTicker(d.config.CacheSyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := d.syncContainers(ctx); err != nil {
+				d.logger.Error("Could not list docker containers", zap.Error(err))
+			}
+		}
+	}
+}
+
+// syncContainers updates the endpoints of all running containers, and removes
+// the endpoints of containers which are no longer running.
+func (d *dockerObserver) syncContainers(ctx context.Context) error {
+	filters := dfilters.NewArgs()
+	filters.Add("status", "running")
+
+	listCtx, cancel := context.WithTimeout(ctx, d.config.Timeout)
+	containers, err := d.client.ContainerList(listCtx, dtypes.ContainerListOptions{Filters: filters})
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	running := make(map[string]bool, len(containers))
+	for _, c := range containers {
+		running[c.ID] = true
+		d.updateContainer(ctx, c.ID)
+	}
+
+	d.lock.Lock()
+	var stopped []string
+	for cid := range d.endpoints {
+		if !running[cid] {
+			stopped = append(stopped, cid)
+		}
+	}
+	d.lock.Unlock()
+
+	for _, cid := range stopped {
+		d.setContainerEndpoints(cid, nil)
+	}
+	return nil
+}
+
+func (d *dockerObserver) containerEventLoop(ctx context.Context, since time.Time) {
+	filters := dfilters.NewArgs([]dfilters.KeyValuePair{
+		{Key: "type", Value: "container"},
+		{Key: "event", Value: "destroy"},
+		{Key: "event", Value: "die"},
+		{Key: "event", Value: "pause"},
+		{Key: "event", Value: "rename"},
+		{Key: "event", Value: "stop"},
+		{Key: "event", Value: "start"},
+		{Key: "event", Value: "unpause"},
+		{Key: "event", Value: "update"},
+	}...)
+	lastTime := since
+
+EVENT_LOOP:
+	for {
+		options := dtypes.EventsOptions{
+			Filters: filters,
+			Since:   lastTime.Format(time.RFC3339Nano),
+		}
+		eventCh, errCh := d.client.Events(ctx, options)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event := <-eventCh:
+				d.logger.Debug(
+					"Docker container update:",
+					zap.String("id", event.ID),
+					zap.String("action", event.Action),
+				)
+				if event.Action == "destroy" {
+					d.setContainerEndpoints(event.ID, nil)
+				} else {
+					d.updateContainer(ctx, event.ID)
+				}
+
+				if event.TimeNano > lastTime.UnixNano() {
+					lastTime = time.Unix(0, event.TimeNano)
+				}
+
+			case err := <-errCh:
+				// We are only interested when the context hasn't been canceled since requests made
+				// with a closed context are guaranteed to fail.
+				if ctx.Err() == nil {
+					d.logger.Error("Error watching docker container events", zap.Error(err))
+					// Either decoding or connection error has occurred, so we should resume the event loop after
+					// waiting a moment.
+					select {
+					case <-time.After(3 * time.Second):
+						continue EVENT_LOOP
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+// updateContainer inspects the container and updates its endpoints.
+func (d *dockerObserver) updateContainer(ctx context.Context, cid string) {
+	inspectCtx, cancel := context.WithTimeout(ctx, d.config.Timeout)
+	c, err := d.client.ContainerInspect(inspectCtx, cid)
+	cancel()
+	if err != nil {
+		if docker.IsErrNotFound(err) {
+			d.setContainerEndpoints(cid, nil)
+			return
+		}
+		d.logger.Error("Could not inspect updated container", zap.String("id", cid), zap.Error(err))
+		return
+	}
+
+	d.setContainerEndpoints(cid, d.containerEndpoints(&c))
+}
+
+// setContainerEndpoints replaces the endpoints of the container and notifies
+// the listeners of the differences. Listeners are notified once the lock is
+// released so that they can call back into the observer.
+func (d *dockerObserver) setContainerEndpoints(cid string, endpoints []observer.Endpoint) {
+	d.lock.Lock()
+
+	existing := make(map[observer.EndpointID]observer.Endpoint, len(d.endpoints[cid]))
+	for _, e := range d.endpoints[cid] {
+		existing[e.ID] = e
+	}
+
+	var added, changed, removed []observer.Endpoint
+	for _, e := range endpoints {
+		old, ok := existing[e.ID]
+		switch {
+		case !ok:
+			added = append(added, e)
+		case !reflect.DeepEqual(old, e):
+			changed = append(changed, e)
+		}
+		delete(existing, e.ID)
+	}
+	for _, e := range existing {
+		removed = append(removed, e)
+	}
+
+	if len(endpoints) == 0 {
+		delete(d.endpoints, cid)
+	} else {
+		d.endpoints[cid] = endpoints
+	}
+	listeners := append([]observer.Notify(nil), d.listeners...)
+	d.lock.Unlock()
+
+	for _, listener := range listeners {
+		if len(removed) > 0 {
+			listener.OnRemove(removed)
+		}
+		if len(added) > 0 {
+			listener.OnAdd(added)
+		}
+		if len(changed) > 0 {
+			listener.OnChange(changed)
+		}
+	}
+}
+
+// containerEndpoints returns an endpoint for each port exposed by the
+// container, or none if the container is not running.
+func (d *dockerObserver) containerEndpoints(c *dtypes.ContainerJSON) []observer.Endpoint {
+	if c.State == nil || !c.State.Running || c.State.Paused || c.Config == nil {
+		return nil
+	}
+
+	var endpoints []observer.Endpoint
+	for port := range c.Config.ExposedPorts {
+		details := observer.Container{
+			Name:        strings.TrimPrefix(c.Name, "/"),
+			Image:       c.Config.Image,
+			Command:     strings.Join(c.Config.Cmd, " "),
+			ContainerID: c.ID,
+			Transport:   portProtoToTransport(port.Proto()),
+			Labels:      c.Config.Labels,
+		}
+
+		containerPort := uint16(port.Int())
+		hostIP, hostPort, bound := d.hostBinding(c, port)
+		if !bound && d.config.IgnoreNonHostBindings {
+			continue
+		}
+
+		if bound && d.config.UseHostBindings {
+			details.Host = hostIP
+			details.Port = hostPort
+			details.AlternatePort = containerPort
+		} else {
+			details.Host = containerHost(c, d.config.UseHostnameIfPresent)
+			details.Port = containerPort
+			details.AlternatePort = hostPort
+		}
+		if details.Host == "" {
+			continue
+		}
+
+		endpoints = append(endpoints, observer.Endpoint{
+			ID: observer.EndpointID(
+				fmt.Sprintf("(%s)%s-%d-%s", d.config.Name(), c.ID, containerPort, details.Transport),
+			),
+			Target:  net.JoinHostPort(details.Host, strconv.Itoa(int(details.Port))),
+			Details: details,
+		})
+	}
+	return endpoints
+}
+
+// hostBinding returns the host IP and port the container port is bound to.
+func (d *dockerObserver) hostBinding(c *dtypes.ContainerJSON, port nat.Port) (string, uint16, bool) {
+	if c.NetworkSettings == nil {
+		return "", 0, false
+	}
+	for _, binding := range c.NetworkSettings.Ports[port] {
+		hostPort, err := strconv.ParseUint(binding.HostPort, 10, 16)
+		if err != nil {
+			continue
+		}
+		hostIP := binding.HostIP
+		// A host IP of 0.0.0.0 means it listens on all interfaces, including
+		// localhost, so use that since we can't actually connect to 0.0.0.0.
+		if hostIP == "" || hostIP == "0.0.0.0" {
+			hostIP = "127.0.0.1"
+		}
+		return hostIP, uint16(hostPort), true
+	}
+	return "", 0, false
+}
+
+// containerHost returns the hostname or IP address of the container.
+func containerHost(c *dtypes.ContainerJSON, useHostname bool) string {
+	if useHostname && c.Config.Hostname != "" {
+		return c.Config.Hostname
+	}
+	if c.NetworkSettings == nil {
+		return ""
+	}
+	if c.NetworkSettings.IPAddress != "" {
+		return c.NetworkSettings.IPAddress
+	}
+	for _, network := range c.NetworkSettings.Networks {
+		if network != nil && network.IPAddress != "" {
+			return network.IPAddress
+		}
+	}
+	return ""
+}
+
+func portProtoToTransport(proto string) observer.Transport {
+	switch proto {
+	case "tcp":
+		return observer.ProtocolTCP
+	case "udp":
+		return observer.ProtocolUDP
+	}
+	return observer.ProtocolUnknown
+}
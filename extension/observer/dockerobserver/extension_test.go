@@ -0,0 +1,364 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dockerobserver
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	dtypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/go-connections/nat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
+)
+
+type notFoundError struct{}
+
+func (notFoundError) Error() string { return "not found" }
+func (notFoundError) NotFound()     {}
+
+// fakeClient is a dockerClient serving the containers it holds.
+type fakeClient struct {
+	sync.Mutex
+	containers map[string]dtypes.ContainerJSON
+	events     chan events.Message
+	errs       chan error
+}
+
+func newFakeClient(containers ...dtypes.ContainerJSON) *fakeClient {
+	c := &fakeClient{
+		containers: map[string]dtypes.ContainerJSON{},
+		events:     make(chan events.Message),
+		errs:       make(chan error),
+	}
+	for _, cnt := range containers {
+		c.containers[cnt.ID] = cnt
+	}
+	return c
+}
+
+func (c *fakeClient) ContainerList(context.Context, dtypes.ContainerListOptions) ([]dtypes.Container, error) {
+	c.Lock()
+	defer c.Unlock()
+	var containers []dtypes.Container
+	for id := range c.containers {
+		containers = append(containers, dtypes.Container{ID: id})
+	}
+	return containers, nil
+}
+
+func (c *fakeClient) ContainerInspect(_ context.Context, id string) (dtypes.ContainerJSON, error) {
+	c.Lock()
+	defer c.Unlock()
+	cnt, ok := c.containers[id]
+	if !ok {
+		return dtypes.ContainerJSON{}, notFoundError{}
+	}
+	return cnt, nil
+}
+
+func (c *fakeClient) Events(context.Context, dtypes.EventsOptions) (<-chan events.Message, <-chan error) {
+	return c.events, c.errs
+}
+
+func (c *fakeClient) set(cnt dtypes.ContainerJSON) {
+	c.Lock()
+	defer c.Unlock()
+	c.containers[cnt.ID] = cnt
+}
+
+func (c *fakeClient) remove(id string) {
+	c.Lock()
+	defer c.Unlock()
+	delete(c.containers, id)
+}
+
+type endpointSink struct {
+	sync.Mutex
+	added   []observer.Endpoint
+	removed []observer.Endpoint
+	changed []observer.Endpoint
+}
+
+func (e *endpointSink) OnAdd(added []observer.Endpoint) {
+	e.Lock()
+	defer e.Unlock()
+	e.added = append(e.added, added...)
+}
+
+func (e *endpointSink) OnRemove(removed []observer.Endpoint) {
+	e.Lock()
+	defer e.Unlock()
+	e.removed = append(e.removed, removed...)
+}
+
+func (e *endpointSink) OnChange(changed []observer.Endpoint) {
+	e.Lock()
+	defer e.Unlock()
+	e.changed = append(e.changed, changed...)
+}
+
+var _ observer.Notify = (*endpointSink)(nil)
+
+func assertSink(t *testing.T, sink *endpointSink, f func() bool) {
+	assert.Eventually(t, func() bool {
+		sink.Lock()
+		defer sink.Unlock()
+		return f()
+	}, 1*time.Second, 10*time.Millisecond)
+}
+
+func redisContainer(labels map[string]string) dtypes.ContainerJSON {
+	return dtypes.ContainerJSON{
+		ContainerJSONBase: &dtypes.ContainerJSONBase{
+			ID:    "redis_id",
+			Name:  "/redis",
+			State: &dtypes.ContainerState{Running: true},
+		},
+		Config: &container.Config{
+			Hostname: "redis-host",
+			Image:    "redis:6",
+			Cmd:      []string{"redis-server", "--appendonly", "yes"},
+			Labels:   labels,
+			ExposedPorts: nat.PortSet{
+				"6379/tcp": struct{}{},
+				"9121/udp": struct{}{},
+			},
+		},
+		NetworkSettings: &dtypes.NetworkSettings{
+			NetworkSettingsBase: dtypes.NetworkSettingsBase{
+				Ports: nat.PortMap{
+					"6379/tcp": []nat.PortBinding{{HostIP: "0.0.0.0", HostPort: "16379"}},
+				},
+			},
+			Networks: map[string]*network.EndpointSettings{
+				"bridge": {IPAddress: "172.17.0.2"},
+			},
+		},
+	}
+}
+
+func TestContainerEndpoints(t *testing.T) {
+	redis := redisContainer(map[string]string{"app": "redis"})
+	tcpDetails := observer.Container{
+		Name:          "redis",
+		Image:         "redis:6",
+		Port:          6379,
+		AlternatePort: 16379,
+		Command:       "redis-server --appendonly yes",
+		ContainerID:   "redis_id",
+		Host:          "172.17.0.2",
+		Transport:     observer.ProtocolTCP,
+		Labels:        map[string]string{"app": "redis"},
+	}
+	udpDetails := tcpDetails
+	udpDetails.Port = 9121
+	udpDetails.AlternatePort = 0
+	udpDetails.Transport = observer.ProtocolUDP
+
+	hostBindingDetails := tcpDetails
+	hostBindingDetails.Host = "127.0.0.1"
+	hostBindingDetails.Port = 16379
+	hostBindingDetails.AlternatePort = 6379
+
+	hostnameDetails := tcpDetails
+	hostnameDetails.Host = "redis-host"
+
+	tcpID := observer.EndpointID("(docker_observer)redis_id-6379-TCP")
+	udpID := observer.EndpointID("(docker_observer)redis_id-9121-UDP")
+
+	tests := []struct {
+		name   string
+		config func(*Config)
+		c      dtypes.ContainerJSON
+		want   []observer.Endpoint
+	}{
+		{
+			name:   "container_ip",
+			config: func(*Config) {},
+			c:      redis,
+			want: []observer.Endpoint{
+				{ID: tcpID, Target: "172.17.0.2:6379", Details: tcpDetails},
+				{ID: udpID, Target: "172.17.0.2:9121", Details: udpDetails},
+			},
+		},
+		{
+			name: "host_bindings",
+			config: func(cfg *Config) {
+				cfg.UseHostBindings = true
+				cfg.IgnoreNonHostBindings = true
+			},
+			c: redis,
+			want: []observer.Endpoint{
+				{ID: tcpID, Target: "127.0.0.1:16379", Details: hostBindingDetails},
+			},
+		},
+		{
+			name: "hostname",
+			config: func(cfg *Config) {
+				cfg.UseHostnameIfPresent = true
+				cfg.IgnoreNonHostBindings = true
+			},
+			c: redis,
+			want: []observer.Endpoint{
+				{ID: tcpID, Target: "redis-host:6379", Details: hostnameDetails},
+			},
+		},
+		{
+			name:   "paused",
+			config: func(*Config) {},
+			c: func() dtypes.ContainerJSON {
+				c := redisContainer(nil)
+				c.State = &dtypes.ContainerState{Running: true, Paused: true}
+				return c
+			}(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			tt.config(cfg)
+			d := newObserverWithClient(zap.NewNop(), cfg, newFakeClient())
+
+			got := d.containerEndpoints(&tt.c)
+			assert.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func TestListAndWatch(t *testing.T) {
+	client := newFakeClient(redisContainer(map[string]string{"app": "redis"}))
+	cfg := createDefaultConfig().(*Config)
+	cfg.IgnoreNonHostBindings = true
+	d := newObserverWithClient(zap.NewNop(), cfg, client)
+
+	require.NoError(t, d.Start(context.Background(), componenttest.NewNopHost()))
+	defer d.Shutdown(context.Background())
+
+	sink := &endpointSink{}
+	// Listen once the initial container list is synced, to check that the
+	// current endpoints are sent to new listeners.
+	assert.Eventually(t, func() bool {
+		d.lock.Lock()
+		defer d.lock.Unlock()
+		return len(d.endpoints) == 1
+	}, 1*time.Second, 10*time.Millisecond)
+	d.ListAndWatch(sink)
+
+	assertSink(t, sink, func() bool {
+		return len(sink.added) == 1 && sink.added[0].Target == "172.17.0.2:6379"
+	})
+
+	// Label update.
+	client.set(redisContainer(map[string]string{"app": "redis", "env": "prod"}))
+	client.events <- events.Message{ID: "redis_id", Action: "update", TimeNano: time.Now().UnixNano()}
+	assertSink(t, sink, func() bool {
+		return len(sink.changed) == 1 &&
+			sink.changed[0].Details.(observer.Container).Labels["env"] == "prod"
+	})
+
+	// Events not changing the endpoints are not notified.
+	client.events <- events.Message{ID: "redis_id", Action: "update", TimeNano: time.Now().UnixNano()}
+
+	client.remove("redis_id")
+	client.events <- events.Message{ID: "redis_id", Action: "destroy", TimeNano: time.Now().UnixNano()}
+	assertSink(t, sink, func() bool {
+		return len(sink.removed) == 1 && sink.removed[0].ID == "(docker_observer)redis_id-6379-TCP"
+	})
+
+	sink.Lock()
+	defer sink.Unlock()
+	assert.Len(t, sink.added, 1)
+	assert.Len(t, sink.changed, 1)
+}
+
+func TestSyncRemovesStoppedContainers(t *testing.T) {
+	client := newFakeClient(redisContainer(nil))
+	d := newObserverWithClient(zap.NewNop(), createDefaultConfig().(*Config), client)
+
+	sink := &endpointSink{}
+	d.ListAndWatch(sink)
+
+	require.NoError(t, d.syncContainers(context.Background()))
+	assert.Len(t, sink.added, 2)
+
+	client.remove("redis_id")
+	require.NoError(t, d.syncContainers(context.Background()))
+	assert.Len(t, sink.removed, 2)
+	assert.Empty(t, d.endpoints)
+}
+
+// callbackSink calls onAdd for each notified addition.
+type callbackSink struct {
+	endpointSink
+	onAdd func()
+}
+
+func (c *callbackSink) OnAdd(added []observer.Endpoint) {
+	c.endpointSink.OnAdd(added)
+	c.onAdd()
+}
+
+func TestListenersCanCallBackIntoObserver(t *testing.T) {
+	client := newFakeClient(redisContainer(nil))
+	d := newObserverWithClient(zap.NewNop(), createDefaultConfig().(*Config), client)
+
+	inner := &endpointSink{}
+	sink := &callbackSink{onAdd: func() { d.ListAndWatch(inner) }}
+	d.ListAndWatch(sink)
+
+	done := make(chan error)
+	go func() { done <- d.syncContainers(context.Background()) }()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("listener could not call back into the observer")
+	}
+
+	assert.Len(t, sink.added, 2)
+	assert.NotEmpty(t, inner.added)
+}
+
+func TestEventLoopHandlesError(t *testing.T) {
+	client := newFakeClient()
+	d := newObserverWithClient(zap.NewNop(), createDefaultConfig().(*Config), client)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		d.containerEventLoop(ctx, time.Now())
+	}()
+
+	client.errs <- errors.New("connection reset")
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("event loop did not stop after its context was cancelled")
+	}
+}
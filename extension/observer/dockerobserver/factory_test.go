@@ -0,0 +1,52 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dockerobserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configcheck"
+	"go.uber.org/zap"
+)
+
+func TestValidConfig(t *testing.T) {
+	err := configcheck.ValidateConfig(createDefaultConfig())
+	require.NoError(t, err)
+}
+
+func TestCreateExtension(t *testing.T) {
+	dockerObserver, err := createExtension(
+		context.Background(),
+		component.ExtensionCreateParams{Logger: zap.NewNop()},
+		createDefaultConfig(),
+	)
+	require.NoError(t, err)
+	require.NotNil(t, dockerObserver)
+}
+
+func TestCreateExtensionInvalidEndpoint(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = "$notavalidendpoint*"
+	dockerObserver, err := createExtension(
+		context.Background(),
+		component.ExtensionCreateParams{Logger: zap.NewNop()},
+		cfg,
+	)
+	require.Error(t, err)
+	require.Nil(t, dockerObserver)
+}
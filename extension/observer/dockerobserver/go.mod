@@ -0,0 +1,14 @@
+module github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer/dockerobserver
+
+go 1.14
+
+require (
+	github.com/docker/docker v17.12.0-ce-rc1.0.20200706150819-a40b877fbb9e+incompatible
+	github.com/docker/go-connections v0.4.0
+	github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer v0.0.0-00010101000000-000000000000
+	github.com/stretchr/testify v1.6.1
+	go.opentelemetry.io/collector v0.11.1-0.20200924160956-8690937037da
+	go.uber.org/zap v1.16.0
+)
+
+replace github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer => ../
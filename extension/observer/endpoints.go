@@ -70,13 +70,90 @@ type HostPort struct {
 	IsIPv6 bool
 }
 
+// Container is a discovered container port.
+type Container struct {
+	// Name is the primary name of the container.
+	Name string
+	// Image is the name of the container image.
+	Image string
+	// Port is the port of the endpoint, through which it can be reached.
+	Port uint16
+	// AlternatePort is the other port of the endpoint: the container port
+	// when Port is the host port it is bound to, and vice-versa. It is 0
+	// when the container port is not bound to any host port.
+	AlternatePort uint16
+	// Command used to invoke the process running in the container.
+	Command string
+	// ContainerID is the id of the container exposing the Endpoint.
+	ContainerID string
+	// Host is the hostname or IP address of the Endpoint.
+	Host string
+	// Transport is the transport protocol used by the Endpoint. (TCP or UDP).
+	Transport Transport
+	// Labels is a map of user-specified metadata on the container.
+	Labels map[string]string
+}
+
+// K8sNode is a discovered Kubernetes node.
+type K8sNode struct {
+	// Name of the node.
+	Name string
+	// UID is the unique ID of the node.
+	UID string
+	// Labels is a map of user-specified metadata.
+	Labels map[string]string
+	// Annotations is a map of user-specified metadata.
+	Annotations map[string]string
+	// InternalIP is the node's internal IP address.
+	InternalIP string
+	// InternalDNS is the node's internal DNS name.
+	InternalDNS string
+	// Hostname is the node's hostname as reported by its Status object.
+	Hostname string
+	// ExternalIP is the node's external IP address.
+	ExternalIP string
+	// ExternalDNS is the node's external DNS name.
+	ExternalDNS string
+	// KubeletEndpointPort is the node's Kubelet endpoint port.
+	KubeletEndpointPort uint16
+}
+
+// K8sService is a port of a discovered Kubernetes service.
+type K8sService struct {
+	// Name of the service.
+	Name string
+	// UID is the unique ID of the service.
+	UID string
+	// Namespace of the service.
+	Namespace string
+	// Labels is a map of user-specified metadata.
+	Labels map[string]string
+	// Annotations is a map of user-specified metadata.
+	Annotations map[string]string
+	// Selector is the label selector of the pods backing the service.
+	Selector map[string]string
+	// ServiceType is the type of the service: ClusterIP, NodePort, LoadBalancer or ExternalName.
+	ServiceType string
+	// ClusterIP is the IP address of the service within the cluster.
+	ClusterIP string
+	// PortName is the name of the service port.
+	PortName string
+	// Port number of the endpoint.
+	Port uint16
+	// Transport is the transport protocol used by the Endpoint. (TCP or UDP).
+	Transport Transport
+}
+
 type EndpointEnv map[string]interface{}
 
 // EndpointToEnv converts an endpoint into a map suitable for expr evaluation.
 func EndpointToEnv(endpoint Endpoint) (EndpointEnv, error) {
 	ruleTypes := map[string]interface{}{
-		"port": false,
-		"pod":  false,
+		"port":        false,
+		"pod":         false,
+		"container":   false,
+		"k8s_node":    false,
+		"k8s_service": false,
 	}
 
 	switch o := endpoint.Details.(type) {
@@ -114,6 +191,54 @@ func EndpointToEnv(endpoint Endpoint) (EndpointEnv, error) {
 			"port":      o.Port,
 			"transport": o.Transport,
 		}, nil
+	case Container:
+		ruleTypes["container"] = true
+		return map[string]interface{}{
+			"type":           ruleTypes,
+			"endpoint":       endpoint.Target,
+			"name":           o.Name,
+			"image":          o.Image,
+			"port":           o.Port,
+			"alternate_port": o.AlternatePort,
+			"command":        o.Command,
+			"container_id":   o.ContainerID,
+			"host":           o.Host,
+			"transport":      o.Transport,
+			"labels":         o.Labels,
+		}, nil
+	case K8sNode:
+		ruleTypes["k8s_node"] = true
+		return map[string]interface{}{
+			"type":                  ruleTypes,
+			"endpoint":              endpoint.Target,
+			"name":                  o.Name,
+			"uid":                   o.UID,
+			"labels":                o.Labels,
+			"annotations":           o.Annotations,
+			"internal_ip":           o.InternalIP,
+			"internal_dns":          o.InternalDNS,
+			"hostname":              o.Hostname,
+			"external_ip":           o.ExternalIP,
+			"external_dns":          o.ExternalDNS,
+			"kubelet_endpoint_port": o.KubeletEndpointPort,
+		}, nil
+	case K8sService:
+		ruleTypes["k8s_service"] = true
+		return map[string]interface{}{
+			"type":         ruleTypes,
+			"endpoint":     endpoint.Target,
+			"name":         o.Name,
+			"uid":          o.UID,
+			"namespace":    o.Namespace,
+			"labels":       o.Labels,
+			"annotations":  o.Annotations,
+			"selector":     o.Selector,
+			"service_type": o.ServiceType,
+			"cluster_ip":   o.ClusterIP,
+			"port_name":    o.PortName,
+			"port":         o.Port,
+			"transport":    o.Transport,
+		}, nil
 
 	default:
 		return nil, fmt.Errorf("unknown endpoint details type %T", endpoint.Details)
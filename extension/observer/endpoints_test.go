@@ -43,8 +43,11 @@ func TestEndpointToEnv(t *testing.T) {
 			},
 			want: EndpointEnv{
 				"type": map[string]interface{}{
-					"port": false,
-					"pod":  true,
+					"port":        false,
+					"pod":         true,
+					"container":   false,
+					"k8s_node":    false,
+					"k8s_service": false,
 				},
 				"endpoint": "192.68.73.2",
 				"name":     "pod_name",
@@ -79,8 +82,11 @@ func TestEndpointToEnv(t *testing.T) {
 			},
 			want: EndpointEnv{
 				"type": map[string]interface{}{
-					"port": true,
-					"pod":  false,
+					"port":        true,
+					"pod":         false,
+					"container":   false,
+					"k8s_node":    false,
+					"k8s_service": false,
 				},
 				"endpoint": "192.68.73.2",
 				"name":     "port_name",
@@ -113,8 +119,11 @@ func TestEndpointToEnv(t *testing.T) {
 			},
 			want: EndpointEnv{
 				"type": map[string]interface{}{
-					"port": true,
-					"pod":  false,
+					"port":        true,
+					"pod":         false,
+					"container":   false,
+					"k8s_node":    false,
+					"k8s_service": false,
 				},
 				"endpoint":  "127.0.0.1",
 				"name":      "process_name",
@@ -125,6 +134,130 @@ func TestEndpointToEnv(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Container",
+			endpoint: Endpoint{
+				ID:     EndpointID("container_endpoint_id"),
+				Target: "127.0.0.1:2379",
+				Details: Container{
+					Name:          "otel-collector",
+					Image:         "otel-collector-image",
+					Port:          2379,
+					AlternatePort: 2380,
+					Command:       "./cmd --config config.yaml",
+					ContainerID:   "container_id",
+					Host:          "127.0.0.1",
+					Transport:     ProtocolTCP,
+					Labels: map[string]string{
+						"label_key": "label_val",
+					},
+				},
+			},
+			want: EndpointEnv{
+				"type": map[string]interface{}{
+					"port":        false,
+					"pod":         false,
+					"container":   true,
+					"k8s_node":    false,
+					"k8s_service": false,
+				},
+				"endpoint":       "127.0.0.1:2379",
+				"name":           "otel-collector",
+				"image":          "otel-collector-image",
+				"port":           uint16(2379),
+				"alternate_port": uint16(2380),
+				"command":        "./cmd --config config.yaml",
+				"container_id":   "container_id",
+				"host":           "127.0.0.1",
+				"transport":      ProtocolTCP,
+				"labels": map[string]string{
+					"label_key": "label_val",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "K8s node",
+			endpoint: Endpoint{
+				ID:     EndpointID("k8s_node_id"),
+				Target: "10.0.0.1:10250",
+				Details: K8sNode{
+					Name:                "node-1",
+					UID:                 "node-uid",
+					Labels:              map[string]string{"label_key": "label_val"},
+					Annotations:         map[string]string{"annotation_1": "value_1"},
+					InternalIP:          "10.0.0.1",
+					InternalDNS:         "node-1.internal",
+					Hostname:            "node-1",
+					ExternalIP:          "1.2.3.4",
+					ExternalDNS:         "node-1.example.com",
+					KubeletEndpointPort: 10250,
+				},
+			},
+			want: EndpointEnv{
+				"type": map[string]interface{}{
+					"port":        false,
+					"pod":         false,
+					"container":   false,
+					"k8s_node":    true,
+					"k8s_service": false,
+				},
+				"endpoint":              "10.0.0.1:10250",
+				"name":                  "node-1",
+				"uid":                   "node-uid",
+				"labels":                map[string]string{"label_key": "label_val"},
+				"annotations":           map[string]string{"annotation_1": "value_1"},
+				"internal_ip":           "10.0.0.1",
+				"internal_dns":          "node-1.internal",
+				"hostname":              "node-1",
+				"external_ip":           "1.2.3.4",
+				"external_dns":          "node-1.example.com",
+				"kubelet_endpoint_port": uint16(10250),
+			},
+			wantErr: false,
+		},
+		{
+			name: "K8s service",
+			endpoint: Endpoint{
+				ID:     EndpointID("k8s_service_id"),
+				Target: "10.96.0.10:9153",
+				Details: K8sService{
+					Name:        "coredns",
+					UID:         "service-uid",
+					Namespace:   "kube-system",
+					Labels:      map[string]string{"label_key": "label_val"},
+					Annotations: map[string]string{"annotation_1": "value_1"},
+					Selector:    map[string]string{"k8s-app": "kube-dns"},
+					ServiceType: "ClusterIP",
+					ClusterIP:   "10.96.0.10",
+					PortName:    "metrics",
+					Port:        9153,
+					Transport:   ProtocolTCP,
+				},
+			},
+			want: EndpointEnv{
+				"type": map[string]interface{}{
+					"port":        false,
+					"pod":         false,
+					"container":   false,
+					"k8s_node":    false,
+					"k8s_service": true,
+				},
+				"endpoint":     "10.96.0.10:9153",
+				"name":         "coredns",
+				"uid":          "service-uid",
+				"namespace":    "kube-system",
+				"labels":       map[string]string{"label_key": "label_val"},
+				"annotations":  map[string]string{"annotation_1": "value_1"},
+				"selector":     map[string]string{"k8s-app": "kube-dns"},
+				"service_type": "ClusterIP",
+				"cluster_ip":   "10.96.0.10",
+				"port_name":    "metrics",
+				"port":         uint16(9153),
+				"transport":    ProtocolTCP,
+			},
+			wantErr: false,
+		},
 		{
 			name: "Unsupported endpoint",
 			endpoint: Endpoint{
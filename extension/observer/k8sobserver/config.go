@@ -15,6 +15,8 @@
 package k8sobserver
 
 import (
+	"errors"
+
 	"go.opentelemetry.io/collector/config/configmodels"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/k8sconfig"
@@ -36,4 +38,22 @@ type Config struct {
 	//
 	// Then set this value to ${K8S_NODE_NAME} in the configuration.
 	Node string `mapstructure:"node"`
+
+	// ObservePods determines whether to report pod and port endpoints. If Node is set, only
+	// the pods scheduled on this node are observed. Default is true.
+	ObservePods bool `mapstructure:"observe_pods"`
+	// ObserveNodes determines whether to report node endpoints. If Node is set, only this
+	// node is observed. Default is false.
+	ObserveNodes bool `mapstructure:"observe_nodes"`
+	// ObserveServices determines whether to report service endpoints, for the services of
+	// all namespaces. Default is false.
+	ObserveServices bool `mapstructure:"observe_services"`
+}
+
+// Validate checks that at least one kind of object is observed.
+func (c *Config) Validate() error {
+	if !c.ObservePods && !c.ObserveNodes && !c.ObserveServices {
+		return errors.New("one of observe_pods, observe_nodes or observe_services must be true")
+	}
+	return nil
 }
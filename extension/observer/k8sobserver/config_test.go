@@ -50,8 +50,35 @@ func TestLoadConfig(t *testing.T) {
 				TypeVal: "k8s_observer",
 				NameVal: "k8s_observer/1",
 			},
-			Node:      "node-1",
-			APIConfig: k8sconfig.APIConfig{AuthType: k8sconfig.AuthTypeKubeConfig},
+			Node:        "node-1",
+			APIConfig:   k8sconfig.APIConfig{AuthType: k8sconfig.AuthTypeKubeConfig},
+			ObservePods: true,
 		},
 		ext1)
 }
+
+func TestLoadConfigObserveResources(t *testing.T) {
+	factories, err := componenttest.ExampleComponents()
+	assert.NoError(t, err)
+
+	factory := &Factory{}
+	factories.Extensions[typeStr] = factory
+	cfg, err := configtest.LoadConfigFile(t, path.Join(".", "testdata", "observe-resources.yaml"), factories)
+
+	require.Nil(t, err)
+	require.NotNil(t, cfg)
+
+	ext := cfg.Extensions["k8s_observer/nodes_and_services"]
+	assert.Equal(t,
+		&Config{
+			ExtensionSettings: configmodels.ExtensionSettings{
+				TypeVal: "k8s_observer",
+				NameVal: "k8s_observer/nodes_and_services",
+			},
+			APIConfig:       k8sconfig.APIConfig{AuthType: k8sconfig.AuthTypeKubeConfig},
+			ObserveNodes:    true,
+			ObserveServices: true,
+		},
+		ext)
+	assert.NoError(t, ext.(*Config).Validate())
+}
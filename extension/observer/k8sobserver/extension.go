@@ -19,21 +19,29 @@ import (
 
 	"go.opentelemetry.io/collector/component"
 	"go.uber.org/zap"
-	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/cache"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
 )
 
 type k8sObserver struct {
-	logger   *zap.Logger
-	informer cache.SharedInformer
-	stop     chan struct{}
-	config   *Config
+	logger    *zap.Logger
+	informers []cache.SharedInformer
+	stop      chan struct{}
+	config    *Config
+}
+
+// listWatch lists and watches the objects of a given type.
+type listWatch struct {
+	objType runtime.Object
+	lw      cache.ListerWatcher
 }
 
 func (k *k8sObserver) Start(ctx context.Context, host component.Host) error {
-	go k.informer.Run(k.stop)
+	for _, informer := range k.informers {
+		go informer.Run(k.stop)
+	}
 	return nil
 }
 
@@ -46,11 +54,17 @@ var _ (component.ServiceExtension) = (*k8sObserver)(nil)
 
 // ListAndWatch notifies watcher with the current state and sends subsequent state changes.
 func (k *k8sObserver) ListAndWatch(listener observer.Notify) {
-	k.informer.AddEventHandler(&handler{watcher: listener, idNamespace: k.config.Name()})
+	for _, informer := range k.informers {
+		informer.AddEventHandler(&handler{watcher: listener, idNamespace: k.config.Name()})
+	}
 }
 
-// newObserver creates a new k8s observer extension.
-func newObserver(logger *zap.Logger, config *Config, listWatch cache.ListerWatcher) (component.ServiceExtension, error) {
-	informer := cache.NewSharedInformer(listWatch, &v1.Pod{}, 0)
-	return &k8sObserver{logger: logger, informer: informer, stop: make(chan struct{}), config: config}, nil
+// newObserver creates a new k8s observer extension watching an informer for
+// each of the given list watches.
+func newObserver(logger *zap.Logger, config *Config, listWatches ...listWatch) (component.ServiceExtension, error) {
+	informers := make([]cache.SharedInformer, 0, len(listWatches))
+	for _, lw := range listWatches {
+		informers = append(informers, cache.NewSharedInformer(lw.lw, lw.objType, 0))
+	}
+	return &k8sObserver{logger: logger, informers: informers, stop: make(chan struct{}), config: config}, nil
 }
@@ -22,6 +22,8 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.uber.org/zap"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
 	framework "k8s.io/client-go/tools/cache/testing"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
@@ -30,7 +32,7 @@ import (
 func TestNewExtension(t *testing.T) {
 	listWatch := framework.NewFakeControllerSource()
 	factory := &Factory{}
-	ext, err := newObserver(zap.NewNop(), factory.CreateDefaultConfig().(*Config), listWatch)
+	ext, err := newObserver(zap.NewNop(), factory.CreateDefaultConfig().(*Config), podListWatch(listWatch))
 	require.NoError(t, err)
 	require.NotNil(t, ext)
 }
@@ -38,7 +40,7 @@ func TestNewExtension(t *testing.T) {
 func TestExtensionObserve(t *testing.T) {
 	listWatch := framework.NewFakeControllerSource()
 	factory := &Factory{}
-	ext, err := newObserver(zap.NewNop(), factory.CreateDefaultConfig().(*Config), listWatch)
+	ext, err := newObserver(zap.NewNop(), factory.CreateDefaultConfig().(*Config), podListWatch(listWatch))
 	require.NoError(t, err)
 	require.NotNil(t, ext)
 	obs := ext.(*k8sObserver)
@@ -85,3 +87,45 @@ func TestExtensionObserve(t *testing.T) {
 
 	require.NoError(t, ext.Shutdown(context.Background()))
 }
+
+func TestExtensionObserveNodesAndServices(t *testing.T) {
+	nodeListWatch := framework.NewFakeControllerSource()
+	serviceListWatch := framework.NewFakeControllerSource()
+	factory := &Factory{}
+	ext, err := newObserver(zap.NewNop(), factory.CreateDefaultConfig().(*Config),
+		listWatch{objType: &v1.Node{}, lw: nodeListWatch},
+		listWatch{objType: &v1.Service{}, lw: serviceListWatch},
+	)
+	require.NoError(t, err)
+	obs := ext.(*k8sObserver)
+
+	nodeListWatch.Add(node1)
+	serviceListWatch.Add(service1)
+
+	require.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+
+	sink := &endpointSink{}
+	obs.ListAndWatch(sink)
+
+	assertSink(t, sink, func() bool {
+		return len(sink.added) == 3
+	})
+
+	var targets []string
+	for _, e := range sink.added {
+		targets = append(targets, e.Target)
+	}
+	assert.ElementsMatch(t, []string{"10.0.0.1:10250", "10.96.0.10:53", "10.96.0.10:9153"}, targets)
+
+	serviceListWatch.Delete(service1)
+
+	assertSink(t, sink, func() bool {
+		return len(sink.removed) == 2
+	})
+
+	require.NoError(t, ext.Shutdown(context.Background()))
+}
+
+func podListWatch(lw cache.ListerWatcher) listWatch {
+	return listWatch{objType: &v1.Pod{}, lw: lw}
+}
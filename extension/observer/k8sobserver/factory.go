@@ -54,7 +54,8 @@ func (f *Factory) CreateDefaultConfig() configmodels.Extension {
 			TypeVal: typeStr,
 			NameVal: string(typeStr),
 		},
-		APIConfig: k8sconfig.APIConfig{AuthType: k8sconfig.AuthTypeServiceAccount},
+		APIConfig:   k8sconfig.APIConfig{AuthType: k8sconfig.AuthTypeServiceAccount},
+		ObservePods: true,
 	}
 }
 
@@ -65,17 +66,49 @@ func (f *Factory) CreateExtension(
 	cfg configmodels.Extension,
 ) (component.ServiceExtension, error) {
 	config := cfg.(*Config)
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
 
 	clientset, err := f.createK8sClientset(config.APIConfig)
 	if err != nil {
 		return nil, err
 	}
 
-	listWatch := cache.NewListWatchFromClient(
-		clientset.CoreV1().RESTClient(), "pods", v1.NamespaceAll,
-		fields.OneTermEqualSelector("spec.nodeName", config.Node))
+	var listWatches []listWatch
+	if config.ObservePods {
+		listWatches = append(listWatches, listWatch{
+			objType: &v1.Pod{},
+			lw: cache.NewListWatchFromClient(
+				clientset.CoreV1().RESTClient(), "pods", v1.NamespaceAll,
+				fields.OneTermEqualSelector("spec.nodeName", config.Node)),
+		})
+	}
+	if config.ObserveNodes {
+		listWatches = append(listWatches, listWatch{
+			objType: &v1.Node{},
+			lw: cache.NewListWatchFromClient(
+				clientset.CoreV1().RESTClient(), "nodes", v1.NamespaceAll,
+				nodeSelector(config.Node)),
+		})
+	}
+	if config.ObserveServices {
+		listWatches = append(listWatches, listWatch{
+			objType: &v1.Service{},
+			lw: cache.NewListWatchFromClient(
+				clientset.CoreV1().RESTClient(), "services", v1.NamespaceAll, fields.Everything()),
+		})
+	}
+
+	return newObserver(params.Logger, config, listWatches...)
+}
 
-	return newObserver(params.Logger, config, listWatch)
+// nodeSelector selects the given node, or all nodes if no node is set.
+func nodeSelector(node string) fields.Selector {
+	if node == "" {
+		return fields.Everything()
+	}
+	return fields.OneTermEqualSelector("metadata.name", node)
 }
 
 // NewFactory should be called to create a factory with default values.
@@ -47,7 +47,8 @@ func TestFactory_CreateDefaultConfig(t *testing.T) {
 			TypeVal: typeStr,
 			NameVal: string(typeStr),
 		},
-		APIConfig: k8sconfig.APIConfig{AuthType: k8sconfig.AuthTypeServiceAccount},
+		APIConfig:   k8sconfig.APIConfig{AuthType: k8sconfig.AuthTypeServiceAccount},
+		ObservePods: true,
 	},
 		cfg)
 
@@ -66,6 +67,28 @@ func TestFactory_CreateExtension(t *testing.T) {
 	require.NotNil(t, ext)
 }
 
+func TestFactory_CreateExtensionAllObjects(t *testing.T) {
+	factory := Factory{createK8sClientset: nilClient}
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.ObserveNodes = true
+	cfg.ObserveServices = true
+
+	ext, err := factory.CreateExtension(context.Background(), component.ExtensionCreateParams{Logger: zap.NewNop()}, cfg)
+	require.NoError(t, err)
+	require.NotNil(t, ext)
+	assert.Len(t, ext.(*k8sObserver).informers, 3)
+}
+
+func TestFactory_CreateExtensionNoObjects(t *testing.T) {
+	factory := Factory{createK8sClientset: nilClient}
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.ObservePods = false
+
+	ext, err := factory.CreateExtension(context.Background(), component.ExtensionCreateParams{Logger: zap.NewNop()}, cfg)
+	assert.EqualError(t, err, "one of observe_pods, observe_nodes or observe_services must be true")
+	assert.Nil(t, ext)
+}
+
 func TestNewFactory(t *testing.T) {
 	f := NewFactory()
 	require.IsType(t, f, &Factory{})
@@ -32,13 +32,25 @@ type handler struct {
 	watcher observer.Notify
 }
 
-// OnAdd is called in response to a pod being added.
+// OnAdd is called in response to an object being added.
 func (h *handler) OnAdd(obj interface{}) {
-	pod, ok := obj.(*v1.Pod)
-	if !ok {
-		return
+	if endpoints := h.convertToEndpoints(obj); len(endpoints) > 0 {
+		h.watcher.OnAdd(endpoints)
 	}
-	h.watcher.OnAdd(h.convertPodToEndpoints(pod))
+}
+
+// convertToEndpoints converts a pod, node or service into a slice of
+// endpoints. Other objects are ignored.
+func (h *handler) convertToEndpoints(obj interface{}) []observer.Endpoint {
+	switch o := obj.(type) {
+	case *v1.Pod:
+		return h.convertPodToEndpoints(o)
+	case *v1.Node:
+		return h.convertNodeToEndpoints(o)
+	case *v1.Service:
+		return h.convertServiceToEndpoints(o)
+	}
+	return nil
 }
 
 // convertPodToEndpoints converts a pod instance into a slice of endpoints. The endpoints
@@ -107,32 +119,113 @@ func getTransport(protocol v1.Protocol) observer.Transport {
 	return observer.ProtocolUnknown
 }
 
-// OnUpdate is called in response to an existing pod changing.
-func (h *handler) OnUpdate(oldObj, newObj interface{}) {
-	oldPod, ok := oldObj.(*v1.Pod)
-	if !ok {
-		return
+// convertNodeToEndpoints converts a node instance into a node endpoint whose
+// target is the Kubelet endpoint of the node.
+func (h *handler) convertNodeToEndpoints(node *v1.Node) []observer.Endpoint {
+	nodeID := observer.EndpointID(fmt.Sprintf("%s/%s", h.idNamespace, node.UID))
+
+	details := observer.K8sNode{
+		Name:                node.Name,
+		UID:                 string(node.UID),
+		Labels:              node.Labels,
+		Annotations:         node.Annotations,
+		KubeletEndpointPort: uint16(node.Status.DaemonEndpoints.KubeletEndpoint.Port),
 	}
-	newPod, ok := newObj.(*v1.Pod)
-	if !ok {
-		return
+	for _, address := range node.Status.Addresses {
+		switch address.Type {
+		case v1.NodeInternalIP:
+			details.InternalIP = address.Address
+		case v1.NodeInternalDNS:
+			details.InternalDNS = address.Address
+		case v1.NodeHostName:
+			details.Hostname = address.Address
+		case v1.NodeExternalIP:
+			details.ExternalIP = address.Address
+		case v1.NodeExternalDNS:
+			details.ExternalDNS = address.Address
+		}
+	}
+
+	// Use the first available address, preferring the ones reachable from
+	// within the cluster.
+	var host string
+	for _, address := range []string{
+		details.InternalIP, details.InternalDNS, details.Hostname, details.ExternalIP, details.ExternalDNS,
+	} {
+		if address != "" {
+			host = address
+			break
+		}
+	}
+	if host == "" {
+		return nil
+	}
+
+	target := host
+	if details.KubeletEndpointPort != 0 {
+		target = fmt.Sprintf("%s:%d", host, details.KubeletEndpointPort)
 	}
 
+	return []observer.Endpoint{{
+		ID:      nodeID,
+		Target:  target,
+		Details: details,
+	}}
+}
+
+// convertServiceToEndpoints converts a service instance into an endpoint for
+// each of its ports. The target of the endpoints is the cluster IP of the
+// service, or its DNS name if it is headless.
+func (h *handler) convertServiceToEndpoints(service *v1.Service) []observer.Endpoint {
+	serviceID := fmt.Sprintf("%s/%s", h.idNamespace, service.UID)
+
+	host := service.Spec.ClusterIP
+	if host == "" || host == v1.ClusterIPNone {
+		host = fmt.Sprintf("%s.%s.svc", service.Name, service.Namespace)
+	}
+
+	endpoints := make([]observer.Endpoint, 0, len(service.Spec.Ports))
+	for _, port := range service.Spec.Ports {
+		endpoints = append(endpoints, observer.Endpoint{
+			ID: observer.EndpointID(
+				fmt.Sprintf("%s/%s(%d)", serviceID, port.Name, port.Port),
+			),
+			Target: fmt.Sprintf("%s:%d", host, port.Port),
+			Details: observer.K8sService{
+				Name:        service.Name,
+				UID:         string(service.UID),
+				Namespace:   service.Namespace,
+				Labels:      service.Labels,
+				Annotations: service.Annotations,
+				Selector:    service.Spec.Selector,
+				ServiceType: string(service.Spec.Type),
+				ClusterIP:   service.Spec.ClusterIP,
+				PortName:    port.Name,
+				Port:        uint16(port.Port),
+				Transport:   getTransport(port.Protocol),
+			},
+		})
+	}
+	return endpoints
+}
+
+// OnUpdate is called in response to an existing object changing.
+func (h *handler) OnUpdate(oldObj, newObj interface{}) {
 	oldEndpoints := map[observer.EndpointID]observer.Endpoint{}
 	newEndpoints := map[observer.EndpointID]observer.Endpoint{}
 
-	// Convert pods to endpoints and map by ID for easier lookup.
-	for _, e := range h.convertPodToEndpoints(oldPod) {
+	// Convert objects to endpoints and map by ID for easier lookup.
+	for _, e := range h.convertToEndpoints(oldObj) {
 		oldEndpoints[e.ID] = e
 	}
-	for _, e := range h.convertPodToEndpoints(newPod) {
+	for _, e := range h.convertToEndpoints(newObj) {
 		newEndpoints[e.ID] = e
 	}
 
 	var removedEndpoints, updatedEndpoints, addedEndpoints []observer.Endpoint
 
-	// Find endpoints that are present in oldPod and newPod and see if they've
-	// changed. Otherwise if it wasn't in oldPod it's a new endpoint.
+	// Find endpoints that are present in oldObj and newObj and see if they've
+	// changed. Otherwise if it wasn't in oldObj it's a new endpoint.
 	for _, e := range newEndpoints {
 		if existing, ok := oldEndpoints[e.ID]; ok {
 			if !reflect.DeepEqual(existing, e) {
@@ -143,7 +236,7 @@ func (h *handler) OnUpdate(oldObj, newObj interface{}) {
 		}
 	}
 
-	// If an endpoint is present in the oldPod but not in the newPod then
+	// If an endpoint is present in the oldObj but not in the newObj then
 	// send as removed.
 	for _, e := range oldEndpoints {
 		if _, ok := newEndpoints[e.ID]; !ok {
@@ -163,25 +256,20 @@ func (h *handler) OnUpdate(oldObj, newObj interface{}) {
 		h.watcher.OnAdd(addedEndpoints)
 	}
 
-	// TODO: can changes be missed where a pod is deleted but we don't
+	// TODO: can changes be missed where an object is deleted but we don't
 	// send remove notifications for some of its endpoints? If not provable
-	// then maybe keep track of pod -> endpoint association to be sure
+	// then maybe keep track of object -> endpoint association to be sure
 	// they are all cleaned up.
 }
 
-// OnDelete is called in response to a pod being deleted.
+// OnDelete is called in response to an object being deleted.
 func (h *handler) OnDelete(obj interface{}) {
-	var pod *v1.Pod
-	switch o := obj.(type) {
-	case *cache.DeletedFinalStateUnknown:
-		// Assuming we never saw the pod state where new endpoints would have been created
+	if o, ok := obj.(*cache.DeletedFinalStateUnknown); ok {
+		// Assuming we never saw the object state where new endpoints would have been created
 		// to begin with it seems that we can't leak endpoints here.
-		pod = o.Obj.(*v1.Pod)
-	case *v1.Pod:
-		pod = o
+		obj = o.Obj
 	}
-	if pod == nil {
-		return
+	if endpoints := h.convertToEndpoints(obj); len(endpoints) > 0 {
+		h.watcher.OnRemove(endpoints)
 	}
-	h.watcher.OnRemove(h.convertPodToEndpoints(pod))
 }
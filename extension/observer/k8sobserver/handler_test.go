@@ -18,6 +18,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
 )
@@ -134,3 +137,99 @@ func TestEndpointsChanged(t *testing.T) {
 				Transport: observer.ProtocolTCP}},
 	}, sink.changed)
 }
+
+func TestNodeEndpoints(t *testing.T) {
+	sink := endpointSink{}
+	h := handler{
+		idNamespace: "test-1",
+		watcher:     &sink,
+	}
+	h.OnAdd(node1)
+	assert.Equal(t, []observer.Endpoint{
+		{
+			ID:     "test-1/node-1-UID",
+			Target: "10.0.0.1:10250",
+			Details: observer.K8sNode{
+				Name:                "node-1",
+				UID:                 "node-1-UID",
+				Labels:              map[string]string{"zone": "us-east-1a"},
+				InternalIP:          "10.0.0.1",
+				Hostname:            "node-1",
+				ExternalIP:          "1.2.3.4",
+				KubeletEndpointPort: 10250,
+			},
+		}}, sink.added)
+
+	// Label changed.
+	updatedNode := node1.DeepCopy()
+	updatedNode.Labels["zone"] = "us-east-1b"
+	h.OnUpdate(node1, updatedNode)
+	require.Len(t, sink.changed, 1)
+	assert.Equal(t, "us-east-1b", sink.changed[0].Details.(observer.K8sNode).Labels["zone"])
+
+	h.OnDelete(&cache.DeletedFinalStateUnknown{Obj: updatedNode})
+	require.Len(t, sink.removed, 1)
+	assert.Equal(t, observer.EndpointID("test-1/node-1-UID"), sink.removed[0].ID)
+}
+
+func TestServiceEndpoints(t *testing.T) {
+	sink := endpointSink{}
+	h := handler{
+		idNamespace: "test-1",
+		watcher:     &sink,
+	}
+	h.OnAdd(service1)
+
+	details := observer.K8sService{
+		Name:        "kube-dns",
+		UID:         "kube-dns-UID",
+		Namespace:   "kube-system",
+		Labels:      map[string]string{"k8s-app": "kube-dns"},
+		Selector:    map[string]string{"k8s-app": "kube-dns"},
+		ServiceType: "ClusterIP",
+		ClusterIP:   "10.96.0.10",
+	}
+	dnsDetails := details
+	dnsDetails.PortName = "dns"
+	dnsDetails.Port = 53
+	dnsDetails.Transport = observer.ProtocolUDP
+	metricsDetails := details
+	metricsDetails.PortName = "metrics"
+	metricsDetails.Port = 9153
+	metricsDetails.Transport = observer.ProtocolTCP
+
+	assert.ElementsMatch(t, []observer.Endpoint{
+		{ID: "test-1/kube-dns-UID/dns(53)", Target: "10.96.0.10:53", Details: dnsDetails},
+		{ID: "test-1/kube-dns-UID/metrics(9153)", Target: "10.96.0.10:9153", Details: metricsDetails},
+	}, sink.added)
+
+	// Headless services are reached through their DNS name.
+	headless := service1.DeepCopy()
+	headless.Spec.ClusterIP = v1.ClusterIPNone
+	endpoints := h.convertToEndpoints(headless)
+	require.Len(t, endpoints, 2)
+	assert.Equal(t, "kube-dns.kube-system.svc:53", endpoints[0].Target)
+
+	// Port removed.
+	sink = endpointSink{}
+	onePort := service1.DeepCopy()
+	onePort.Spec.Ports = onePort.Spec.Ports[1:]
+	h.OnUpdate(service1, onePort)
+	assert.Nil(t, sink.added)
+	assert.Nil(t, sink.changed)
+	assert.Equal(t, []observer.Endpoint{
+		{ID: "test-1/kube-dns-UID/dns(53)", Target: "10.96.0.10:53", Details: dnsDetails},
+	}, sink.removed)
+}
+
+func TestUnsupportedObject(t *testing.T) {
+	sink := endpointSink{}
+	h := handler{
+		idNamespace: "test-1",
+		watcher:     &sink,
+	}
+	h.OnAdd(&v1.ConfigMap{})
+	h.OnDelete(&v1.ConfigMap{})
+	assert.Nil(t, sink.added)
+	assert.Nil(t, sink.removed)
+}
@@ -110,3 +110,45 @@ var podWithNamedPorts = func() *v1.Pod {
 	}
 	return pod
 }()
+
+var node1 = &v1.Node{
+	ObjectMeta: metav1.ObjectMeta{
+		Name: "node-1",
+		UID:  types.UID("node-1-UID"),
+		Labels: map[string]string{
+			"zone": "us-east-1a",
+		},
+	},
+	Status: v1.NodeStatus{
+		Addresses: []v1.NodeAddress{
+			{Type: v1.NodeHostName, Address: "node-1"},
+			{Type: v1.NodeInternalIP, Address: "10.0.0.1"},
+			{Type: v1.NodeExternalIP, Address: "1.2.3.4"},
+		},
+		DaemonEndpoints: v1.NodeDaemonEndpoints{
+			KubeletEndpoint: v1.DaemonEndpoint{Port: 10250},
+		},
+	},
+}
+
+var service1 = &v1.Service{
+	ObjectMeta: metav1.ObjectMeta{
+		Namespace: "kube-system",
+		Name:      "kube-dns",
+		UID:       types.UID("kube-dns-UID"),
+		Labels: map[string]string{
+			"k8s-app": "kube-dns",
+		},
+	},
+	Spec: v1.ServiceSpec{
+		Type:      v1.ServiceTypeClusterIP,
+		ClusterIP: "10.96.0.10",
+		Selector: map[string]string{
+			"k8s-app": "kube-dns",
+		},
+		Ports: []v1.ServicePort{
+			{Name: "dns", Port: 53, Protocol: v1.ProtocolUDP},
+			{Name: "metrics", Port: 9153, Protocol: v1.ProtocolTCP},
+		},
+	},
+}
@@ -0,0 +1,76 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsutil
+
+// CredentialsSettings defines how the AWS credentials of a component are
+// obtained. It is meant to be squashed into the component configuration.
+type CredentialsSettings struct {
+	// Profile is the name of the profile to load from the shared credentials
+	// file. When empty, AWS_PROFILE or the default profile is used.
+	Profile string `mapstructure:"profile"`
+
+	// CredentialsFile is the path of the shared credentials file. When empty,
+	// AWS_SHARED_CREDENTIALS_FILE or ~/.aws/credentials is used.
+	CredentialsFile string `mapstructure:"credentials_file"`
+
+	// RoleARN is the IAM role assumed through AWS STS using the credentials
+	// found above.
+	RoleARN string `mapstructure:"role_arn"`
+
+	// ExternalID is passed to AWS STS when assuming RoleARN, as required by
+	// roles shared with third parties.
+	ExternalID string `mapstructure:"external_id"`
+
+	// WebIdentityTokenFile is the path of an OIDC token exchanged for
+	// credentials of RoleARN, e.g. the token mounted by IAM roles for
+	// service accounts on EKS.
+	WebIdentityTokenFile string `mapstructure:"web_identity_token_file"`
+
+	// STSEndpoint overrides the AWS STS endpoint used to assume RoleARN.
+	// When empty, the regional endpoint of the configured region is used.
+	STSEndpoint string `mapstructure:"sts_endpoint"`
+}
+
+// SessionSettings holds the options used to build the AWS session and
+// client configuration of a component.
+type SessionSettings struct {
+	CredentialsSettings
+
+	// Region requests are sent to. When empty, it is looked up from the
+	// environment, then from the ECS and EC2 metadata unless LocalMode is set.
+	Region string
+
+	// Endpoint overrides the endpoint of the AWS service.
+	Endpoint string
+
+	// LocalMode skips the ECS and EC2 metadata lookups.
+	LocalMode bool
+
+	// MaxRetries is the maximum number of retries of a failed request.
+	MaxRetries int
+
+	// NumberOfWorkers is the number of idle connections kept per host.
+	NumberOfWorkers int
+
+	// RequestTimeoutSeconds is the number of seconds before a request times out.
+	RequestTimeoutSeconds int
+
+	// NoVerifySSL disables TLS certificate verification.
+	NoVerifySSL bool
+
+	// ProxyAddress is the HTTP proxy requests go through. When empty,
+	// HTTPS_PROXY is used.
+	ProxyAddress string
+}
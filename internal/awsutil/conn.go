@@ -0,0 +1,290 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package awsutil builds the AWS sessions, credentials and HTTP transports
+// shared by the AWS components.
+package awsutil
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/ec2metadata"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"go.uber.org/zap"
+)
+
+const (
+	awsRegionEnvVar                   = "AWS_REGION"
+	awsDefaultRegionEnvVar            = "AWS_DEFAULT_REGION"
+	ecsContainerMetadataEnabledEnvVar = "ECS_ENABLE_CONTAINER_METADATA"
+	ecsMetadataFileEnvVar             = "ECS_CONTAINER_METADATA_FILE"
+
+	stsEndpointPrefix         = "https://sts."
+	stsEndpointSuffix         = ".amazonaws.com"
+	stsAwsCnPartitionIDSuffix = ".amazonaws.com.cn" // AWS China partition.
+)
+
+var (
+	errWebIdentityWithoutRole    = errors.New("web_identity_token_file requires role_arn to be set")
+	errWebIdentityWithExternalID = errors.New("external_id cannot be used with web_identity_token_file")
+)
+
+// ConnAttr wraps the calls reaching out to AWS while building a session so
+// that they can be replaced in tests.
+type ConnAttr interface {
+	NewAWSSession(logger *zap.Logger, settings *CredentialsSettings, region string) (*session.Session, error)
+	GetEC2Region(s *session.Session) (string, error)
+}
+
+// Conn implements ConnAttr.
+type Conn struct{}
+
+// GetEC2Region returns the region of the EC2 instance the collector runs on.
+func (c *Conn) GetEC2Region(s *session.Session) (string, error) {
+	return ec2metadata.New(s).Region()
+}
+
+// NewAWSSession returns a session using the credentials of the configured
+// profile and credentials file, or of the default credential chain. When a
+// role is configured, it is assumed through AWS STS on top of them.
+func (c *Conn) NewAWSSession(logger *zap.Logger, settings *CredentialsSettings, region string) (*session.Session, error) {
+	if settings.RoleARN == "" && settings.WebIdentityTokenFile != "" {
+		return nil, errWebIdentityWithoutRole
+	}
+	if settings.ExternalID != "" && settings.WebIdentityTokenFile != "" {
+		return nil, errWebIdentityWithExternalID
+	}
+
+	sess, err := newSession(settings)
+	if err != nil {
+		logger.Error("Error in creating session object", zap.Error(err))
+		return nil, err
+	}
+	if settings.RoleARN == "" {
+		return sess, nil
+	}
+
+	calls := &stsCalls{log: logger, getSTSCredsFromRegionEndpoint: getSTSCredsFromRegionEndpoint}
+	stsCreds, err := calls.getCreds(sess, region, settings)
+	if err != nil {
+		return nil, err
+	}
+	return sess.Copy(&aws.Config{Credentials: stsCreds}), nil
+}
+
+func newSession(settings *CredentialsSettings) (*session.Session, error) {
+	opts := session.Options{Profile: settings.Profile}
+	if settings.CredentialsFile != "" {
+		opts.SharedConfigFiles = []string{settings.CredentialsFile}
+	}
+	return session.NewSessionWithOptions(opts)
+}
+
+// GetAWSConfigSession returns the AWS client configuration and session built
+// from the given settings.
+func GetAWSConfigSession(logger *zap.Logger, cn ConnAttr, settings *SessionSettings) (*aws.Config, *session.Session, error) {
+	http, err := newHTTPClient(logger, settings.NumberOfWorkers, settings.RequestTimeoutSeconds, settings.NoVerifySSL,
+		settings.ProxyAddress)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	awsRegion, err := getRegion(logger, cn, settings)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sess, err := cn.NewAWSSession(logger, &settings.CredentialsSettings, awsRegion)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &aws.Config{
+		Region:                        aws.String(awsRegion),
+		DisableParamValidation:        aws.Bool(true),
+		MaxRetries:                    aws.Int(settings.MaxRetries),
+		Endpoint:                      aws.String(settings.Endpoint),
+		HTTPClient:                    http,
+		CredentialsChainVerboseErrors: aws.Bool(true),
+	}, sess, nil
+}
+
+// getRegion looks the region up from the settings, the environment, the ECS
+// metadata file and the EC2 metadata service, in that order.
+func getRegion(logger *zap.Logger, cn ConnAttr, settings *SessionSettings) (string, error) {
+	if settings.Region != "" {
+		logger.Debug("Fetched region from config file", zap.String("region", settings.Region))
+		return settings.Region, nil
+	}
+
+	regionEnv := os.Getenv(awsRegionEnvVar)
+	if regionEnv == "" {
+		regionEnv = os.Getenv(awsDefaultRegionEnvVar)
+	}
+	if regionEnv != "" {
+		logger.Debug("Fetched region from environment variables", zap.String("region", regionEnv))
+		return regionEnv, nil
+	}
+
+	if settings.LocalMode {
+		return "", errors.New("could not fetch region from config file or environment variables")
+	}
+
+	awsRegion, err := getRegionFromECSMetadata()
+	if err == nil {
+		logger.Debug("Fetched region from ECS metadata file", zap.String("region", awsRegion))
+		return awsRegion, nil
+	}
+	logger.Debug("Unable to fetch region from ECS metadata", zap.Error(err))
+
+	var sess *session.Session
+	sess, err = session.NewSession()
+	if err == nil {
+		awsRegion, err = cn.GetEC2Region(sess)
+		if err == nil {
+			logger.Debug("Fetched region from EC2 metadata", zap.String("region", awsRegion))
+			return awsRegion, nil
+		}
+		logger.Debug("Unable to fetch region from EC2 metadata", zap.Error(err))
+	}
+	return "", fmt.Errorf("could not fetch region from config file, environment variables, ecs metadata, or ec2 metadata: %w", err)
+}
+
+func getRegionFromECSMetadata() (string, error) {
+	ecsMetadataEnabled := os.Getenv(ecsContainerMetadataEnabledEnvVar)
+	ecsMetadataEnabled = strings.ToLower(ecsMetadataEnabled)
+	if ecsMetadataEnabled == "true" {
+		metadataFilePath := os.Getenv(ecsMetadataFileEnvVar)
+		metadata, err := ioutil.ReadFile(metadataFilePath)
+		if err != nil {
+			return "", fmt.Errorf("unable to open ECS metadata file, path: %s, error: %w",
+				metadataFilePath, err)
+		}
+		var dat map[string]interface{}
+		err = json.Unmarshal(metadata, &dat)
+		if err != nil {
+			return "", fmt.Errorf("invalid json in read ECS metadata file content, path: %s, error: %w",
+				metadataFilePath, err)
+		}
+		taskArn, err := arn.Parse(dat["TaskARN"].(string))
+		if err != nil {
+			return "", err
+		}
+
+		return taskArn.Region, nil
+	}
+	return "", errors.New("ECS metadata endpoint is inaccessible")
+}
+
+type stsCalls struct {
+	log                           *zap.Logger
+	getSTSCredsFromRegionEndpoint func(log *zap.Logger, sess *session.Session, region string, settings *CredentialsSettings) *credentials.Credentials
+}
+
+// getCreds gets STS credentials first from the regional endpoint, then from the primary
+// region in the respective AWS partition if the regional endpoint is disabled.
+func (s *stsCalls) getCreds(sess *session.Session, region string, settings *CredentialsSettings) (*credentials.Credentials, error) {
+	stsCred := s.getSTSCredsFromRegionEndpoint(s.log, sess, region, settings)
+	// Make explicit call to fetch credentials.
+	_, err := stsCred.Get()
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok {
+			switch aerr.Code() {
+			case sts.ErrCodeRegionDisabledException:
+				s.log.Warn("STS regional endpoint disabled. Credentials for provided RoleARN will be fetched from STS primary region endpoint instead",
+					zap.String("region", region), zap.Error(aerr))
+				stsCred, err = s.getSTSCredsFromPrimaryRegionEndpoint(sess, region, settings)
+			default:
+				return nil, fmt.Errorf("unable to handle AWS error: %w", aerr)
+			}
+		}
+	}
+	return stsCred, err
+}
+
+// getSTSCredsFromRegionEndpoint fetches STS credentials for the configured role from the
+// configured STS endpoint, or from the regional endpoint when none is configured.
+// AWS STS recommends that you provide both the Region and endpoint when you make calls to a Regional endpoint.
+// Reference: https://docs.aws.amazon.com/IAM/latest/UserGuide/id_credentials_temp_enable-regions.html#id_credentials_temp_enable-regions_writing_code
+func getSTSCredsFromRegionEndpoint(log *zap.Logger, sess *session.Session, region string,
+	settings *CredentialsSettings) *credentials.Credentials {
+	endpoint := settings.STSEndpoint
+	if endpoint == "" {
+		// if the regional endpoint is "", the STS endpoint is Global endpoint for classic regions except ap-east-1 - (HKG)
+		// for other opt-in regions, region value will create STS regional endpoint.
+		// This will only be the case if the provided region is not present in aws_regions.go
+		endpoint = getSTSRegionalEndpoint(region)
+	}
+	c := &aws.Config{Region: aws.String(region), Endpoint: &endpoint}
+	st := sts.New(sess, c)
+	log.Info("STS endpoint to use", zap.String("endpoint", st.Endpoint))
+
+	if settings.WebIdentityTokenFile != "" {
+		// AssumeRoleWithWebIdentity does not accept an external ID, which is rejected by NewAWSSession.
+		return credentials.NewCredentials(
+			stscreds.NewWebIdentityRoleProvider(st, settings.RoleARN, "", settings.WebIdentityTokenFile))
+	}
+	return stscreds.NewCredentialsWithClient(st, settings.RoleARN, func(p *stscreds.AssumeRoleProvider) {
+		if settings.ExternalID != "" {
+			p.ExternalID = aws.String(settings.ExternalID)
+		}
+	})
+}
+
+// getSTSCredsFromPrimaryRegionEndpoint fetches STS credentials for the configured role from primary region
+// endpoint in the respective partition.
+func (s *stsCalls) getSTSCredsFromPrimaryRegionEndpoint(sess *session.Session, region string,
+	settings *CredentialsSettings) (*credentials.Credentials, error) {
+	partitionID := getPartition(region)
+	switch partitionID {
+	case endpoints.AwsPartitionID:
+		return s.getSTSCredsFromRegionEndpoint(s.log, sess, endpoints.UsEast1RegionID, settings), nil
+	case endpoints.AwsCnPartitionID:
+		return s.getSTSCredsFromRegionEndpoint(s.log, sess, endpoints.CnNorth1RegionID, settings), nil
+	case endpoints.AwsUsGovPartitionID:
+		return s.getSTSCredsFromRegionEndpoint(s.log, sess, endpoints.UsGovWest1RegionID, settings), nil
+	default:
+		return nil, fmt.Errorf("unrecognized AWS region: %s, or partition: %s", region, partitionID)
+	}
+}
+
+func getSTSRegionalEndpoint(r string) string {
+	p := getPartition(r)
+
+	var e string
+	if p == endpoints.AwsPartitionID || p == endpoints.AwsUsGovPartitionID {
+		e = stsEndpointPrefix + r + stsEndpointSuffix
+	} else if p == endpoints.AwsCnPartitionID {
+		e = stsEndpointPrefix + r + stsAwsCnPartitionIDSuffix
+	}
+	return e
+}
+
+// getPartition returns the AWS Partition for the provided region.
+func getPartition(region string) string {
+	p, _ := endpoints.PartitionForRegion(endpoints.DefaultPartitions(), region)
+	return p.ID()
+}
@@ -0,0 +1,700 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsutil
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+const (
+	ec2Region = "us-west-2"
+	roleARN   = "arn:aws:iam::123456789012:role/collector"
+)
+
+type mockConn struct {
+	getEC2RegionErr error
+	sn              *session.Session
+}
+
+func (m *mockConn) GetEC2Region(s *session.Session) (string, error) {
+	if m.getEC2RegionErr != nil {
+		return "", m.getEC2RegionErr
+	}
+	return ec2Region, nil
+}
+
+func (m *mockConn) NewAWSSession(logger *zap.Logger, settings *CredentialsSettings, region string) (*session.Session, error) {
+	return m.sn, nil
+}
+
+// fakeSTS is a local stand-in for AWS STS answering AssumeRole and
+// AssumeRoleWithWebIdentity calls with fixed credentials.
+type fakeSTS struct {
+	*httptest.Server
+	// requests holds the form values of the received calls.
+	requests []map[string]string
+	// accessKeys holds the access keys the received calls were signed with.
+	accessKeys []string
+	errorCode  string
+}
+
+func newFakeSTS() *fakeSTS {
+	f := &fakeSTS{}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.handle))
+	return f
+}
+
+func (f *fakeSTS) handle(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	values := make(map[string]string, len(r.PostForm))
+	for k := range r.PostForm {
+		values[k] = r.PostForm.Get(k)
+	}
+	f.requests = append(f.requests, values)
+	auth := r.Header.Get("Authorization")
+	if i := strings.Index(auth, "Credential="); i >= 0 {
+		f.accessKeys = append(f.accessKeys, strings.SplitN(auth[i+len("Credential="):], "/", 2)[0])
+	}
+
+	if f.errorCode != "" {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprintf(w, `<ErrorResponse><Error><Type>Sender</Type><Code>%s</Code><Message>rejected</Message></Error><RequestId>1</RequestId></ErrorResponse>`,
+			f.errorCode)
+		return
+	}
+	action := values["Action"]
+	fmt.Fprintf(w, `<%[1]sResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <%[1]sResult>
+    <Credentials>
+      <AccessKeyId>ASSUMED_AKID</AccessKeyId>
+      <SecretAccessKey>ASSUMED_SECRET</SecretAccessKey>
+      <SessionToken>ASSUMED_TOKEN</SessionToken>
+      <Expiration>2100-01-01T00:00:00Z</Expiration>
+    </Credentials>
+  </%[1]sResult>
+</%[1]sResponse>`, action)
+}
+
+func logSetup() (*zap.Logger, *observer.ObservedLogs) {
+	core, recorded := observer.New(zapcore.DebugLevel)
+	return zap.New(core), recorded
+}
+
+func stashEnv() []string {
+	env := os.Environ()
+	os.Clearenv()
+
+	return env
+}
+
+func restoreEnv(env []string) {
+	os.Clearenv()
+
+	for _, e := range env {
+		p := strings.SplitN(e, "=", 2)
+		os.Setenv(p[0], p[1])
+	}
+}
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func writeCredentialsFile(t *testing.T, dir string) string {
+	return writeFile(t, dir, "credentials", `[default]
+aws_access_key_id = DEFAULT_AKID
+aws_secret_access_key = DEFAULT_SECRET
+
+[collector]
+aws_access_key_id = PROFILE_AKID
+aws_secret_access_key = PROFILE_SECRET
+`)
+}
+
+// fetch region value from environment variable
+func TestRegionFromEnv(t *testing.T) {
+	env := stashEnv()
+	defer restoreEnv(env)
+
+	logger, recordedLogs := logSetup()
+	region := "us-east-100"
+
+	os.Setenv(awsRegionEnvVar, region)
+
+	expectedSession, err := session.NewSession()
+	assert.NoError(t, err, "expectedSession should be created")
+	m := &mockConn{sn: expectedSession}
+
+	awsCfg, s, err := GetAWSConfigSession(logger, m, &SessionSettings{})
+	assert.NoError(t, err, "GetAWSConfigSession should not error out")
+	assert.Equal(t, expectedSession, s, "mock session is not overridden")
+	assert.Equal(t, region, *awsCfg.Region, "region value fetched from environment")
+
+	logs := recordedLogs.All()
+	lastEntry := logs[len(logs)-1]
+	assert.Contains(t, lastEntry.Message, "Fetched region from environment variables", "expected log message")
+	assert.Equal(t, "region", lastEntry.Context[0].Key, "expected log key")
+	assert.Equal(t, region, lastEntry.Context[0].String)
+}
+
+func TestRegionFromDefaultRegionEnv(t *testing.T) {
+	env := stashEnv()
+	defer restoreEnv(env)
+
+	os.Setenv(awsDefaultRegionEnvVar, "eu-west-100")
+
+	awsCfg, _, err := GetAWSConfigSession(zap.NewNop(), &mockConn{}, &SessionSettings{})
+	assert.NoError(t, err, "GetAWSConfigSession should not error out")
+	assert.Equal(t, "eu-west-100", *awsCfg.Region, "region value fetched from environment")
+}
+
+// Get region from the config file
+func TestRegionFromConfig(t *testing.T) {
+	env := stashEnv()
+	defer restoreEnv(env)
+	os.Setenv(awsRegionEnvVar, "us-east-100")
+
+	logger, recordedLogs := logSetup()
+
+	expectedSession, err := session.NewSession()
+	assert.NoError(t, err, "expectedSession should be created")
+	m := &mockConn{sn: expectedSession}
+
+	settings := &SessionSettings{
+		Region:     "ap-northeast-1",
+		Endpoint:   "https://xray.ap-northeast-1.amazonaws.com",
+		MaxRetries: 3,
+	}
+	awsCfg, s, err := GetAWSConfigSession(logger, m, settings)
+	assert.NoError(t, err, "GetAWSConfigSession should not error out")
+	assert.Equal(t, expectedSession, s, "mock session is not overridden")
+	assert.Equal(t, settings.Region, *awsCfg.Region, "region value fetched from the config file")
+	assert.Equal(t, settings.Endpoint, *awsCfg.Endpoint)
+	assert.Equal(t, settings.MaxRetries, *awsCfg.MaxRetries)
+	assert.NotNil(t, awsCfg.HTTPClient)
+
+	logs := recordedLogs.All()
+	lastEntry := logs[len(logs)-1]
+	assert.Contains(t, lastEntry.Message, "Fetched region from config file", "expected log message")
+	assert.Equal(t, "region", lastEntry.Context[0].Key, "expected log key")
+	assert.Equal(t, settings.Region, lastEntry.Context[0].String)
+}
+
+func TestRegionFromECS(t *testing.T) {
+	env := stashEnv()
+	defer restoreEnv(env)
+
+	os.Setenv(ecsContainerMetadataEnabledEnvVar, "true")
+	os.Setenv(ecsMetadataFileEnvVar, "testdata/ecsmetadatafile.txt")
+
+	logger, recordedLogs := logSetup()
+
+	expectedSession, err := session.NewSession()
+	assert.NoError(t, err, "expectedSession should be created")
+	m := &mockConn{sn: expectedSession}
+
+	awsCfg, s, err := GetAWSConfigSession(logger, m, &SessionSettings{})
+	assert.NoError(t, err, "GetAWSConfigSession should not error out")
+	assert.Equal(t, expectedSession, s, "mock session is not overridden")
+	assert.Equal(t, "us-west-50", *awsCfg.Region, "region value fetched from ECS metadata")
+
+	logs := recordedLogs.All()
+	lastEntry := logs[len(logs)-1]
+	assert.Contains(t, lastEntry.Message, "Fetched region from ECS metadata file", "expected log message")
+	assert.Equal(t, "region", lastEntry.Context[0].Key, "expected log key")
+	assert.Equal(t, "us-west-50", lastEntry.Context[0].String)
+}
+
+func TestRegionFromECSInvalidArn(t *testing.T) {
+	env := stashEnv()
+	defer restoreEnv(env)
+
+	os.Setenv(ecsContainerMetadataEnabledEnvVar, "true")
+	os.Setenv(ecsMetadataFileEnvVar, "testdata/ecsmetadatafileInvalidArn.txt")
+
+	logger, recordedLogs := logSetup()
+
+	expectedSession, err := session.NewSession()
+	assert.NoError(t, err, "expectedSession should be created")
+	m := &mockConn{sn: expectedSession}
+
+	_, s, err := GetAWSConfigSession(logger, m, &SessionSettings{})
+	assert.NoError(t, err, "GetAWSConfigSession should not error out")
+	assert.Equal(t, expectedSession, s, "mock session is not overridden")
+
+	logs := recordedLogs.All()
+	// assert fetching from ECS metadata failed
+	sndToLastEntry := logs[len(logs)-2]
+	assert.Contains(t, sndToLastEntry.Message, "Unable to fetch region from ECS metadata", "expected log message")
+	assert.Error(t, sndToLastEntry.Context[0].Interface.(error), "expected error")
+
+	// fall back to use EC2 meta data service
+	lastEntry := logs[len(logs)-1]
+	assert.Contains(t, lastEntry.Message, "Fetched region from EC2 metadata", "expected log message")
+}
+
+// fetch region value from ec2 meta data service
+func TestRegionFromEC2(t *testing.T) {
+	env := stashEnv()
+	defer restoreEnv(env)
+
+	logger, recordedLogs := logSetup()
+
+	expectedSession, err := session.NewSession()
+	assert.NoError(t, err, "expectedSession should be created")
+	m := &mockConn{sn: expectedSession}
+
+	awsCfg, s, err := GetAWSConfigSession(logger, m, &SessionSettings{})
+	assert.NoError(t, err, "GetAWSConfigSession should not error out")
+	assert.Equal(t, expectedSession, s, "mock session is not overridden")
+	assert.Equal(t, ec2Region, *awsCfg.Region, "region value fetched from ec2-metadata service")
+
+	logs := recordedLogs.All()
+	lastEntry := logs[len(logs)-1]
+	assert.Contains(t, lastEntry.Message, "Fetched region from EC2 metadata", "expected log message")
+	assert.Equal(t, lastEntry.Context[0].Key, "region", "expected log key")
+	assert.Equal(t, lastEntry.Context[0].String, ec2Region)
+}
+
+func TestNoRegion(t *testing.T) {
+	env := stashEnv()
+	defer restoreEnv(env)
+
+	logger, recordedLogs := logSetup()
+	m := &mockConn{
+		getEC2RegionErr: errors.New("expected getEC2Region error"),
+	}
+
+	_, _, err := GetAWSConfigSession(logger, m, &SessionSettings{})
+	assert.Error(t, err, "GetAWSConfigSession should fail")
+
+	logs := recordedLogs.All()
+	lastEntry := logs[len(logs)-1]
+	assert.Contains(t, lastEntry.Message, "Unable to fetch region from EC2 metadata", "expected log message")
+	assert.EqualError(t,
+		lastEntry.Context[0].Interface.(error),
+		m.getEC2RegionErr.Error(), "expected error")
+}
+
+// metadata must not be queried in local mode
+func TestNoRegionInLocalMode(t *testing.T) {
+	env := stashEnv()
+	defer restoreEnv(env)
+
+	os.Setenv(ecsContainerMetadataEnabledEnvVar, "true")
+	os.Setenv(ecsMetadataFileEnvVar, "testdata/ecsmetadatafile.txt")
+
+	_, _, err := GetAWSConfigSession(zap.NewNop(), &mockConn{}, &SessionSettings{LocalMode: true})
+	assert.EqualError(t, err, "could not fetch region from config file or environment variables")
+}
+
+func TestGetAWSConfigSessionInvalidProxyAddress(t *testing.T) {
+	_, _, err := GetAWSConfigSession(zap.NewNop(), &mockConn{}, &SessionSettings{
+		Region:       "us-west-2",
+		ProxyAddress: "invalid\n",
+	})
+	assert.Error(t, err, "expected error")
+	assert.Contains(t, err.Error(), "invalid control character in URL")
+}
+
+// getRegionFromECSMetadata() returns an error if ECS metadata related env is not set
+func TestNoECSMetadata(t *testing.T) {
+	env := stashEnv()
+	defer restoreEnv(env)
+	_, err := getRegionFromECSMetadata()
+	assert.EqualError(t, err, "ECS metadata endpoint is inaccessible", "expected error")
+}
+
+// getRegionFromECSMetadata() throws an error when ECS metadata file cannot be parsed as valid JSON
+func TestInvalidECSMetadata(t *testing.T) {
+	env := stashEnv()
+	defer restoreEnv(env)
+
+	os.Setenv(ecsContainerMetadataEnabledEnvVar, "true")
+	os.Setenv(ecsMetadataFileEnvVar, "testdata/ecsmetadatafileinvalid.txt")
+
+	_, err := getRegionFromECSMetadata()
+	assert.EqualError(t, err,
+		"invalid json in read ECS metadata file content, path: testdata/ecsmetadatafileinvalid.txt, error: invalid character 'i' looking for beginning of value",
+		"expected error")
+}
+
+// getRegionFromECSMetadata() throws an error and returns an empty string when ECS metadata file cannot be opened
+func TestMissingECSMetadataFile(t *testing.T) {
+	env := stashEnv()
+	defer restoreEnv(env)
+
+	os.Setenv(ecsContainerMetadataEnabledEnvVar, "true")
+	os.Setenv(ecsMetadataFileEnvVar, "testdata/doesntExist.txt")
+
+	_, err := getRegionFromECSMetadata()
+	assert.EqualError(t, err,
+		"unable to open ECS metadata file, path: testdata/doesntExist.txt, error: open testdata/doesntExist.txt: no such file or directory",
+		"expected error")
+}
+
+func TestLoadEnvConfigCreds(t *testing.T) {
+	env := stashEnv()
+	defer restoreEnv(env)
+
+	cases := struct {
+		Env map[string]string
+		Val credentials.Value
+	}{
+		Env: map[string]string{
+			"AWS_ACCESS_KEY":    "AKID",
+			"AWS_SECRET_KEY":    "SECRET",
+			"AWS_SESSION_TOKEN": "TOKEN",
+		},
+		Val: credentials.Value{
+			AccessKeyID: "AKID", SecretAccessKey: "SECRET", SessionToken: "TOKEN",
+			ProviderName: "EnvConfigCredentials",
+		},
+	}
+
+	for k, v := range cases.Env {
+		os.Setenv(k, v)
+	}
+	conn := &Conn{}
+	sess, err := conn.NewAWSSession(zap.NewNop(), &CredentialsSettings{}, "")
+	assert.NoError(t, err, "Expect no error")
+	value, err := sess.Config.Credentials.Get()
+	assert.NoError(t, err, "Expect no error")
+	assert.Equal(t, cases.Val, value, "Expect the credentials value to match")
+}
+
+func TestLoadProfileCreds(t *testing.T) {
+	env := stashEnv()
+	defer restoreEnv(env)
+
+	// explicitly configured profiles take precedence over environment credentials
+	os.Setenv("AWS_ACCESS_KEY", "AKID")
+	os.Setenv("AWS_SECRET_KEY", "SECRET")
+
+	dir, err := ioutil.TempDir("", "awsutil")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	conn := &Conn{}
+	sess, err := conn.NewAWSSession(zap.NewNop(), &CredentialsSettings{
+		Profile:         "collector",
+		CredentialsFile: writeCredentialsFile(t, dir),
+	}, "us-west-2")
+	require.NoError(t, err)
+	value, err := sess.Config.Credentials.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "PROFILE_AKID", value.AccessKeyID)
+	assert.Equal(t, "PROFILE_SECRET", value.SecretAccessKey)
+}
+
+func TestLoadMissingProfile(t *testing.T) {
+	env := stashEnv()
+	defer restoreEnv(env)
+
+	dir, err := ioutil.TempDir("", "awsutil")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	conn := &Conn{}
+	sess, err := conn.NewAWSSession(zap.NewNop(), &CredentialsSettings{
+		Profile:         "missing",
+		CredentialsFile: writeCredentialsFile(t, dir),
+	}, "us-west-2")
+	require.NoError(t, err)
+	_, err = sess.Config.Credentials.Get()
+	assert.Error(t, err, "expected error")
+}
+
+func TestAssumeRoleWithExternalID(t *testing.T) {
+	env := stashEnv()
+	defer restoreEnv(env)
+
+	dir, err := ioutil.TempDir("", "awsutil")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	stsServer := newFakeSTS()
+	defer stsServer.Close()
+
+	conn := &Conn{}
+	sess, err := conn.NewAWSSession(zap.NewNop(), &CredentialsSettings{
+		Profile:         "collector",
+		CredentialsFile: writeCredentialsFile(t, dir),
+		RoleARN:         roleARN,
+		ExternalID:      "external-id",
+		STSEndpoint:     stsServer.URL,
+	}, "us-west-2")
+	require.NoError(t, err)
+
+	value, err := sess.Config.Credentials.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "ASSUMED_AKID", value.AccessKeyID)
+	assert.Equal(t, "ASSUMED_SECRET", value.SecretAccessKey)
+	assert.Equal(t, "ASSUMED_TOKEN", value.SessionToken)
+
+	require.Len(t, stsServer.requests, 1, "credentials should be fetched once")
+	assert.Equal(t, "AssumeRole", stsServer.requests[0]["Action"])
+	assert.Equal(t, roleARN, stsServer.requests[0]["RoleArn"])
+	assert.Equal(t, "external-id", stsServer.requests[0]["ExternalId"])
+	assert.Equal(t, []string{"PROFILE_AKID"}, stsServer.accessKeys, "role should be assumed with the profile credentials")
+}
+
+func TestAssumeRoleWithWebIdentity(t *testing.T) {
+	env := stashEnv()
+	defer restoreEnv(env)
+
+	dir, err := ioutil.TempDir("", "awsutil")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	stsServer := newFakeSTS()
+	defer stsServer.Close()
+
+	conn := &Conn{}
+	sess, err := conn.NewAWSSession(zap.NewNop(), &CredentialsSettings{
+		RoleARN:              roleARN,
+		WebIdentityTokenFile: writeFile(t, dir, "token", "oidc-token"),
+		STSEndpoint:          stsServer.URL,
+	}, "us-west-2")
+	require.NoError(t, err)
+
+	value, err := sess.Config.Credentials.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "ASSUMED_AKID", value.AccessKeyID)
+
+	require.Len(t, stsServer.requests, 1, "credentials should be fetched once")
+	assert.Equal(t, "AssumeRoleWithWebIdentity", stsServer.requests[0]["Action"])
+	assert.Equal(t, roleARN, stsServer.requests[0]["RoleArn"])
+	assert.Equal(t, "oidc-token", stsServer.requests[0]["WebIdentityToken"])
+	assert.Empty(t, stsServer.accessKeys, "web identity calls are not signed")
+}
+
+func TestWebIdentityWithoutRole(t *testing.T) {
+	conn := &Conn{}
+	_, err := conn.NewAWSSession(zap.NewNop(), &CredentialsSettings{
+		WebIdentityTokenFile: "token",
+	}, "us-west-2")
+	assert.Equal(t, errWebIdentityWithoutRole, err)
+}
+
+func TestWebIdentityWithExternalID(t *testing.T) {
+	conn := &Conn{}
+	_, err := conn.NewAWSSession(zap.NewNop(), &CredentialsSettings{
+		RoleARN:              roleARN,
+		ExternalID:           "external-id",
+		WebIdentityTokenFile: "token",
+	}, "us-west-2")
+	assert.Equal(t, errWebIdentityWithExternalID, err)
+}
+
+func TestAssumeRoleRejected(t *testing.T) {
+	env := stashEnv()
+	defer restoreEnv(env)
+	os.Setenv("AWS_ACCESS_KEY", "AKID")
+	os.Setenv("AWS_SECRET_KEY", "SECRET")
+
+	stsServer := newFakeSTS()
+	defer stsServer.Close()
+	stsServer.errorCode = "AccessDenied"
+
+	conn := &Conn{}
+	_, err := conn.NewAWSSession(zap.NewNop(), &CredentialsSettings{
+		RoleARN:     roleARN,
+		STSEndpoint: stsServer.URL,
+	}, "us-west-2")
+	assert.Error(t, err, "expected error")
+	assert.Contains(t, err.Error(), "unable to handle AWS error", "expected error message")
+}
+
+func TestNewSessionCreationFailed(t *testing.T) {
+	env := stashEnv()
+	defer restoreEnv(env)
+
+	// manipulate env vars so that session.NewSession() fails
+	os.Setenv("AWS_SDK_LOAD_CONFIG", "true")
+	os.Setenv("AWS_STS_REGIONAL_ENDPOINTS", "invalid")
+
+	conn := &Conn{}
+	_, err := conn.NewAWSSession(zap.NewNop(), &CredentialsSettings{}, "dontCare")
+	assert.Error(t, err, "expected failure")
+
+	_, err = conn.NewAWSSession(zap.NewNop(), &CredentialsSettings{RoleARN: roleARN}, "us-west-2")
+	assert.Error(t, err, "expected failure")
+}
+
+func TestGetPartition(t *testing.T) {
+	p := getPartition("us-east-1")
+	assert.Equal(t, endpoints.AwsPartitionID, p)
+
+	p = getPartition("cn-north-1")
+	assert.Equal(t, endpoints.AwsCnPartitionID, p)
+
+	p = getPartition("us-gov-east-1")
+	assert.Equal(t, endpoints.AwsUsGovPartitionID, p)
+
+	p = getPartition("XYZ")
+	assert.Equal(t, "", p)
+}
+
+func TestGetSTSRegionalEndpoint(t *testing.T) {
+	p := getSTSRegionalEndpoint("us-east-1")
+	assert.Equal(t, "https://sts.us-east-1.amazonaws.com", p)
+
+	p = getSTSRegionalEndpoint("cn-north-1")
+	assert.Equal(t, "https://sts.cn-north-1.amazonaws.com.cn", p)
+
+	p = getSTSRegionalEndpoint("us-gov-east-1")
+	assert.Equal(t, "https://sts.us-gov-east-1.amazonaws.com", p)
+
+	p = getSTSRegionalEndpoint("XYZ")
+	assert.Equal(t, "", p)
+}
+
+func TestGetSTSCredsFromPrimaryRegionEndpoint(t *testing.T) {
+	settings := &CredentialsSettings{RoleARN: roleARN}
+	called := false
+	fake := &stsCalls{
+		log: zap.NewNop(),
+		getSTSCredsFromRegionEndpoint: func(_ *zap.Logger, _ *session.Session, region string, s *CredentialsSettings) *credentials.Credentials {
+			assert.Equal(t, region, endpoints.UsEast1RegionID, "expected region differs")
+			assert.Equal(t, settings, s, "expected settings differ")
+			called = true
+			return nil
+		},
+	}
+	_, err := fake.getSTSCredsFromPrimaryRegionEndpoint(nil, "us-west-2", settings)
+	assert.True(t, called, "getSTSCredsFromRegionEndpoint should be called")
+	assert.NoError(t, err, "no expected error")
+
+	called = false
+	fake.getSTSCredsFromRegionEndpoint = func(_ *zap.Logger, _ *session.Session, region string, s *CredentialsSettings) *credentials.Credentials {
+		assert.Equal(t, region, endpoints.CnNorth1RegionID, "expected region differs")
+		called = true
+		return nil
+	}
+	_, err = fake.getSTSCredsFromPrimaryRegionEndpoint(nil, "cn-north-1", settings)
+	assert.True(t, called, "getSTSCredsFromRegionEndpoint should be called")
+	assert.NoError(t, err, "no expected error")
+
+	called = false
+	fake.getSTSCredsFromRegionEndpoint = func(_ *zap.Logger, _ *session.Session, region string, s *CredentialsSettings) *credentials.Credentials {
+		assert.Equal(t, region, endpoints.UsGovWest1RegionID, "expected region differs")
+		called = true
+		return nil
+	}
+	_, err = fake.getSTSCredsFromPrimaryRegionEndpoint(nil, "us-gov-east-1", settings)
+	assert.True(t, called, "getSTSCredsFromRegionEndpoint should be called")
+	assert.NoError(t, err, "no expected error")
+
+	called = false
+	fake.getSTSCredsFromRegionEndpoint = func(_ *zap.Logger, _ *session.Session, region string, s *CredentialsSettings) *credentials.Credentials {
+		called = true
+		return nil
+	}
+	invalidRegion := "invalid region"
+	_, err = fake.getSTSCredsFromPrimaryRegionEndpoint(nil, invalidRegion, settings)
+	assert.False(t, called, "getSTSCredsFromRegionEndpoint should not be called")
+	assert.EqualError(t, err,
+		fmt.Sprintf("unrecognized AWS region: %s, or partition: ", invalidRegion),
+		"expected error message")
+}
+
+type mockAWSErr struct {
+}
+
+func (m *mockAWSErr) Error() string {
+	return "mockAWSErr"
+}
+
+func (m *mockAWSErr) Code() string {
+	return sts.ErrCodeRegionDisabledException
+}
+
+func (m *mockAWSErr) Message() string {
+	return ""
+}
+
+func (m *mockAWSErr) OrigErr() error {
+	return errors.New("mockAWSErr")
+}
+
+type mockProvider struct {
+	retrieveErr error
+}
+
+func (m *mockProvider) Retrieve() (credentials.Value, error) {
+	val, _ := credentials.AnonymousCredentials.Get()
+	if m.retrieveErr != nil {
+		return val, m.retrieveErr
+	}
+	return val, nil
+}
+
+func (m *mockProvider) IsExpired() bool {
+	return true
+}
+
+func TestSTSRegionalEndpointDisabled(t *testing.T) {
+	logger, recordedLogs := logSetup()
+
+	const expectedRegion = "us-west-2000"
+	called := false
+	expectedErr := &mockAWSErr{}
+	fake := &stsCalls{
+		log: logger,
+		getSTSCredsFromRegionEndpoint: func(_ *zap.Logger, _ *session.Session, region string, _ *CredentialsSettings) *credentials.Credentials {
+			called = true
+			return credentials.NewCredentials(&mockProvider{expectedErr})
+		},
+	}
+	_, err := fake.getCreds(nil, expectedRegion, &CredentialsSettings{RoleARN: roleARN})
+	assert.True(t, called, "getSTSCredsFromRegionEndpoint should be called")
+	assert.NoError(t, err, "no expected error")
+
+	logs := recordedLogs.All()
+	lastEntry := logs[len(logs)-1]
+	assert.Contains(t, lastEntry.Message,
+		"STS regional endpoint disabled. Credentials for provided RoleARN will be fetched from STS primary region endpoint instead",
+		"expected log message")
+	assert.Equal(t,
+		lastEntry.Context[0].String,
+		expectedRegion, "expected error")
+	assert.EqualError(t,
+		lastEntry.Context[1].Interface.(error),
+		expectedErr.Error(), "expected error")
+}
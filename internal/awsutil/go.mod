@@ -0,0 +1,10 @@
+module github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsutil
+
+go 1.14
+
+require (
+	github.com/aws/aws-sdk-go v1.34.30
+	github.com/stretchr/testify v1.6.1
+	go.uber.org/zap v1.16.0
+	golang.org/x/net v0.0.0-20200822124328-c89045814202
+)
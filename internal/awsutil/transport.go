@@ -0,0 +1,101 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsutil
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/net/http2"
+)
+
+const httpsProxyEnvVar = "HTTPS_PROXY"
+
+// newHTTPClient returns new HTTP client instance with provided configuration.
+func newHTTPClient(logger *zap.Logger, maxIdle int, requestTimeout int, noVerify bool,
+	proxyAddress string) (*http.Client, error) {
+	logger.Debug("Using proxy address: ",
+		zap.String("proxyAddr", proxyAddress),
+	)
+	tls := &tls.Config{
+		InsecureSkipVerify: noVerify,
+	}
+
+	proxyURL, err := getProxyURL(getProxyAddress(proxyAddress))
+	if err != nil {
+		logger.Error("unable to obtain proxy URL", zap.Error(err))
+		return nil, err
+	}
+	transport := &http.Transport{
+		MaxIdleConnsPerHost: maxIdle,
+		TLSClientConfig:     tls,
+		Proxy:               http.ProxyURL(proxyURL),
+	}
+
+	// is not enabled by default as we configure TLSClientConfig for supporting SSL to data plane.
+	// http2.ConfigureTransport will setup transport layer to use HTTP2
+	http2.ConfigureTransport(transport)
+	return &http.Client{
+		Transport: transport,
+		Timeout:   time.Second * time.Duration(requestTimeout),
+	}, nil
+}
+
+// ProxyServerTransport configures the HTTP transport used by local proxy
+// servers to forward signed requests to AWS.
+func ProxyServerTransport(settings *SessionSettings) (*http.Transport, error) {
+	proxyURL, err := getProxyURL(getProxyAddress(settings.ProxyAddress))
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Transport{
+		MaxIdleConnsPerHost: settings.NumberOfWorkers,
+		IdleConnTimeout:     time.Duration(settings.RequestTimeoutSeconds) * time.Second,
+		Proxy:               http.ProxyURL(proxyURL),
+		TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: settings.NoVerifySSL,
+		},
+
+		// If not disabled the transport will add a gzip encoding header
+		// to requests with no `accept-encoding` header value. The header
+		// is added after we sign the request which invalidates the
+		// signature.
+		DisableCompression: true,
+	}, nil
+}
+
+func getProxyAddress(proxyAddress string) string {
+	if proxyAddress != "" {
+		return proxyAddress
+	}
+	return os.Getenv(httpsProxyEnvVar)
+}
+
+func getProxyURL(proxyAddress string) (*url.URL, error) {
+	if proxyAddress == "" {
+		return nil, nil
+	}
+	proxyURL, err := url.Parse(proxyAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse proxy URL: %w", err)
+	}
+	return proxyURL, nil
+}
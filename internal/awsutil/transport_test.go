@@ -0,0 +1,91 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsutil
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestGetProxyUrlProxyAddressNotValid(t *testing.T) {
+	errorAddress := [3]string{"http://[%10::1]", "http://%41:8080/", "http://a b.com/"}
+	for _, address := range errorAddress {
+		_, err := getProxyURL(address)
+		assert.Error(t, err, "expected error")
+	}
+}
+
+func TestGetProxyAddressFromEnvVariable(t *testing.T) {
+	env := stashEnv()
+	defer restoreEnv(env)
+	os.Setenv(httpsProxyEnvVar, "https://127.0.0.1:8888")
+
+	assert.Equal(t, os.Getenv(httpsProxyEnvVar), getProxyAddress(""), "Expect function return value should be same with Environment value")
+}
+
+func TestGetProxyAddressFromConfigFile(t *testing.T) {
+	env := stashEnv()
+	defer restoreEnv(env)
+	const expectedAddr = "https://127.0.0.1:8888"
+
+	assert.Equal(t, expectedAddr, getProxyAddress("https://127.0.0.1:8888"), "Expect function return value should be same with input value")
+}
+
+func TestGetProxyAddressWhenNotExist(t *testing.T) {
+	env := stashEnv()
+	defer restoreEnv(env)
+
+	assert.Equal(t, "", getProxyAddress(""), "Expect function return value to be empty")
+}
+
+func TestGetProxyAddressPriority(t *testing.T) {
+	env := stashEnv()
+	defer restoreEnv(env)
+	os.Setenv(httpsProxyEnvVar, "https://127.0.0.1:8888")
+
+	assert.Equal(t, "https://127.0.0.1:9999", getProxyAddress("https://127.0.0.1:9999"), "Expect function return value to be same with input")
+}
+
+func TestNewHTTPClient(t *testing.T) {
+	client, err := newHTTPClient(zap.NewNop(), 8, 30, true, "https://127.0.0.1:8888")
+	assert.NoError(t, err, "no expected error")
+	assert.Equal(t, 30*time.Second, client.Timeout)
+
+	_, err = newHTTPClient(zap.NewNop(), 8, 30, false, "invalid\n")
+	assert.Error(t, err, "expected error")
+}
+
+func TestProxyServerTransportInvalidProxyAddr(t *testing.T) {
+	_, err := ProxyServerTransport(&SessionSettings{
+		ProxyAddress: "invalid\n",
+	})
+	assert.Error(t, err, "expected error")
+	assert.Contains(t, err.Error(), "invalid control character in URL")
+}
+
+func TestProxyServerTransportHappyCase(t *testing.T) {
+	transport, err := ProxyServerTransport(&SessionSettings{
+		NumberOfWorkers: 2,
+		NoVerifySSL:     true,
+	})
+	assert.NoError(t, err, "no expected error")
+	assert.Equal(t, 2, transport.MaxIdleConnsPerHost)
+	assert.True(t, transport.TLSClientConfig.InsecureSkipVerify)
+	assert.True(t, transport.DisableCompression)
+}
@@ -39,6 +39,26 @@ type Config struct {
 	// Filter section allows specifying filters to filter
 	// pods by labels, fields, namespaces, nodes, etc.
 	Filter FilterConfig `mapstructure:"filter"`
+
+	// PodAssociation lists the sources used to identify the pod telemetry
+	// data comes from. The sources are tried in order until one of them
+	// identifies a pod.
+	//
+	// Sources supported right now are,
+	//   pod_uid       - the k8s.pod.uid resource attribute
+	//   container_id  - the container.id resource attribute, matched against
+	//                   the container statuses of pods
+	//   pod_name      - the k8s.pod.name and k8s.namespace.name resource attributes
+	//   hostname      - the host.name resource attribute, matched against the
+	//                   hostname of pods in the k8s.namespace.name namespace, or
+	//                   in any namespace when only one pod has that hostname
+	//   ip            - the k8s.pod.ip or ip resource attributes, or for metrics
+	//                   a host.hostname resource attribute holding an IP address
+	//   connection    - the IP address of the client that sent the data
+	//
+	// By default all of the sources are tried in the order above. An IP address
+	// that doesn't belong to a known pod doesn't stop the search.
+	PodAssociation []string `mapstructure:"pod_association"`
 }
 
 // ExtractConfig section allows specifying extraction rules to extract
@@ -50,6 +70,11 @@ type ExtractConfig struct {
 	// Metadata fields supported right now are,
 	//   namespace, podName, podUID, deployment, cluster, node and startTime
 	//
+	// and for the container described by the k8s.container.name or the
+	// container.id resource attribute,
+	//   containerImageName, containerImageTag, containerRestartCount and
+	//   containerResources (requests and limits)
+	//
 	// Specifying anything other than these values will result in an error.
 	// By default all of the fields are extracted and added to spans and metrics.
 	Metadata []string `mapstructure:"metadata"`
@@ -65,6 +90,18 @@ type ExtractConfig struct {
 	// It is a list of FieldExtractConfig type. See FieldExtractConfig
 	// documentation for more details.
 	Labels []FieldExtractConfig `mapstructure:"labels"`
+
+	// NamespaceLabels allows extracting data from the labels of the namespace
+	// of a pod and record it as resource attributes.
+	// It is a list of FieldExtractConfig type. See FieldExtractConfig
+	// documentation for more details.
+	NamespaceLabels []FieldExtractConfig `mapstructure:"namespace_labels"`
+
+	// NodeLabels allows extracting data from the labels of the node a pod
+	// runs on and record it as resource attributes.
+	// It is a list of FieldExtractConfig type. See FieldExtractConfig
+	// documentation for more details.
+	NodeLabels []FieldExtractConfig `mapstructure:"node_labels"`
 }
 
 // FieldExtractConfig allows specifying an extraction rule to extract a value from exactly one field.
@@ -75,6 +75,12 @@ func TestLoadConfig(t *testing.T) {
 					{TagName: "l1", Key: "label1"},
 					{TagName: "l2", Key: "label2", Regex: "field=(?P<value>.+)"},
 				},
+				NamespaceLabels: []FieldExtractConfig{
+					{TagName: "team", Key: "team"},
+				},
+				NodeLabels: []FieldExtractConfig{
+					{TagName: "zone", Key: "topology.kubernetes.io/zone"},
+				},
 			},
 			Filter: FilterConfig{
 				Namespace:      "ns2",
@@ -89,5 +95,6 @@ func TestLoadConfig(t *testing.T) {
 					{Key: "key2", Value: "value2", Op: "not-equals"},
 				},
 			},
+			PodAssociation: []string{"pod_uid", "connection"},
 		})
 }
@@ -17,15 +17,50 @@
 // The processor automatically discovers k8s resources (pods), extracts metadata from them and adds the
 // extracted metadata to the relevant spans, metrics and logs. The processor use the kubernetes API to discover all pods
 // running in a cluster, keeps a record of their IP addresses and interesting metadata. Upon receiving telemetry data,
-// the processor looks for presence of well-known resource attributes which might contain IP address ("ip",
-// "k8s.pod.ip" for logs, metrics or traces and "host.hostname" for metrics). If this field is not available, or it
-// does not contain a valid IP address, the processor tries to identify the source IP address of the service
-// that sent the telemetry data.
+// the processor looks for presence of well-known resource attributes which identify the pod the data comes from.
+// By default the following pod association sources are tried in order:
+//
+//   pod_uid       "k8s.pod.uid"
+//   container_id  "container.id", matched against the container statuses of pods
+//   pod_name      "k8s.pod.name" and "k8s.namespace.name"
+//   hostname      "host.name", matched against the hostname of pods in the "k8s.namespace.name" namespace if set
+//   ip            "ip", "k8s.pod.ip" for logs, metrics or traces and "host.hostname" holding an IP address for metrics
+//   connection    the source IP address of the service that sent the telemetry data
+//
+// An IP address that doesn't belong to a known pod doesn't stop the search, the next source is tried. It is
+// still added as the "k8s.pod.ip" resource attribute when no source matches a pod.
+//
+// The sources, and their order, can be changed with the "pod_association" option:
+//
+//    k8s_tagger:
+//      pod_association:
+//        - pod_uid
+//        - connection
+//
 // If a match is found, the cached metadata is added to the data as resource attributes.
 //
+// When the data carries a "k8s.container.name" or "container.id" resource attribute, the image name and tag,
+// the restart count and the resource requests and limits of that container are added as well. They are
+// selected with the containerImageName, containerImageTag, containerRestartCount and containerResources
+// "extract.metadata" fields.
+//
+// Metadata is extracted from pods, and from the labels of their namespace and node when the
+// "extract.namespace_labels" and "extract.node_labels" rules are set. Namespaces and nodes are only
+// watched in that case:
+//
+//    k8s_tagger:
+//      extract:
+//        namespace_labels:
+//          - tag_name: team
+//            key: team
+//        node_labels:
+//          - tag_name: zone
+//            key: topology.kubernetes.io/zone
+//
 // RBAC
 //
-// TODO: mention the required RBAC rules.
+// The processor needs to get, list and watch pods, and namespaces and nodes when their labels are
+// extracted.
 //
 // Config
 //
@@ -182,12 +182,15 @@ func createProcessorOpts(cfg configmodels.Processor) []Option {
 	opts = append(opts, WithExtractMetadata(oCfg.Extract.Metadata...))
 	opts = append(opts, WithExtractLabels(oCfg.Extract.Labels...))
 	opts = append(opts, WithExtractAnnotations(oCfg.Extract.Annotations...))
+	opts = append(opts, WithExtractNamespaceLabels(oCfg.Extract.NamespaceLabels...))
+	opts = append(opts, WithExtractNodeLabels(oCfg.Extract.NodeLabels...))
 
 	// filters
 	opts = append(opts, WithFilterNode(oCfg.Filter.Node, oCfg.Filter.NodeFromEnvVar))
 	opts = append(opts, WithFilterNamespace(oCfg.Filter.Namespace))
 	opts = append(opts, WithFilterLabels(oCfg.Filter.Labels...))
 	opts = append(opts, WithFilterFields(oCfg.Filter.Fields...))
+	opts = append(opts, WithPodAssociations(oCfg.PodAssociation...))
 	opts = append(opts, WithAPIConfig(oCfg.APIConfig))
 
 	return opts
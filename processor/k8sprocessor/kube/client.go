@@ -17,6 +17,7 @@ package kube
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -36,18 +37,35 @@ import (
 
 // WatchClient is the main interface provided by this package to a kubernetes cluster.
 type WatchClient struct {
-	m               sync.RWMutex
-	deleteMut       sync.Mutex
-	logger          *zap.Logger
-	kc              kubernetes.Interface
-	informer        cache.SharedInformer
-	deploymentRegex *regexp.Regexp
-	deleteQueue     []deleteRequest
-	stopCh          chan struct{}
-
-	Pods    map[string]*Pod
-	Rules   ExtractionRules
-	Filters Filters
+	m         sync.RWMutex
+	deleteMut sync.Mutex
+	logger    *zap.Logger
+	kc        kubernetes.Interface
+	informer  cache.SharedInformer
+	// namespaceInformer and nodeInformer are only set when namespace or
+	// node labels are extracted.
+	namespaceInformer cache.SharedInformer
+	nodeInformer      cache.SharedInformer
+	deploymentRegex   *regexp.Regexp
+	deleteQueue       []deleteRequest
+	stopCh            chan struct{}
+
+	// Pods indexes pods by IP address, the other indexes by UID,
+	// <namespace>/<name>, <namespace>/<hostname> and container ID.
+	Pods              map[string]*Pod
+	PodsByUID         map[string]*Pod
+	PodsByName        map[string]*Pod
+	PodsByHostname    map[string]*Pod
+	PodsByContainerID map[string]*Pod
+	Namespaces        map[string]*Namespace
+	Nodes             map[string]*Node
+	Rules             ExtractionRules
+	Filters           Filters
+
+	// hostnameNamespaces holds the namespaces of the pods indexed by each
+	// hostname, so that hostnames used in several namespaces are not
+	// matched when the namespace is unknown.
+	hostnameNamespaces map[string]map[string]struct{}
 }
 
 // Extract deployment name from the pod name. Pod name is created using
@@ -60,6 +78,13 @@ func New(logger *zap.Logger, apiCfg k8sconfig.APIConfig, rules ExtractionRules,
 	go c.deleteLoop(time.Second*30, defaultPodDeleteGracePeriod)
 
 	c.Pods = map[string]*Pod{}
+	c.PodsByUID = map[string]*Pod{}
+	c.PodsByName = map[string]*Pod{}
+	c.PodsByHostname = map[string]*Pod{}
+	c.hostnameNamespaces = map[string]map[string]struct{}{}
+	c.PodsByContainerID = map[string]*Pod{}
+	c.Namespaces = map[string]*Namespace{}
+	c.Nodes = map[string]*Node{}
 	if newClientSet == nil {
 		newClientSet = k8sconfig.MakeClient
 	}
@@ -84,11 +109,34 @@ func New(logger *zap.Logger, apiCfg k8sconfig.APIConfig, rules ExtractionRules,
 	}
 
 	c.informer = newInformer(c.kc, c.Filters.Namespace, labelSelector, fieldSelector)
+	if len(c.Rules.NamespaceLabels) > 0 {
+		c.namespaceInformer = newNamespaceSharedInformer(c.kc, c.Filters.Namespace)
+	}
+	if len(c.Rules.NodeLabels) > 0 {
+		c.nodeInformer = newNodeSharedInformer(c.kc, c.Filters.Node)
+	}
 	return c, err
 }
 
 // Start registers pod event handlers and starts watching the kubernetes cluster for pod changes.
+// Namespaces and nodes are watched as well when their labels are extracted.
 func (c *WatchClient) Start() {
+	if c.namespaceInformer != nil {
+		c.namespaceInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.handleNamespaceAdd,
+			UpdateFunc: c.handleNamespaceUpdate,
+			DeleteFunc: c.handleNamespaceDelete,
+		})
+		go c.namespaceInformer.Run(c.stopCh)
+	}
+	if c.nodeInformer != nil {
+		c.nodeInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.handleNodeAdd,
+			UpdateFunc: c.handleNodeUpdate,
+			DeleteFunc: c.handleNodeDelete,
+		})
+		go c.nodeInformer.Run(c.stopCh)
+	}
 	c.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    c.handlePodAdd,
 		UpdateFunc: c.handlePodUpdate,
@@ -130,6 +178,56 @@ func (c *WatchClient) handlePodDelete(obj interface{}) {
 	}
 }
 
+func (c *WatchClient) handleNamespaceAdd(obj interface{}) {
+	if ns, ok := obj.(*api_v1.Namespace); ok {
+		c.addOrUpdateNamespace(ns)
+	} else {
+		c.logger.Error("object received was not of type api_v1.Namespace", zap.Any("received", obj))
+	}
+}
+
+func (c *WatchClient) handleNamespaceUpdate(old, new interface{}) {
+	c.handleNamespaceAdd(new)
+}
+
+func (c *WatchClient) handleNamespaceDelete(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	if ns, ok := obj.(*api_v1.Namespace); ok {
+		c.m.Lock()
+		delete(c.Namespaces, ns.Name)
+		c.m.Unlock()
+	} else {
+		c.logger.Error("object received was not of type api_v1.Namespace", zap.Any("received", obj))
+	}
+}
+
+func (c *WatchClient) handleNodeAdd(obj interface{}) {
+	if node, ok := obj.(*api_v1.Node); ok {
+		c.addOrUpdateNode(node)
+	} else {
+		c.logger.Error("object received was not of type api_v1.Node", zap.Any("received", obj))
+	}
+}
+
+func (c *WatchClient) handleNodeUpdate(old, new interface{}) {
+	c.handleNodeAdd(new)
+}
+
+func (c *WatchClient) handleNodeDelete(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	if node, ok := obj.(*api_v1.Node); ok {
+		c.m.Lock()
+		delete(c.Nodes, node.Name)
+		c.m.Unlock()
+	} else {
+		c.logger.Error("object received was not of type api_v1.Node", zap.Any("received", obj))
+	}
+}
+
 func (c *WatchClient) deleteLoop(interval time.Duration, gracePeriod time.Duration) {
 	// This loop runs after N seconds and deletes pods from cache.
 	// It iterates over the delete queue and deletes all that aren't
@@ -159,6 +257,9 @@ func (c *WatchClient) deleteLoop(interval time.Duration, gracePeriod time.Durati
 						delete(c.Pods, d.ip)
 					}
 				}
+				if p, ok := c.PodsByUID[d.uid]; ok && d.uid != "" {
+					c.removeFromIndexes(p)
+				}
 			}
 			c.m.Unlock()
 
@@ -183,6 +284,76 @@ func (c *WatchClient) GetPodByIP(ip string) (*Pod, bool) {
 	return nil, false
 }
 
+// GetNamespace returns the namespace with the given name. Namespaces are
+// only known when their labels are extracted.
+func (c *WatchClient) GetNamespace(name string) (*Namespace, bool) {
+	c.m.RLock()
+	ns, ok := c.Namespaces[name]
+	c.m.RUnlock()
+	return ns, ok
+}
+
+// GetNode returns the node with the given name. Nodes are only known when
+// their labels are extracted.
+func (c *WatchClient) GetNode(name string) (*Node, bool) {
+	c.m.RLock()
+	node, ok := c.Nodes[name]
+	c.m.RUnlock()
+	return node, ok
+}
+
+// GetPodByUID returns the pod with the given UID.
+func (c *WatchClient) GetPodByUID(uid string) (*Pod, bool) {
+	return c.getPod(c.PodsByUID, uid)
+}
+
+// GetPodByName returns the pod with the given name in the given namespace.
+func (c *WatchClient) GetPodByName(namespace, name string) (*Pod, bool) {
+	return c.getPod(c.PodsByName, podNameKey(namespace, name))
+}
+
+// GetPodByHostname returns the pod with the given hostname in the given
+// namespace. When the namespace is empty the pod is only returned if the
+// hostname is not used in any other namespace. Pods in the host network are
+// not indexed by hostname as they share the one of their node.
+func (c *WatchClient) GetPodByHostname(namespace, hostname string) (*Pod, bool) {
+	if hostname == "" {
+		return nil, false
+	}
+	if namespace == "" {
+		c.m.RLock()
+		namespaces := c.hostnameNamespaces[hostname]
+		if len(namespaces) == 1 {
+			for ns := range namespaces {
+				namespace = ns
+			}
+		}
+		c.m.RUnlock()
+		if namespace == "" {
+			return nil, false
+		}
+	}
+	return c.getPod(c.PodsByHostname, podNameKey(namespace, hostname))
+}
+
+// GetPodByContainerID returns the pod running the container with the given ID.
+func (c *WatchClient) GetPodByContainerID(containerID string) (*Pod, bool) {
+	return c.getPod(c.PodsByContainerID, containerID)
+}
+
+func (c *WatchClient) getPod(index map[string]*Pod, key string) (*Pod, bool) {
+	if key == "" {
+		return nil, false
+	}
+	c.m.RLock()
+	pod, ok := index[key]
+	c.m.RUnlock()
+	if !ok || pod.Ignore {
+		return nil, false
+	}
+	return pod, true
+}
+
 func (c *WatchClient) extractPodAttributes(pod *api_v1.Pod) map[string]string {
 	tags := map[string]string{}
 	if c.Rules.PodName {
@@ -238,6 +409,71 @@ func (c *WatchClient) extractPodAttributes(pod *api_v1.Pod) map[string]string {
 	return tags
 }
 
+// extractPodContainers returns the containers of the pod and their
+// attributes, or nil if no container metadata is extracted.
+func (c *WatchClient) extractPodContainers(pod *api_v1.Pod) map[string]*Container {
+	if !c.Rules.ContainerImageName && !c.Rules.ContainerImageTag &&
+		!c.Rules.ContainerRestartCount && !c.Rules.ContainerResources {
+		return nil
+	}
+
+	statuses := map[string]api_v1.ContainerStatus{}
+	for _, cs := range pod.Status.InitContainerStatuses {
+		statuses[cs.Name] = cs
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		statuses[cs.Name] = cs
+	}
+
+	containers := map[string]*Container{}
+	for _, specs := range [][]api_v1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+		for _, spec := range specs {
+			container := &Container{
+				Name:       spec.Name,
+				Attributes: map[string]string{},
+			}
+
+			imageName, imageTag := parseImage(spec.Image)
+			if c.Rules.ContainerImageName && imageName != "" {
+				container.Attributes[conventions.AttributeContainerImage] = imageName
+			}
+			if c.Rules.ContainerImageTag && imageTag != "" {
+				container.Attributes[conventions.AttributeContainerTag] = imageTag
+			}
+
+			if c.Rules.ContainerResources {
+				for name, q := range spec.Resources.Requests {
+					container.Attributes[tagContainerRequests+"."+string(name)] = q.String()
+				}
+				for name, q := range spec.Resources.Limits {
+					container.Attributes[tagContainerLimits+"."+string(name)] = q.String()
+				}
+			}
+
+			if status, ok := statuses[spec.Name]; ok {
+				container.ID = stripContainerID(status.ContainerID)
+				if c.Rules.ContainerRestartCount {
+					container.Attributes[tagContainerRestartCount] = strconv.Itoa(int(status.RestartCount))
+				}
+			}
+
+			containers[spec.Name] = container
+		}
+	}
+	return containers
+}
+
+// extractLabels extracts the labels matching the rules.
+func (c *WatchClient) extractLabels(labels map[string]string, rules []FieldExtractionRule) map[string]string {
+	tags := map[string]string{}
+	for _, r := range rules {
+		if v, ok := labels[r.Key]; ok {
+			tags[r.Name] = c.extractField(v, r)
+		}
+	}
+	return tags
+}
+
 func (c *WatchClient) extractField(v string, r FieldExtractionRule) string {
 	// Check if a subset of the field should be extracted with a regular expression
 	// instead of the whole field.
@@ -253,52 +489,146 @@ func (c *WatchClient) extractField(v string, r FieldExtractionRule) string {
 }
 
 func (c *WatchClient) addOrUpdatePod(pod *api_v1.Pod) {
-	if pod.Status.PodIP == "" {
-		return
+	newPod := &Pod{
+		Name:         pod.Name,
+		Namespace:    pod.Namespace,
+		UID:          string(pod.UID),
+		NodeName:     pod.Spec.NodeName,
+		Address:      pod.Status.PodIP,
+		Hostname:     podHostname(pod),
+		ContainerIDs: podContainerIDs(pod),
+		StartTime:    pod.Status.StartTime,
+	}
+
+	if c.shouldIgnorePod(pod) {
+		newPod.Ignore = true
+	} else {
+		newPod.Attributes = c.extractPodAttributes(pod)
+		newPod.Containers = c.extractPodContainers(pod)
 	}
 
 	c.m.Lock()
 	defer c.m.Unlock()
-	// compare initial scheduled timestamp for existing pod and new pod with same IP
-	// and only replace old pod if scheduled time of new pod is newer? This should fix
-	// the case where scheduler has assigned the same IP to a new pod but update event for
-	// the old pod came in later
-	if p, ok := c.Pods[pod.Status.PodIP]; ok {
-		if p.StartTime != nil && pod.Status.StartTime.Before(p.StartTime) {
-			return
+
+	// Drop the identifiers of the previous revision of the pod, a restarted
+	// container for instance gets a new ID.
+	if p, ok := c.PodsByUID[newPod.UID]; ok && newPod.UID != "" {
+		c.removeFromIndexes(p)
+	}
+
+	if newPod.Address != "" {
+		// compare initial scheduled timestamp for existing pod and new pod with same IP
+		// and only replace old pod if scheduled time of new pod is newer? This should fix
+		// the case where scheduler has assigned the same IP to a new pod but update event for
+		// the old pod came in later
+		p, ok := c.Pods[newPod.Address]
+		if !ok || p.StartTime == nil || !newPod.StartTime.Before(p.StartTime) {
+			c.Pods[newPod.Address] = newPod
 		}
 	}
-	newPod := &Pod{
-		Name:      pod.Name,
-		Address:   pod.Status.PodIP,
-		StartTime: pod.Status.StartTime,
+
+	c.indexPod(c.PodsByUID, newPod.UID, newPod)
+	if newPod.Name != "" {
+		c.indexPod(c.PodsByName, podNameKey(newPod.Namespace, newPod.Name), newPod)
+	}
+	if newPod.Hostname != "" {
+		c.indexPod(c.PodsByHostname, podNameKey(newPod.Namespace, newPod.Hostname), newPod)
+		namespaces, ok := c.hostnameNamespaces[newPod.Hostname]
+		if !ok {
+			namespaces = map[string]struct{}{}
+			c.hostnameNamespaces[newPod.Hostname] = namespaces
+		}
+		namespaces[newPod.Namespace] = struct{}{}
+	}
+	for _, id := range newPod.ContainerIDs {
+		c.indexPod(c.PodsByContainerID, id, newPod)
 	}
+}
 
-	if c.shouldIgnorePod(pod) {
-		newPod.Ignore = true
-	} else {
-		newPod.Attributes = c.extractPodAttributes(pod)
+// indexPod adds the pod to the index unless the key already belongs to a
+// different pod that started later. Names and hostnames are reused when pods
+// are recreated, so events for the old pod must not replace the new one.
+func (c *WatchClient) indexPod(index map[string]*Pod, key string, pod *Pod) {
+	if key == "" {
+		return
+	}
+	if p, ok := index[key]; ok && p.UID != pod.UID && p.StartTime != nil && pod.StartTime.Before(p.StartTime) {
+		return
 	}
-	c.Pods[pod.Status.PodIP] = newPod
+	index[key] = pod
+}
+
+// removeFromIndexes removes the pod from all the indexes but the IP one,
+// leaving keys that have since been taken by other pods. It must be called
+// with the lock held.
+func (c *WatchClient) removeFromIndexes(pod *Pod) {
+	remove := func(index map[string]*Pod, key string) {
+		if p, ok := index[key]; ok && p.UID == pod.UID {
+			delete(index, key)
+		}
+	}
+
+	remove(c.PodsByUID, pod.UID)
+	remove(c.PodsByName, podNameKey(pod.Namespace, pod.Name))
+	hostnameKey := podNameKey(pod.Namespace, pod.Hostname)
+	remove(c.PodsByHostname, hostnameKey)
+	if _, ok := c.PodsByHostname[hostnameKey]; !ok {
+		if namespaces, ok := c.hostnameNamespaces[pod.Hostname]; ok {
+			delete(namespaces, pod.Namespace)
+			if len(namespaces) == 0 {
+				delete(c.hostnameNamespaces, pod.Hostname)
+			}
+		}
+	}
+	for _, id := range pod.ContainerIDs {
+		remove(c.PodsByContainerID, id)
+	}
+}
+
+func (c *WatchClient) addOrUpdateNamespace(ns *api_v1.Namespace) {
+	newNamespace := &Namespace{
+		Name:       ns.Name,
+		Attributes: c.extractLabels(ns.Labels, c.Rules.NamespaceLabels),
+	}
+
+	c.m.Lock()
+	c.Namespaces[ns.Name] = newNamespace
+	c.m.Unlock()
+}
+
+func (c *WatchClient) addOrUpdateNode(node *api_v1.Node) {
+	newNode := &Node{
+		Name:       node.Name,
+		Attributes: c.extractLabels(node.Labels, c.Rules.NodeLabels),
+	}
+
+	c.m.Lock()
+	c.Nodes[node.Name] = newNode
+	c.m.Unlock()
 }
 
 func (c *WatchClient) forgetPod(pod *api_v1.Pod) {
-	if pod.Status.PodIP == "" {
-		return
+	req := deleteRequest{
+		name: pod.Name,
+		ts:   time.Now(),
 	}
+
 	c.m.RLock()
-	p, ok := c.GetPodByIP(pod.Status.PodIP)
+	if p, ok := c.Pods[pod.Status.PodIP]; ok && pod.Status.PodIP != "" && p.Name == pod.Name {
+		req.ip = pod.Status.PodIP
+	}
+	if _, ok := c.PodsByUID[string(pod.UID)]; ok && pod.UID != "" {
+		req.uid = string(pod.UID)
+	}
 	c.m.RUnlock()
 
-	if ok && p.Name == pod.Name {
-		c.deleteMut.Lock()
-		c.deleteQueue = append(c.deleteQueue, deleteRequest{
-			ip:   pod.Status.PodIP,
-			name: pod.Name,
-			ts:   time.Now(),
-		})
-		c.deleteMut.Unlock()
+	if req.ip == "" && req.uid == "" {
+		return
 	}
+
+	c.deleteMut.Lock()
+	c.deleteQueue = append(c.deleteQueue, req)
+	c.deleteMut.Unlock()
 }
 
 func (c *WatchClient) shouldIgnorePod(pod *api_v1.Pod) bool {
@@ -328,6 +658,71 @@ func (c *WatchClient) shouldIgnorePod(pod *api_v1.Pod) bool {
 	return false
 }
 
+func podNameKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+// podHostname returns the hostname containers of the pod run with, which
+// is the pod name unless a hostname is set in the spec. Pods in the host
+// network run with the hostname of their node, which isn't returned.
+func podHostname(pod *api_v1.Pod) string {
+	if pod.Spec.HostNetwork {
+		return ""
+	}
+	if pod.Spec.Hostname != "" {
+		return pod.Spec.Hostname
+	}
+	return pod.Name
+}
+
+// podContainerIDs returns the IDs of the containers of the pod, without
+// the container runtime prefix (e.g. docker://).
+func podContainerIDs(pod *api_v1.Pod) []string {
+	var ids []string
+	for _, statuses := range [][]api_v1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+		for _, cs := range statuses {
+			if id := stripContainerID(cs.ContainerID); id != "" {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// stripContainerID removes the container runtime prefix (e.g. docker://)
+// from a container ID.
+func stripContainerID(id string) string {
+	if i := strings.Index(id, "://"); i >= 0 {
+		return id[i+len("://"):]
+	}
+	return id
+}
+
+// parseImage splits a container image reference into the image name and
+// tag. The tag is empty for images referenced by digest only, and latest
+// when neither is set.
+func parseImage(image string) (name, tag string) {
+	if image == "" {
+		return "", ""
+	}
+
+	name = image
+	byDigest := false
+	if i := strings.Index(name, "@"); i >= 0 {
+		name = name[:i]
+		byDigest = true
+	}
+
+	// A colon before the last slash separates the registry host and port.
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		return name[:i], name[i+1:]
+	}
+	if byDigest {
+		return name, ""
+	}
+	return name, "latest"
+}
+
 func selectorsFromFilters(filters Filters) (labels.Selector, fields.Selector, error) {
 	labelSelector := labels.Everything()
 	for _, f := range filters.Labels {
@@ -27,12 +27,14 @@ import (
 	"go.uber.org/zap/zapcore"
 	"go.uber.org/zap/zaptest/observer"
 	api_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/k8sconfig"
 )
@@ -151,6 +153,138 @@ func TestPodHostNetwork(t *testing.T) {
 	assert.True(t, got.Ignore)
 }
 
+func TestPodIndexes(t *testing.T) {
+	c, _ := newTestClient(t)
+
+	pod := &api_v1.Pod{}
+	pod.Name = "podA"
+	pod.Namespace = "ns"
+	pod.UID = "uid-a"
+	pod.Status.PodIP = "1.1.1.1"
+	pod.Status.ContainerStatuses = []api_v1.ContainerStatus{
+		{ContainerID: "docker://container-1"},
+		{ContainerID: "containerd://container-2"},
+		{},
+	}
+	c.handlePodAdd(pod)
+
+	for _, lookup := range []func() (*Pod, bool){
+		func() (*Pod, bool) { return c.GetPodByIP("1.1.1.1") },
+		func() (*Pod, bool) { return c.GetPodByUID("uid-a") },
+		func() (*Pod, bool) { return c.GetPodByName("ns", "podA") },
+		func() (*Pod, bool) { return c.GetPodByHostname("", "podA") },
+		func() (*Pod, bool) { return c.GetPodByHostname("ns", "podA") },
+		func() (*Pod, bool) { return c.GetPodByContainerID("container-1") },
+		func() (*Pod, bool) { return c.GetPodByContainerID("container-2") },
+	} {
+		got, ok := lookup()
+		require.True(t, ok)
+		assert.Equal(t, "uid-a", got.UID)
+	}
+
+	_, ok := c.GetPodByName("other", "podA")
+	assert.False(t, ok)
+	_, ok = c.GetPodByUID("")
+	assert.False(t, ok)
+
+	// a restarted container gets a new ID and a hostname can be set in the spec
+	updated := pod.DeepCopy()
+	updated.Spec.Hostname = "host-a"
+	updated.Status.ContainerStatuses = []api_v1.ContainerStatus{
+		{ContainerID: "docker://container-3"},
+	}
+	c.handlePodUpdate(pod, updated)
+
+	_, ok = c.GetPodByContainerID("container-1")
+	assert.False(t, ok)
+	_, ok = c.GetPodByHostname("", "podA")
+	assert.False(t, ok)
+	got, ok := c.GetPodByContainerID("container-3")
+	require.True(t, ok)
+	assert.Equal(t, "uid-a", got.UID)
+	got, ok = c.GetPodByHostname("ns", "host-a")
+	require.True(t, ok)
+	assert.Equal(t, "uid-a", got.UID)
+}
+
+func TestPodRecreatedWithSameName(t *testing.T) {
+	c, _ := newTestClient(t)
+
+	oldStart := meta_v1.NewTime(time.Now().Add(-time.Minute))
+	oldPod := &api_v1.Pod{}
+	oldPod.Name = "podA"
+	oldPod.Namespace = "ns"
+	oldPod.UID = "uid-old"
+	oldPod.Status.StartTime = &oldStart
+	c.handlePodAdd(oldPod)
+
+	newStart := meta_v1.NewTime(time.Now())
+	newPod := &api_v1.Pod{}
+	newPod.Name = "podA"
+	newPod.Namespace = "ns"
+	newPod.UID = "uid-new"
+	newPod.Status.StartTime = &newStart
+	c.handlePodAdd(newPod)
+
+	// a late update of the old pod doesn't replace the new one
+	c.handlePodUpdate(oldPod, oldPod)
+	got, ok := c.GetPodByName("ns", "podA")
+	require.True(t, ok)
+	assert.Equal(t, "uid-new", got.UID)
+
+	// removing the old pod leaves the new one in place
+	c.m.Lock()
+	c.removeFromIndexes(c.PodsByUID["uid-old"])
+	c.m.Unlock()
+	got, ok = c.GetPodByName("ns", "podA")
+	require.True(t, ok)
+	assert.Equal(t, "uid-new", got.UID)
+	_, ok = c.GetPodByUID("uid-old")
+	assert.False(t, ok)
+}
+
+func TestPodSameHostnameInNamespaces(t *testing.T) {
+	c, _ := newTestClient(t)
+
+	podA := &api_v1.Pod{}
+	podA.Name = "pod"
+	podA.Namespace = "ns-a"
+	podA.UID = "uid-a"
+	c.handlePodAdd(podA)
+
+	got, ok := c.GetPodByHostname("", "pod")
+	require.True(t, ok)
+	assert.Equal(t, "uid-a", got.UID)
+
+	podB := podA.DeepCopy()
+	podB.Namespace = "ns-b"
+	podB.UID = "uid-b"
+	c.handlePodAdd(podB)
+
+	// the hostname is ambiguous without a namespace
+	_, ok = c.GetPodByHostname("", "pod")
+	assert.False(t, ok)
+	got, ok = c.GetPodByHostname("ns-a", "pod")
+	require.True(t, ok)
+	assert.Equal(t, "uid-a", got.UID)
+	got, ok = c.GetPodByHostname("ns-b", "pod")
+	require.True(t, ok)
+	assert.Equal(t, "uid-b", got.UID)
+
+	// removing one pod leaves the other one in place
+	c.m.Lock()
+	c.removeFromIndexes(c.PodsByUID["uid-a"])
+	c.m.Unlock()
+	_, ok = c.GetPodByHostname("ns-a", "pod")
+	assert.False(t, ok)
+	got, ok = c.GetPodByHostname("ns-b", "pod")
+	require.True(t, ok)
+	assert.Equal(t, "uid-b", got.UID)
+	got, ok = c.GetPodByHostname("", "pod")
+	require.True(t, ok)
+	assert.Equal(t, "uid-b", got.UID)
+}
+
 func TestPodAddOutOfSync(t *testing.T) {
 	c, _ := newTestClient(t)
 	assert.Equal(t, len(c.Pods), 0)
@@ -228,6 +362,24 @@ func TestPodDelete(t *testing.T) {
 	assert.True(t, deleteRequest.ts.Before(time.Now()))
 }
 
+func TestPodDeleteByUID(t *testing.T) {
+	c, _ := newTestClient(t)
+
+	// pods without an IP address are only indexed by their other identifiers
+	pod := &api_v1.Pod{}
+	pod.Name = "podA"
+	pod.Namespace = "ns"
+	pod.UID = "uid-a"
+	c.handlePodAdd(pod)
+	assert.Equal(t, 0, len(c.Pods))
+	assert.Equal(t, 1, len(c.PodsByUID))
+
+	c.handlePodDelete(pod)
+	require.Equal(t, 1, len(c.deleteQueue))
+	assert.Equal(t, "", c.deleteQueue[0].ip)
+	assert.Equal(t, "uid-a", c.deleteQueue[0].uid)
+}
+
 func TestDeleteQueue(t *testing.T) {
 	c, _ := newTestClient(t)
 	podAddAndUpdateTest(t, c, c.handlePodAdd)
@@ -291,6 +443,83 @@ func TestGetIgnoredPod(t *testing.T) {
 	assert.False(t, ok)
 }
 
+func TestNamespaceAndNodeInformers(t *testing.T) {
+	c, _ := newTestClient(t)
+	assert.Nil(t, c.namespaceInformer)
+	assert.Nil(t, c.nodeInformer)
+
+	c, _ = newTestClientWithRulesAndFilters(t, ExtractionRules{
+		NamespaceLabels: []FieldExtractionRule{{Name: "team", Key: "team"}},
+		NodeLabels:      []FieldExtractionRule{{Name: "zone", Key: "topology.kubernetes.io/zone"}},
+	}, Filters{})
+	assert.NotNil(t, c.namespaceInformer)
+	assert.NotNil(t, c.nodeInformer)
+}
+
+func TestNamespaceLabels(t *testing.T) {
+	c, _ := newTestClientWithRulesAndFilters(t, ExtractionRules{
+		NamespaceLabels: []FieldExtractionRule{
+			{Name: "team", Key: "team"},
+			{Name: "cost.center", Key: "cost-center", Regex: regexp.MustCompile(`^cc-(?P<value>\d+)$`)},
+		},
+	}, Filters{})
+
+	ns := &api_v1.Namespace{}
+	ns.Name = "ns1"
+	ns.Labels = map[string]string{
+		"team":        "payments",
+		"cost-center": "cc-1234",
+		"other":       "value",
+	}
+	c.handleNamespaceAdd(ns)
+
+	got, ok := c.GetNamespace("ns1")
+	require.True(t, ok)
+	assert.Equal(t, map[string]string{"team": "payments", "cost.center": "1234"}, got.Attributes)
+
+	updated := ns.DeepCopy()
+	updated.Labels["team"] = "billing"
+	c.handleNamespaceUpdate(ns, updated)
+	got, ok = c.GetNamespace("ns1")
+	require.True(t, ok)
+	assert.Equal(t, "billing", got.Attributes["team"])
+
+	c.handleNamespaceDelete(cache.DeletedFinalStateUnknown{Obj: updated})
+	_, ok = c.GetNamespace("ns1")
+	assert.False(t, ok)
+}
+
+func TestNodeLabels(t *testing.T) {
+	c, _ := newTestClientWithRulesAndFilters(t, ExtractionRules{
+		NodeLabels: []FieldExtractionRule{
+			{Name: "zone", Key: "topology.kubernetes.io/zone"},
+			{Name: "instance.type", Key: "node.kubernetes.io/instance-type"},
+		},
+	}, Filters{})
+
+	node := &api_v1.Node{}
+	node.Name = "node1"
+	node.Labels = map[string]string{
+		"topology.kubernetes.io/zone": "us-east-1a",
+	}
+	c.handleNodeAdd(node)
+
+	got, ok := c.GetNode("node1")
+	require.True(t, ok)
+	assert.Equal(t, map[string]string{"zone": "us-east-1a"}, got.Attributes)
+
+	updated := node.DeepCopy()
+	updated.Labels["node.kubernetes.io/instance-type"] = "m5.large"
+	c.handleNodeUpdate(node, updated)
+	got, ok = c.GetNode("node1")
+	require.True(t, ok)
+	assert.Equal(t, map[string]string{"zone": "us-east-1a", "instance.type": "m5.large"}, got.Attributes)
+
+	c.handleNodeDelete(updated)
+	_, ok = c.GetNode("node1")
+	assert.False(t, ok)
+}
+
 func TestHandlerWrongType(t *testing.T) {
 	c, logs := newTestClientWithRulesAndFilters(t, ExtractionRules{}, Filters{})
 	assert.Equal(t, logs.Len(), 0)
@@ -303,6 +532,24 @@ func TestHandlerWrongType(t *testing.T) {
 	}
 }
 
+func TestNamespaceAndNodeHandlerWrongType(t *testing.T) {
+	c, logs := newTestClientWithRulesAndFilters(t, ExtractionRules{}, Filters{})
+	c.handleNamespaceAdd(1)
+	c.handleNamespaceDelete(1)
+	c.handleNamespaceUpdate(1, 2)
+	c.handleNodeAdd(1)
+	c.handleNodeDelete(1)
+	c.handleNodeUpdate(1, 2)
+	require.Equal(t, 6, logs.Len())
+	for i, l := range logs.All() {
+		if i < 3 {
+			assert.Equal(t, "object received was not of type api_v1.Namespace", l.Message)
+		} else {
+			assert.Equal(t, "object received was not of type api_v1.Node", l.Message)
+		}
+	}
+}
+
 func TestExtractionRules(t *testing.T) {
 	c, _ := newTestClientWithRulesAndFilters(t, ExtractionRules{}, Filters{})
 
@@ -545,6 +792,97 @@ func TestPodIgnorePatterns(t *testing.T) {
 	}
 }
 
+func TestExtractPodContainers(t *testing.T) {
+	pod := &api_v1.Pod{
+		Spec: api_v1.PodSpec{
+			InitContainers: []api_v1.Container{{
+				Name:  "init",
+				Image: "busybox",
+			}},
+			Containers: []api_v1.Container{{
+				Name:  "app",
+				Image: "registry.example.com:5000/team/app:1.2.3",
+				Resources: api_v1.ResourceRequirements{
+					Requests: api_v1.ResourceList{
+						api_v1.ResourceCPU: resource.MustParse("250m"),
+					},
+					Limits: api_v1.ResourceList{
+						api_v1.ResourceMemory: resource.MustParse("128Mi"),
+					},
+				},
+			}},
+		},
+		Status: api_v1.PodStatus{
+			InitContainerStatuses: []api_v1.ContainerStatus{{
+				Name:        "init",
+				ContainerID: "docker://init-id",
+			}},
+			ContainerStatuses: []api_v1.ContainerStatus{{
+				Name:         "app",
+				ContainerID:  "containerd://app-id",
+				RestartCount: 3,
+			}},
+		},
+	}
+
+	c, _ := newTestClient(t)
+	assert.Nil(t, c.extractPodContainers(pod))
+
+	c, _ = newTestClientWithRulesAndFilters(t, ExtractionRules{
+		ContainerImageName:    true,
+		ContainerImageTag:     true,
+		ContainerRestartCount: true,
+		ContainerResources:    true,
+	}, Filters{})
+	assert.Equal(t, map[string]*Container{
+		"init": {
+			Name: "init",
+			ID:   "init-id",
+			Attributes: map[string]string{
+				"container.image.name":        "busybox",
+				"container.image.tag":         "latest",
+				"k8s.container.restart_count": "0",
+			},
+		},
+		"app": {
+			Name: "app",
+			ID:   "app-id",
+			Attributes: map[string]string{
+				"container.image.name":        "registry.example.com:5000/team/app",
+				"container.image.tag":         "1.2.3",
+				"k8s.container.restart_count": "3",
+				"k8s.container.requests.cpu":  "250m",
+				"k8s.container.limits.memory": "128Mi",
+			},
+		},
+	}, c.extractPodContainers(pod))
+
+	c, _ = newTestClientWithRulesAndFilters(t, ExtractionRules{ContainerImageTag: true}, Filters{})
+	containers := c.extractPodContainers(pod)
+	assert.Equal(t, map[string]string{"container.image.tag": "1.2.3"}, containers["app"].Attributes)
+}
+
+func Test_parseImage(t *testing.T) {
+	tests := []struct {
+		image string
+		name  string
+		tag   string
+	}{
+		{"", "", ""},
+		{"nginx", "nginx", "latest"},
+		{"nginx:1.19", "nginx", "1.19"},
+		{"localhost:5000/nginx", "localhost:5000/nginx", "latest"},
+		{"localhost:5000/nginx:1.19", "localhost:5000/nginx", "1.19"},
+		{"nginx@sha256:abc", "nginx", ""},
+		{"nginx:1.19@sha256:abc", "nginx", "1.19"},
+	}
+	for _, tt := range tests {
+		name, tag := parseImage(tt.image)
+		assert.Equal(t, tt.name, name, tt.image)
+		assert.Equal(t, tt.tag, tag, tt.image)
+	}
+}
+
 func Test_extractField(t *testing.T) {
 	c := WatchClient{}
 	type args struct {
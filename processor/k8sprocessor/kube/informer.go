@@ -53,6 +53,52 @@ func newSharedInformer(
 	return informer
 }
 
+// newNamespaceSharedInformer returns an informer watching all namespaces,
+// or only the given one.
+func newNamespaceSharedInformer(client kubernetes.Interface, namespace string) cache.SharedInformer {
+	fs := fields.Everything()
+	if namespace != "" {
+		fs = fields.OneTermEqualSelector(objectNameField, namespace)
+	}
+	return cache.NewSharedInformer(
+		&cache.ListWatch{
+			ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+				opts.FieldSelector = fs.String()
+				return client.CoreV1().Namespaces().List(context.Background(), opts)
+			},
+			WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+				opts.FieldSelector = fs.String()
+				return client.CoreV1().Namespaces().Watch(context.Background(), opts)
+			},
+		},
+		&api_v1.Namespace{},
+		watchSyncPeriod,
+	)
+}
+
+// newNodeSharedInformer returns an informer watching all nodes, or only the
+// given one.
+func newNodeSharedInformer(client kubernetes.Interface, node string) cache.SharedInformer {
+	fs := fields.Everything()
+	if node != "" {
+		fs = fields.OneTermEqualSelector(objectNameField, node)
+	}
+	return cache.NewSharedInformer(
+		&cache.ListWatch{
+			ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+				opts.FieldSelector = fs.String()
+				return client.CoreV1().Nodes().List(context.Background(), opts)
+			},
+			WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+				opts.FieldSelector = fs.String()
+				return client.CoreV1().Nodes().Watch(context.Background(), opts)
+			},
+		},
+		&api_v1.Node{},
+		watchSyncPeriod,
+	)
+}
+
 func informerListFuncWithSelectors(client kubernetes.Interface, namespace string, ls labels.Selector, fs fields.Selector) cache.ListFunc {
 	return func(opts metav1.ListOptions) (runtime.Object, error) {
 		opts.LabelSelector = ls.String()
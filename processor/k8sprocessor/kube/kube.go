@@ -28,10 +28,15 @@ import (
 
 const (
 	podNodeField            = "spec.nodeName"
+	objectNameField         = "metadata.name"
 	ignoreAnnotation string = "opentelemetry.io/k8s-processor/ignore"
 
 	tagNodeName  = "k8s.node.name"
 	tagStartTime = "k8s.pod.startTime"
+
+	tagContainerRestartCount = "k8s.container.restart_count"
+	tagContainerRequests     = "k8s.container.requests"
+	tagContainerLimits       = "k8s.container.limits"
 )
 
 var (
@@ -47,6 +52,12 @@ var (
 // Client defines the main interface that allows querying pods by metadata.
 type Client interface {
 	GetPodByIP(string) (*Pod, bool)
+	GetPodByUID(string) (*Pod, bool)
+	GetPodByName(namespace, name string) (*Pod, bool)
+	GetPodByHostname(namespace, hostname string) (*Pod, bool)
+	GetPodByContainerID(string) (*Pod, bool)
+	GetNamespace(string) (*Namespace, bool)
+	GetNode(string) (*Node, bool)
 	Start()
 	Stop()
 }
@@ -60,8 +71,16 @@ type APIClientsetProvider func(config k8sconfig.APIConfig) (kubernetes.Interface
 
 // Pod represents a kubernetes pod.
 type Pod struct {
-	Name       string
-	Address    string
+	Name         string
+	Namespace    string
+	UID          string
+	NodeName     string
+	Address      string
+	Hostname     string
+	ContainerIDs []string
+	// Containers holds the containers of the pod by name, when container
+	// metadata is extracted.
+	Containers map[string]*Container
 	Attributes map[string]string
 	StartTime  *metav1.Time
 	Ignore     bool
@@ -69,9 +88,31 @@ type Pod struct {
 	DeletedAt time.Time
 }
 
+// Container represents a container of a kubernetes pod.
+type Container struct {
+	Name string
+	// ID is the ID of the current instance of the container, without the
+	// container runtime prefix.
+	ID         string
+	Attributes map[string]string
+}
+
+// Namespace represents a kubernetes namespace.
+type Namespace struct {
+	Name       string
+	Attributes map[string]string
+}
+
+// Node represents a kubernetes node.
+type Node struct {
+	Name       string
+	Attributes map[string]string
+}
+
 type deleteRequest struct {
 	ip   string
 	name string
+	uid  string
 	ts   time.Time
 }
 
@@ -110,8 +151,15 @@ type ExtractionRules struct {
 	Cluster    bool
 	StartTime  bool
 
-	Annotations []FieldExtractionRule
-	Labels      []FieldExtractionRule
+	ContainerImageName    bool
+	ContainerImageTag     bool
+	ContainerRestartCount bool
+	ContainerResources    bool
+
+	Annotations     []FieldExtractionRule
+	Labels          []FieldExtractionRule
+	NamespaceLabels []FieldExtractionRule
+	NodeLabels      []FieldExtractionRule
 }
 
 // FieldExtractionRule is used to specify which fields to extract from pod fields
@@ -38,6 +38,18 @@ const (
 	metadataDeployment = "deployment"
 	metadataCluster    = "cluster"
 	metadataNode       = "node"
+
+	metadataContainerImageName    = "containerImageName"
+	metadataContainerImageTag     = "containerImageTag"
+	metadataContainerRestartCount = "containerRestartCount"
+	metadataContainerResources    = "containerResources"
+
+	podAssociationPodUID      = "pod_uid"
+	podAssociationContainerID = "container_id"
+	podAssociationPodName     = "pod_name"
+	podAssociationHostname    = "hostname"
+	podAssociationIP          = "ip"
+	podAssociationConnection  = "connection"
 )
 
 // Option represents a configuration option that can be passes.
@@ -75,6 +87,10 @@ func WithExtractMetadata(fields ...string) Option {
 				metadataDeployment,
 				metadataCluster,
 				metadataNode,
+				metadataContainerImageName,
+				metadataContainerImageTag,
+				metadataContainerRestartCount,
+				metadataContainerResources,
 			}
 		}
 		for _, field := range fields {
@@ -93,6 +109,14 @@ func WithExtractMetadata(fields ...string) Option {
 				p.rules.Cluster = true
 			case metadataNode:
 				p.rules.Node = true
+			case metadataContainerImageName:
+				p.rules.ContainerImageName = true
+			case metadataContainerImageTag:
+				p.rules.ContainerImageTag = true
+			case metadataContainerRestartCount:
+				p.rules.ContainerRestartCount = true
+			case metadataContainerResources:
+				p.rules.ContainerResources = true
 			default:
 				return fmt.Errorf("\"%s\" is not a supported metadata field", field)
 			}
@@ -101,6 +125,34 @@ func WithExtractMetadata(fields ...string) Option {
 	}
 }
 
+// WithPodAssociations allows specifying the ordered list of sources used to
+// identify the pod telemetry data comes from. If no sources are explicitly
+// provided, all of them are used.
+func WithPodAssociations(sources ...string) Option {
+	return func(p *kubernetesprocessor) error {
+		if len(sources) == 0 {
+			sources = []string{
+				podAssociationPodUID,
+				podAssociationContainerID,
+				podAssociationPodName,
+				podAssociationHostname,
+				podAssociationIP,
+				podAssociationConnection,
+			}
+		}
+		for _, source := range sources {
+			switch source {
+			case podAssociationPodUID, podAssociationContainerID, podAssociationPodName,
+				podAssociationHostname, podAssociationIP, podAssociationConnection:
+			default:
+				return fmt.Errorf("\"%s\" is not a supported pod association source", source)
+			}
+		}
+		p.podAssociations = sources
+		return nil
+	}
+}
+
 // WithExtractLabels allows specifying options to control extraction of pod labels.
 func WithExtractLabels(labels ...FieldExtractConfig) Option {
 	return func(p *kubernetesprocessor) error {
@@ -125,6 +177,30 @@ func WithExtractAnnotations(annotations ...FieldExtractConfig) Option {
 	}
 }
 
+// WithExtractNamespaceLabels allows specifying options to control extraction of namespace labels.
+func WithExtractNamespaceLabels(labels ...FieldExtractConfig) Option {
+	return func(p *kubernetesprocessor) error {
+		labels, err := extractFieldRules("namespace.label", labels...)
+		if err != nil {
+			return err
+		}
+		p.rules.NamespaceLabels = labels
+		return nil
+	}
+}
+
+// WithExtractNodeLabels allows specifying options to control extraction of node labels.
+func WithExtractNodeLabels(labels ...FieldExtractConfig) Option {
+	return func(p *kubernetesprocessor) error {
+		labels, err := extractFieldRules("node.label", labels...)
+		if err != nil {
+			return err
+		}
+		p.rules.NodeLabels = labels
+		return nil
+	}
+}
+
 func extractFieldRules(fieldType string, fields ...FieldExtractConfig) ([]kube.FieldExtractionRule, error) {
 	rules := []kube.FieldExtractionRule{}
 	for _, a := range fields {
@@ -205,6 +205,10 @@ func TestWithExtractMetadata(t *testing.T) {
 	assert.True(t, p.rules.Deployment)
 	assert.True(t, p.rules.Cluster)
 	assert.True(t, p.rules.Node)
+	assert.True(t, p.rules.ContainerImageName)
+	assert.True(t, p.rules.ContainerImageTag)
+	assert.True(t, p.rules.ContainerRestartCount)
+	assert.True(t, p.rules.ContainerResources)
 
 	p = &kubernetesprocessor{}
 	err := WithExtractMetadata("randomfield")(p)
@@ -219,6 +223,14 @@ func TestWithExtractMetadata(t *testing.T) {
 	assert.False(t, p.rules.StartTime)
 	assert.False(t, p.rules.Deployment)
 	assert.False(t, p.rules.Node)
+	assert.False(t, p.rules.ContainerImageName)
+
+	p = &kubernetesprocessor{}
+	assert.NoError(t, WithExtractMetadata("containerImageName", "containerRestartCount")(p))
+	assert.True(t, p.rules.ContainerImageName)
+	assert.False(t, p.rules.ContainerImageTag)
+	assert.True(t, p.rules.ContainerRestartCount)
+	assert.False(t, p.rules.ContainerResources)
 }
 
 func TestWithFilterLabels(t *testing.T) {
@@ -564,3 +576,36 @@ func Test_extractFieldRules(t *testing.T) {
 		})
 	}
 }
+
+func TestWithPodAssociations(t *testing.T) {
+	p := &kubernetesprocessor{}
+	assert.NoError(t, WithPodAssociations()(p))
+	assert.Equal(t, []string{"pod_uid", "container_id", "pod_name", "hostname", "ip", "connection"}, p.podAssociations)
+
+	p = &kubernetesprocessor{}
+	assert.NoError(t, WithPodAssociations("hostname", "connection")(p))
+	assert.Equal(t, []string{"hostname", "connection"}, p.podAssociations)
+
+	p = &kubernetesprocessor{}
+	assert.Error(t, WithPodAssociations("hostname", "mac_address")(p))
+}
+
+func TestWithExtractNamespaceAndNodeLabels(t *testing.T) {
+	p := &kubernetesprocessor{}
+	assert.NoError(t, WithExtractNamespaceLabels(
+		FieldExtractConfig{Key: "team"},
+		FieldExtractConfig{TagName: "cost.center", Key: "cost-center", Regex: "cc-(?P<value>.+)"},
+	)(p))
+	assert.NoError(t, WithExtractNodeLabels(FieldExtractConfig{Key: "topology.kubernetes.io/zone"})(p))
+	assert.Equal(t, []kube.FieldExtractionRule{
+		{Name: "k8s.namespace.label.team", Key: "team"},
+		{Name: "cost.center", Key: "cost-center", Regex: regexp.MustCompile("cc-(?P<value>.+)")},
+	}, p.rules.NamespaceLabels)
+	assert.Equal(t, []kube.FieldExtractionRule{
+		{Name: "k8s.node.label.topology.kubernetes.io/zone", Key: "topology.kubernetes.io/zone"},
+	}, p.rules.NodeLabels)
+
+	p = &kubernetesprocessor{}
+	assert.Error(t, WithExtractNamespaceLabels(FieldExtractConfig{Key: "team", Regex: "["})(p))
+	assert.Error(t, WithExtractNodeLabels(FieldExtractConfig{Key: "zone", Regex: "(?P<other>.+)"})(p))
+}
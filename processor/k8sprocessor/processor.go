@@ -20,6 +20,7 @@ import (
 	"go.opentelemetry.io/collector/client"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/k8sconfig"
@@ -38,6 +39,7 @@ type kubernetesprocessor struct {
 	passthroughMode bool
 	rules           kube.ExtractionRules
 	filters         kube.Filters
+	podAssociations []string
 }
 
 func (kp *kubernetesprocessor) initKubeClient(logger *zap.Logger, kubeClient kube.ClientProvider) error {
@@ -68,7 +70,7 @@ func (kp *kubernetesprocessor) Shutdown(context.Context) error {
 	return nil
 }
 
-// ProcessTraces process traces and add k8s metadata using resource IP or incoming IP as pod origin.
+// ProcessTraces process traces and add k8s metadata of the pod identified by the pod association sources.
 func (kp *kubernetesprocessor) ProcessTraces(ctx context.Context, td pdata.Traces) (pdata.Traces, error) {
 	rss := td.ResourceSpans()
 	for i := 0; i < rss.Len(); i++ {
@@ -83,7 +85,8 @@ func (kp *kubernetesprocessor) ProcessTraces(ctx context.Context, td pdata.Trace
 	return td, nil
 }
 
-// ProcessMetrics process metrics and add k8s metadata using resource IP, hostname or incoming IP as pod origin.
+// ProcessMetrics process metrics and add k8s metadata of the pod identified by the pod association sources,
+// an IP address in the hostname is also used as pod IP.
 func (kp *kubernetesprocessor) ProcessMetrics(ctx context.Context, md pdata.Metrics) (pdata.Metrics, error) {
 	rm := md.ResourceMetrics()
 	for i := 0; i < rm.Len(); i++ {
@@ -98,7 +101,7 @@ func (kp *kubernetesprocessor) ProcessMetrics(ctx context.Context, md pdata.Metr
 	return md, nil
 }
 
-// ProcessLogs process logs and add k8s metadata using resource IP, hostname or incoming IP as pod origin.
+// ProcessLogs process logs and add k8s metadata of the pod identified by the pod association sources.
 func (kp *kubernetesprocessor) ProcessLogs(ctx context.Context, ld pdata.Logs) (pdata.Logs, error) {
 	rl := ld.ResourceLogs()
 	for i := 0; i < rl.Len(); i++ {
@@ -114,56 +117,144 @@ func (kp *kubernetesprocessor) ProcessLogs(ctx context.Context, ld pdata.Logs) (
 }
 
 func (kp *kubernetesprocessor) processResource(ctx context.Context, resource pdata.Resource, attributeExtractors ...ipExtractor) {
-	var podIP string
-
-	if !resource.IsNil() {
-		for _, extractor := range attributeExtractors {
-			podIP = extractor(resource.Attributes())
-			if podIP != "" {
-				break
+	// The first IP address found is tagged when no source identifies a pod,
+	// the pod may just not be known yet.
+	var unknownPodIP string
+	for _, source := range kp.podAssociations {
+		switch source {
+		case podAssociationIP, podAssociationConnection:
+			podIP := kp.podIPFromSource(ctx, resource, source, attributeExtractors)
+			if podIP == "" {
+				continue
+			}
+			// Don't invoke any k8s client functionality in passthrough mode.
+			// Just tag the IP and forward the batch.
+			if kp.passthroughMode {
+				kp.tagPodIP(resource, podIP)
+				return
+			}
+			// An IP address that doesn't belong to a known pod, such as the one
+			// of a node-local agent in the host network, doesn't stop the search.
+			if pod, ok := kp.kc.GetPodByIP(podIP); ok {
+				kp.tagPodIP(resource, podIP)
+				kp.addPodAttributes(resource, pod)
+				return
+			}
+			if unknownPodIP == "" {
+				unknownPodIP = podIP
+			}
+		default:
+			// Don't invoke any k8s client functionality in passthrough mode.
+			if kp.passthroughMode || resource.IsNil() {
+				continue
+			}
+			if pod, ok := kp.podFromSource(resource.Attributes(), source); ok {
+				kp.addPodAttributes(resource, pod)
+				return
 			}
 		}
 	}
+	if unknownPodIP != "" {
+		kp.tagPodIP(resource, unknownPodIP)
+	}
+}
 
-	// Check if the receiver detected client IP.
-	if podIP == "" {
+// podIPFromSource returns the pod IP address detected from the resource
+// attributes or from the client connection.
+func (kp *kubernetesprocessor) podIPFromSource(ctx context.Context, resource pdata.Resource, source string, attributeExtractors []ipExtractor) string {
+	if source == podAssociationConnection {
+		// Check if the receiver detected client IP.
 		if c, ok := client.FromContext(ctx); ok {
-			podIP = c.IP
+			return c.IP
 		}
+		return ""
 	}
 
-	// If IP is still not available by this point, nothing can be tagged here. Return.
-	if podIP == "" {
-		return
+	if resource.IsNil() {
+		return ""
 	}
+	for _, extractor := range attributeExtractors {
+		if podIP := extractor(resource.Attributes()); podIP != "" {
+			return podIP
+		}
+	}
+	return ""
+}
 
-	if resource.IsNil() {
-		resource.InitEmpty()
+// podFromSource looks up the pod identified by the resource attributes
+// the source relies on.
+func (kp *kubernetesprocessor) podFromSource(attrs pdata.AttributeMap, source string) (*kube.Pod, bool) {
+	switch source {
+	case podAssociationPodUID:
+		return kp.kc.GetPodByUID(stringAttributeFromMap(attrs, conventions.AttributeK8sPodUID))
+	case podAssociationContainerID:
+		return kp.kc.GetPodByContainerID(stringAttributeFromMap(attrs, conventions.AttributeContainerID))
+	case podAssociationPodName:
+		name := stringAttributeFromMap(attrs, conventions.AttributeK8sPod)
+		namespace := stringAttributeFromMap(attrs, conventions.AttributeK8sNamespace)
+		if name == "" || namespace == "" {
+			return nil, false
+		}
+		return kp.kc.GetPodByName(namespace, name)
+	case podAssociationHostname:
+		namespace := stringAttributeFromMap(attrs, conventions.AttributeK8sNamespace)
+		return kp.kc.GetPodByHostname(namespace, stringAttributeFromMap(attrs, conventions.AttributeHostName))
 	}
-	resource.Attributes().InsertString(k8sIPLabelName, podIP)
+	return nil, false
+}
 
-	// Don't invoke any k8s client functionality in passthrough mode.
-	// Just tag the IP and forward the batch.
-	if kp.passthroughMode {
-		return
+// containerFromAttributes returns the container of the pod identified by the
+// k8s.container.name or the container.id resource attribute.
+func containerFromAttributes(pod *kube.Pod, attrs pdata.AttributeMap) (*kube.Container, bool) {
+	if len(pod.Containers) == 0 {
+		return nil, false
 	}
 
-	// add k8s tags to resource
-	attrsToAdd := kp.getAttributesForPodIP(podIP)
-	if len(attrsToAdd) == 0 {
-		return
+	if name := stringAttributeFromMap(attrs, conventions.AttributeK8sContainer); name != "" {
+		container, ok := pod.Containers[name]
+		return container, ok
 	}
 
-	attrs := resource.Attributes()
-	for k, v := range attrsToAdd {
-		attrs.InsertString(k, v)
+	if id := stringAttributeFromMap(attrs, conventions.AttributeContainerID); id != "" {
+		for _, container := range pod.Containers {
+			if container.ID == id {
+				return container, true
+			}
+		}
 	}
+	return nil, false
+}
+
+func (kp *kubernetesprocessor) tagPodIP(resource pdata.Resource, podIP string) {
+	if resource.IsNil() {
+		resource.InitEmpty()
+	}
+	resource.Attributes().InsertString(k8sIPLabelName, podIP)
 }
 
-func (kp *kubernetesprocessor) getAttributesForPodIP(ip string) map[string]string {
-	pod, ok := kp.kc.GetPodByIP(ip)
-	if !ok {
-		return nil
+// addPodAttributes adds k8s tags of the pod, its IP, its namespace and its node to the
+// resource, and of the container the resource describes if any.
+func (kp *kubernetesprocessor) addPodAttributes(resource pdata.Resource, pod *kube.Pod) {
+	attrs := resource.Attributes()
+	if pod.Address != "" {
+		attrs.InsertString(k8sIPLabelName, pod.Address)
+	}
+	for k, v := range pod.Attributes {
+		attrs.InsertString(k, v)
+	}
+	if container, ok := containerFromAttributes(pod, attrs); ok {
+		for k, v := range container.Attributes {
+			attrs.InsertString(k, v)
+		}
+	}
+	if ns, ok := kp.kc.GetNamespace(pod.Namespace); ok {
+		for k, v := range ns.Attributes {
+			attrs.InsertString(k, v)
+		}
+	}
+	if node, ok := kp.kc.GetNode(pod.NodeName); ok {
+		for k, v := range node.Attributes {
+			attrs.InsertString(k, v)
+		}
 	}
-	return pod.Attributes
 }
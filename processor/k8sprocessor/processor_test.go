@@ -298,6 +298,12 @@ func withHostname(hostname string) generateResourceFunc {
 	}
 }
 
+func withPodUID(uid string) generateResourceFunc {
+	return func(res pdata.Resource) {
+		res.Attributes().InsertString(conventions.AttributeK8sPodUID, uid)
+	}
+}
+
 func TestIPDetectionFromContext(t *testing.T) {
 	m := newMultiTest(t, NewFactory().CreateDefaultConfig(), nil)
 
@@ -490,6 +496,224 @@ func TestIPSource(t *testing.T) {
 	}
 }
 
+func TestPodAssociation(t *testing.T) {
+	m := newMultiTest(
+		t,
+		NewFactory().CreateDefaultConfig(),
+		nil,
+	)
+
+	m.kubernetesProcessorOperation(func(kp *kubernetesprocessor) {
+		kc := kp.kc.(*fakeClient)
+		kc.Pods["1.1.1.1"] = &kube.Pod{Attributes: map[string]string{"pod": "by-ip"}}
+		kc.PodsByUID["uid-1"] = &kube.Pod{Attributes: map[string]string{"pod": "by-uid"}}
+		kc.PodsByContainerID["container-1"] = &kube.Pod{Attributes: map[string]string{"pod": "by-container-id"}}
+		kc.PodsByName["ns/pod-1"] = &kube.Pod{Attributes: map[string]string{"pod": "by-name"}}
+		kc.PodsByHostname["host-1"] = &kube.Pod{Attributes: map[string]string{"pod": "by-hostname"}}
+		kc.PodsByHostname["ns/host-2"] = &kube.Pod{Attributes: map[string]string{"pod": "by-namespace-hostname"}}
+	})
+
+	testCases := []struct {
+		name  string
+		attrs map[string]string
+		out   string
+	}{
+		{
+			name: "pod uid first",
+			attrs: map[string]string{
+				"k8s.pod.uid":  "uid-1",
+				"container.id": "container-1",
+				"k8s.pod.ip":   "1.1.1.1",
+			},
+			out: "by-uid",
+		},
+		{
+			name: "container id",
+			attrs: map[string]string{
+				"k8s.pod.uid":  "unknown",
+				"container.id": "container-1",
+				"k8s.pod.ip":   "1.1.1.1",
+			},
+			out: "by-container-id",
+		},
+		{
+			name: "pod name and namespace",
+			attrs: map[string]string{
+				"k8s.pod.name":       "pod-1",
+				"k8s.namespace.name": "ns",
+				"host.name":          "host-1",
+			},
+			out: "by-name",
+		},
+		{
+			name: "pod name without namespace",
+			attrs: map[string]string{
+				"k8s.pod.name": "pod-1",
+				"host.name":    "host-1",
+			},
+			out: "by-hostname",
+		},
+		{
+			name: "hostname in namespace",
+			attrs: map[string]string{
+				"k8s.namespace.name": "ns",
+				"host.name":          "host-2",
+			},
+			out: "by-namespace-hostname",
+		},
+		{
+			name: "hostname before ip",
+			attrs: map[string]string{
+				"host.name":  "host-1",
+				"k8s.pod.ip": "1.1.1.1",
+			},
+			out: "by-hostname",
+		},
+		{
+			name: "ip",
+			attrs: map[string]string{
+				"host.name":  "unknown",
+				"k8s.pod.ip": "1.1.1.1",
+			},
+			out: "by-ip",
+		},
+	}
+
+	for i, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			setAttrs := func(res pdata.Resource) {
+				for k, v := range tc.attrs {
+					res.Attributes().InsertString(k, v)
+				}
+			}
+
+			m.testConsume(
+				context.Background(),
+				generateTraces(setAttrs),
+				generateMetrics(setAttrs),
+				generateLogs(setAttrs),
+				func(err error) {
+					assert.NoError(t, err)
+				})
+
+			m.assertBatchesLen(i + 1)
+			assertResourceHasStringAttribute(t, m.nextTrace.AllTraces()[i].ResourceSpans().At(0).Resource(), "pod", tc.out)
+			assertResourceHasStringAttribute(t, m.nextMetrics.AllMetrics()[i].ResourceMetrics().At(0).Resource(), "pod", tc.out)
+			assertResourceHasStringAttribute(t, m.nextLogs.AllLogs()[i].ResourceLogs().At(0).Resource(), "pod", tc.out)
+		})
+	}
+}
+
+func TestPodAssociationAgentHostname(t *testing.T) {
+	m := newMultiTest(t, NewFactory().CreateDefaultConfig(), nil)
+
+	m.kubernetesProcessorOperation(func(kp *kubernetesprocessor) {
+		kc := kp.kc.(*fakeClient)
+		kc.Pods["3.3.3.3"] = &kube.Pod{Address: "3.3.3.3", Attributes: map[string]string{"pod": "by-connection"}}
+		kc.PodsByHostname["app-1"] = &kube.Pod{Address: "4.4.4.4", Attributes: map[string]string{"pod": "app"}}
+	})
+
+	// data forwarded by a node-local agent carries the hostname of the pod it comes from
+	setAttrs := func(res pdata.Resource) {
+		res.Attributes().InsertString("host.name", "app-1")
+	}
+	ctx := client.NewContext(context.Background(), &client.Client{IP: "3.3.3.3"})
+	m.testConsume(ctx, generateTraces(setAttrs), generateMetrics(setAttrs), generateLogs(setAttrs), nil)
+
+	// the agent IP doesn't belong to a known pod, the search goes on to the connection
+	setAttrs = func(res pdata.Resource) {
+		res.Attributes().InsertString("host.name", "unknown")
+		res.Attributes().InsertString("k8s.pod.ip", "5.5.5.5")
+	}
+	m.testConsume(ctx, generateTraces(setAttrs), generateMetrics(setAttrs), generateLogs(setAttrs), nil)
+
+	// the first IP is still tagged when no pod is found
+	ctx = client.NewContext(context.Background(), &client.Client{IP: "6.6.6.6"})
+	m.testConsume(ctx, generateTraces(setAttrs), generateMetrics(setAttrs), generateLogs(setAttrs), nil)
+
+	m.assertBatchesLen(3)
+	m.assertResource(0, 0, func(res pdata.Resource) {
+		assertResourceHasStringAttribute(t, res, "pod", "app")
+		assertResourceHasStringAttribute(t, res, "k8s.pod.ip", "4.4.4.4")
+	})
+	m.assertResource(1, 0, func(res pdata.Resource) {
+		assertResourceHasStringAttribute(t, res, "pod", "by-connection")
+		assertResourceHasStringAttribute(t, res, "k8s.pod.ip", "5.5.5.5")
+	})
+	m.assertResource(2, 0, func(res pdata.Resource) {
+		assert.Equal(t, 2, res.Attributes().Len())
+		assertResourceHasStringAttribute(t, res, "k8s.pod.ip", "5.5.5.5")
+	})
+}
+
+func TestPodAssociationTagsPodIP(t *testing.T) {
+	m := newMultiTest(t, NewFactory().CreateDefaultConfig(), nil)
+
+	m.kubernetesProcessorOperation(func(kp *kubernetesprocessor) {
+		kc := kp.kc.(*fakeClient)
+		kc.PodsByUID["uid-1"] = &kube.Pod{Address: "1.1.1.1", Attributes: map[string]string{"pod": "by-uid"}}
+		kc.PodsByUID["uid-2"] = &kube.Pod{Address: "2.2.2.2", Attributes: map[string]string{"pod": "by-uid"}}
+	})
+
+	// the IP of the matched pod is added unless already set
+	m.testConsume(context.Background(),
+		generateTraces(withPodUID("uid-1")),
+		generateMetrics(withPodUID("uid-1")),
+		generateLogs(withPodUID("uid-1")),
+		nil)
+	setAttrs := func(res pdata.Resource) {
+		res.Attributes().InsertString("k8s.pod.uid", "uid-2")
+		res.Attributes().InsertString("k8s.pod.ip", "5.5.5.5")
+	}
+	m.testConsume(context.Background(), generateTraces(setAttrs), generateMetrics(setAttrs), generateLogs(setAttrs), nil)
+	m.assertBatchesLen(2)
+	m.assertResource(0, 0, func(res pdata.Resource) {
+		assertResourceHasStringAttribute(t, res, "pod", "by-uid")
+		assertResourceHasStringAttribute(t, res, "k8s.pod.ip", "1.1.1.1")
+	})
+	m.assertResource(1, 0, func(res pdata.Resource) {
+		assertResourceHasStringAttribute(t, res, "pod", "by-uid")
+		assertResourceHasStringAttribute(t, res, "k8s.pod.ip", "5.5.5.5")
+	})
+}
+
+func TestPodAssociationOrder(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.PodAssociation = []string{"connection", "pod_uid"}
+	m := newMultiTest(t, cfg, nil)
+
+	m.kubernetesProcessorOperation(func(kp *kubernetesprocessor) {
+		kc := kp.kc.(*fakeClient)
+		kc.Pods["3.3.3.3"] = &kube.Pod{Attributes: map[string]string{"pod": "by-connection"}}
+		kc.PodsByUID["uid-1"] = &kube.Pod{Attributes: map[string]string{"pod": "by-uid"}}
+		kc.PodsByHostname["host-1"] = &kube.Pod{Attributes: map[string]string{"pod": "by-hostname"}}
+	})
+
+	setAttrs := func(res pdata.Resource) {
+		res.Attributes().InsertString("k8s.pod.uid", "uid-1")
+		res.Attributes().InsertString("host.name", "host-1")
+	}
+
+	// the connection comes first
+	ctx := client.NewContext(context.Background(), &client.Client{IP: "3.3.3.3"})
+	m.testConsume(ctx, generateTraces(setAttrs), generateMetrics(setAttrs), generateLogs(setAttrs), nil)
+	m.assertBatchesLen(1)
+	m.assertResource(0, 0, func(res pdata.Resource) {
+		assertResourceHasStringAttribute(t, res, "pod", "by-connection")
+		assertResourceHasStringAttribute(t, res, "k8s.pod.ip", "3.3.3.3")
+	})
+
+	// hostname isn't configured
+	m.testConsume(context.Background(), generateTraces(setAttrs), generateMetrics(setAttrs), generateLogs(setAttrs), nil)
+	m.assertBatchesLen(2)
+	m.assertResource(1, 0, func(res pdata.Resource) {
+		assertResourceHasStringAttribute(t, res, "pod", "by-uid")
+		_, ok := res.Attributes().Get("k8s.pod.ip")
+		assert.False(t, ok)
+	})
+}
+
 func TestProcessorAddLabels(t *testing.T) {
 	m := newMultiTest(
 		t,
@@ -539,6 +763,97 @@ func TestProcessorAddLabels(t *testing.T) {
 	}
 }
 
+func TestProcessorAddNamespaceAndNodeLabels(t *testing.T) {
+	m := newMultiTest(
+		t,
+		NewFactory().CreateDefaultConfig(),
+		nil,
+	)
+
+	m.kubernetesProcessorOperation(func(kp *kubernetesprocessor) {
+		kc := kp.kc.(*fakeClient)
+		kc.Pods["1.1.1.1"] = &kube.Pod{
+			Namespace:  "ns1",
+			NodeName:   "node1",
+			Attributes: map[string]string{"pod": "test-2323"},
+		}
+		kc.Namespaces["ns1"] = &kube.Namespace{Attributes: map[string]string{"team": "payments"}}
+		kc.Nodes["node1"] = &kube.Node{Attributes: map[string]string{"zone": "us-east-1a"}}
+	})
+
+	ctx := client.NewContext(context.Background(), &client.Client{IP: "1.1.1.1"})
+	m.testConsume(ctx, generateTraces(), generateMetrics(), generateLogs(), nil)
+
+	m.assertBatchesLen(1)
+	m.assertResource(0, 0, func(res pdata.Resource) {
+		require.False(t, res.IsNil())
+		assertResourceHasStringAttribute(t, res, "pod", "test-2323")
+		assertResourceHasStringAttribute(t, res, "team", "payments")
+		assertResourceHasStringAttribute(t, res, "zone", "us-east-1a")
+	})
+}
+
+func TestProcessorAddContainerAttributes(t *testing.T) {
+	m := newMultiTest(
+		t,
+		NewFactory().CreateDefaultConfig(),
+		nil,
+	)
+
+	m.kubernetesProcessorOperation(func(kp *kubernetesprocessor) {
+		kp.kc.(*fakeClient).Pods["1.1.1.1"] = &kube.Pod{
+			Attributes: map[string]string{"pod": "test-2323"},
+			Containers: map[string]*kube.Container{
+				"app": {
+					Name:       "app",
+					ID:         "app-id",
+					Attributes: map[string]string{"container.image.tag": "1.2.3"},
+				},
+				"sidecar": {
+					Name:       "sidecar",
+					ID:         "sidecar-id",
+					Attributes: map[string]string{"container.image.tag": "0.1"},
+				},
+			},
+		}
+	})
+
+	ctx := client.NewContext(context.Background(), &client.Client{IP: "1.1.1.1"})
+	tests := []struct {
+		name  string
+		attrs map[string]string
+		tag   string
+	}{
+		{"no container", nil, ""},
+		{"container name", map[string]string{"k8s.container.name": "app"}, "1.2.3"},
+		{"container id", map[string]string{"container.id": "sidecar-id"}, "0.1"},
+		{"unknown container", map[string]string{"k8s.container.name": "other"}, ""},
+	}
+
+	for i, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			setAttrs := func(res pdata.Resource) {
+				for k, v := range tt.attrs {
+					res.Attributes().InsertString(k, v)
+				}
+			}
+			m.testConsume(ctx, generateTraces(setAttrs), generateMetrics(setAttrs), generateLogs(setAttrs), nil)
+
+			m.assertBatchesLen(i + 1)
+			m.assertResource(i, 0, func(res pdata.Resource) {
+				assertResourceHasStringAttribute(t, res, "pod", "test-2323")
+				if tt.tag == "" {
+					_, ok := res.Attributes().Get("container.image.tag")
+					assert.False(t, ok)
+				} else {
+					assertResourceHasStringAttribute(t, res, "container.image.tag", tt.tag)
+				}
+			})
+		})
+	}
+}
+
 func TestProcessorPicksUpPassthoughPodIp(t *testing.T) {
 	m := newMultiTest(
 		t,
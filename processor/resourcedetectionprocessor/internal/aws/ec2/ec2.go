@@ -16,8 +16,13 @@ package ec2
 
 import (
 	"context"
+	"fmt"
+	"regexp"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/translator/conventions"
 
@@ -27,20 +32,40 @@ import (
 const (
 	TypeStr          = "ec2"
 	cloudProviderAWS = "aws"
+
+	tagPrefix = "ec2.tag."
 )
 
 var _ internal.Detector = (*Detector)(nil)
 
 type Detector struct {
-	provider ec2MetadataProvider
+	provider      ec2MetadataProvider
+	tagKeyRegexes []*regexp.Regexp
+	// newEC2Client creates the client used to describe the instance tags
+	// in the region of the instance.
+	newEC2Client func(region string) ec2iface.EC2API
 }
 
-func NewDetector() (internal.Detector, error) {
+func NewDetector(dcfg internal.DetectorConfig) (internal.Detector, error) {
 	sess, err := session.NewSession()
 	if err != nil {
 		return nil, err
 	}
-	return &Detector{provider: &ec2MetadataImpl{sess: sess}}, nil
+
+	var tagKeyRegexes []*regexp.Regexp
+	if cfg, ok := dcfg.(Config); ok {
+		if tagKeyRegexes, err = compileRegexes(cfg.Tags); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Detector{
+		provider:      &ec2MetadataImpl{sess: sess},
+		tagKeyRegexes: tagKeyRegexes,
+		newEC2Client: func(region string) ec2iface.EC2API {
+			return ec2.New(sess, aws.NewConfig().WithRegion(region))
+		},
+	}, nil
 }
 
 func (d *Detector) Detect(ctx context.Context) (pdata.Resource, error) {
@@ -65,5 +90,62 @@ func (d *Detector) Detect(ctx context.Context) (pdata.Resource, error) {
 	attr.InsertString(conventions.AttributeHostImageID, meta.ImageID)
 	attr.InsertString(conventions.AttributeHostType, meta.InstanceType)
 
+	if len(d.tagKeyRegexes) != 0 {
+		tags, err := fetchEC2Tags(ctx, d.newEC2Client(meta.Region), meta.InstanceID, d.tagKeyRegexes)
+		if err != nil {
+			return res, fmt.Errorf("failed fetching ec2 instance tags: %w", err)
+		}
+		for key, val := range tags {
+			attr.InsertString(tagPrefix+key, val)
+		}
+	}
+
 	return res, nil
 }
+
+// fetchEC2Tags returns the tags of the given instance whose keys match
+// any of the regexes, using the credentials of the default chain.
+func fetchEC2Tags(ctx context.Context, svc ec2iface.EC2API, instanceID string, tagKeyRegexes []*regexp.Regexp) (map[string]string, error) {
+	input := &ec2.DescribeTagsInput{
+		Filters: []*ec2.Filter{{
+			Name:   aws.String("resource-id"),
+			Values: []*string{aws.String(instanceID)},
+		}},
+	}
+
+	tags := make(map[string]string)
+	err := svc.DescribeTagsPagesWithContext(ctx, input, func(page *ec2.DescribeTagsOutput, lastPage bool) bool {
+		for _, tag := range page.Tags {
+			key := aws.StringValue(tag.Key)
+			if regexArrayMatch(tagKeyRegexes, key) {
+				tags[key] = aws.StringValue(tag.Value)
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+func compileRegexes(tags []string) ([]*regexp.Regexp, error) {
+	regexes := make([]*regexp.Regexp, 0, len(tags))
+	for _, elem := range tags {
+		regex, err := regexp.Compile(elem)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ec2 tag regex %q: %w", elem, err)
+		}
+		regexes = append(regexes, regex)
+	}
+	return regexes, nil
+}
+
+func regexArrayMatch(arr []*regexp.Regexp, val string) bool {
+	for _, elem := range arr {
+		if elem.MatchString(val) {
+			return true
+		}
+	}
+	return false
+}
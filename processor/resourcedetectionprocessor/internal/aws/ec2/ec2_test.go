@@ -17,9 +17,14 @@ package ec2
 import (
 	"context"
 	"errors"
+	"regexp"
 	"testing"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/ec2metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/consumer/pdata"
@@ -46,15 +51,55 @@ func (mm mockMetadata) get(ctx context.Context) (ec2metadata.EC2InstanceIdentity
 	return mm.ret, nil
 }
 
+type mockEC2Client struct {
+	ec2iface.EC2API
+	region    string
+	tags      []*ec2.TagDescription
+	returnErr error
+}
+
+func (mc *mockEC2Client) DescribeTagsPagesWithContext(_ aws.Context, input *ec2.DescribeTagsInput, fn func(*ec2.DescribeTagsOutput, bool) bool, _ ...request.Option) error {
+	if mc.returnErr != nil {
+		return mc.returnErr
+	}
+	if aws.StringValue(input.Filters[0].Name) != "resource-id" || aws.StringValue(input.Filters[0].Values[0]) != "i-abcd1234" {
+		return errors.New("unexpected filter")
+	}
+	// Return the tags over two pages.
+	half := len(mc.tags) / 2
+	if fn(&ec2.DescribeTagsOutput{Tags: mc.tags[:half]}, false) {
+		fn(&ec2.DescribeTagsOutput{Tags: mc.tags[half:]}, true)
+	}
+	return nil
+}
+
+func newMockEC2ClientFactory(t *testing.T, client *mockEC2Client) func(string) ec2iface.EC2API {
+	return func(region string) ec2iface.EC2API {
+		assert.Equal(t, client.region, region)
+		return client
+	}
+}
+
 func TestNewDetector(t *testing.T) {
-	detector, err := NewDetector()
+	detector, err := NewDetector(nil)
 	assert.NotNil(t, detector)
 	assert.NoError(t, err)
 }
 
+func TestNewDetectorWithTags(t *testing.T) {
+	detector, err := NewDetector(Config{Tags: []string{"^team$", "^cost-.*"}})
+	require.NoError(t, err)
+	assert.Len(t, detector.(*Detector).tagKeyRegexes, 2)
+
+	_, err = NewDetector(Config{Tags: []string{"("}})
+	assert.Error(t, err)
+}
+
 func TestDetector_Detect(t *testing.T) {
 	type fields struct {
-		provider ec2MetadataProvider
+		provider      ec2MetadataProvider
+		tagKeyRegexes []*regexp.Regexp
+		client        *mockEC2Client
 	}
 	type args struct {
 		ctx context.Context
@@ -91,6 +136,55 @@ func TestDetector_Detect(t *testing.T) {
 				attr.InsertString("host.type", "c4.xlarge")
 				return res
 			}()},
+		{
+			name: "success with tags",
+			fields: fields{
+				provider: &mockMetadata{ret: ec2metadata.EC2InstanceIdentityDocument{
+					Region:           "us-west-2",
+					AccountID:        "account1234",
+					AvailabilityZone: "us-west-2a",
+					InstanceID:       "i-abcd1234",
+					ImageID:          "abcdef",
+					InstanceType:     "c4.xlarge",
+				},
+					isAvailable: true},
+				tagKeyRegexes: []*regexp.Regexp{regexp.MustCompile("^team$"), regexp.MustCompile("^cost-")},
+				client: &mockEC2Client{region: "us-west-2", tags: []*ec2.TagDescription{
+					{Key: aws.String("team"), Value: aws.String("observability")},
+					{Key: aws.String("Name"), Value: aws.String("my-instance")},
+					{Key: aws.String("cost-center"), Value: aws.String("1234")},
+					{Key: aws.String("teams"), Value: aws.String("ignored")},
+				}},
+			},
+			args: args{ctx: context.Background()},
+			want: func() pdata.Resource {
+				res := pdata.NewResource()
+				res.InitEmpty()
+				attr := res.Attributes()
+				attr.InsertString("cloud.account.id", "account1234")
+				attr.InsertString("cloud.provider", "aws")
+				attr.InsertString("cloud.region", "us-west-2")
+				attr.InsertString("cloud.zone", "us-west-2a")
+				attr.InsertString("host.id", "i-abcd1234")
+				attr.InsertString("host.image.id", "abcdef")
+				attr.InsertString("host.type", "c4.xlarge")
+				attr.InsertString("ec2.tag.team", "observability")
+				attr.InsertString("ec2.tag.cost-center", "1234")
+				return res
+			}()},
+		{
+			name: "describe tags fails",
+			fields: fields{
+				provider: &mockMetadata{ret: ec2metadata.EC2InstanceIdentityDocument{
+					Region:     "us-west-2",
+					InstanceID: "i-abcd1234",
+				},
+					isAvailable: true},
+				tagKeyRegexes: []*regexp.Regexp{regexp.MustCompile(".*")},
+				client:        &mockEC2Client{region: "us-west-2", returnErr: errors.New("access denied")},
+			},
+			args:    args{ctx: context.Background()},
+			wantErr: true},
 		{
 			name: "endpoint not available",
 			fields: fields{provider: &mockMetadata{
@@ -123,7 +217,11 @@ func TestDetector_Detect(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			d := &Detector{
-				provider: tt.fields.provider,
+				provider:      tt.fields.provider,
+				tagKeyRegexes: tt.fields.tagKeyRegexes,
+			}
+			if tt.fields.client != nil {
+				d.newEC2Client = newMockEC2ClientFactory(t, tt.fields.client)
 			}
 			got, err := d.Detect(tt.args.ctx)
 
@@ -0,0 +1,133 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ecs provides a detector that loads resource information from
+// the ECS task metadata endpoint
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/resourcedetectionprocessor/internal"
+)
+
+const (
+	TypeStr          = "ecs"
+	cloudProviderAWS = "aws"
+
+	tmde3EnvVar = "ECS_CONTAINER_METADATA_URI"
+	tmde4EnvVar = "ECS_CONTAINER_METADATA_URI_V4"
+
+	attributeECSClusterARN   = "aws.ecs.cluster.arn"
+	attributeECSTaskARN      = "aws.ecs.task.arn"
+	attributeECSTaskFamily   = "aws.ecs.task.family"
+	attributeECSTaskRevision = "aws.ecs.task.revision"
+	attributeECSLaunchType   = "aws.ecs.launchtype"
+)
+
+var _ internal.Detector = (*Detector)(nil)
+
+type Detector struct {
+	provider ecsMetadataProvider
+}
+
+func NewDetector(internal.DetectorConfig) (internal.Detector, error) {
+	return &Detector{provider: newECSMetadata()}, nil
+}
+
+// Detect records metadata retrieved from the ECS Task Metadata Endpoint (TMDE) as resource attributes.
+// If neither the v3 nor the v4 endpoint is advertised through the environment an empty
+// resource is returned, as the collector is not running in an ECS task.
+func (d *Detector) Detect(ctx context.Context) (pdata.Resource, error) {
+	res := pdata.NewResource()
+	res.InitEmpty()
+
+	endpoint := getTmdeFromEnv()
+	if endpoint == "" {
+		return res, nil
+	}
+
+	task, err := d.provider.fetchTask(ctx, endpoint)
+	if err != nil {
+		return res, fmt.Errorf("failed to fetch task metadata: %w", err)
+	}
+
+	attr := res.Attributes()
+	attr.InsertString(conventions.AttributeCloudProvider, cloudProviderAWS)
+	attr.InsertString(attributeECSTaskARN, task.TaskARN)
+	attr.InsertString(attributeECSTaskFamily, task.Family)
+	attr.InsertString(attributeECSTaskRevision, task.Revision)
+
+	region, account := parseRegionAndAccount(task.TaskARN)
+	if region != "" {
+		attr.InsertString(conventions.AttributeCloudRegion, region)
+	}
+	if account != "" {
+		attr.InsertString(conventions.AttributeCloudAccount, account)
+	}
+
+	if task.AvailabilityZone != "" {
+		attr.InsertString(conventions.AttributeCloudZone, task.AvailabilityZone)
+	}
+
+	// The v3 endpoint does not report the launch type.
+	if task.LaunchType != "" {
+		attr.InsertString(attributeECSLaunchType, strings.ToLower(task.LaunchType))
+	}
+
+	if clusterARN := constructClusterARN(task.TaskARN, task.Cluster); clusterARN != "" {
+		attr.InsertString(attributeECSClusterARN, clusterARN)
+	}
+
+	return res, nil
+}
+
+func getTmdeFromEnv() string {
+	var endpoint string
+	if endpoint = strings.TrimSpace(os.Getenv(tmde4EnvVar)); endpoint == "" {
+		endpoint = strings.TrimSpace(os.Getenv(tmde3EnvVar))
+	}
+	return endpoint
+}
+
+// parseRegionAndAccount extracts the region and account from a task ARN of the form
+// "arn:aws:ecs:<region>:<account>:task/...".
+func parseRegionAndAccount(taskARN string) (region string, account string) {
+	parts := strings.Split(taskARN, ":")
+	if len(parts) < 6 {
+		return "", ""
+	}
+	return parts[3], parts[4]
+}
+
+// constructClusterARN returns the cluster ARN, building it from the task ARN
+// when the endpoint only reports the cluster name. On Fargate and with v4 the
+// full ARN is returned, while v3 on EC2 may only report the name.
+func constructClusterARN(taskARN, cluster string) string {
+	if cluster == "" || strings.HasPrefix(cluster, "arn:") {
+		return cluster
+	}
+
+	idx := strings.LastIndex(taskARN, ":")
+	if idx == -1 {
+		return ""
+	}
+	return fmt.Sprintf("%s:cluster/%s", taskARN[:idx], cluster)
+}
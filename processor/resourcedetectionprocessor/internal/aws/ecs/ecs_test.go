@@ -0,0 +1,133 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ecs
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/resourcedetectionprocessor/internal"
+)
+
+type mockMetadata struct {
+	task      *TaskMetadata
+	returnErr error
+}
+
+var _ ecsMetadataProvider = (*mockMetadata)(nil)
+
+func (mm *mockMetadata) fetchTask(context.Context, string) (*TaskMetadata, error) {
+	if mm.returnErr != nil {
+		return nil, mm.returnErr
+	}
+	return mm.task, nil
+}
+
+func TestNewDetector(t *testing.T) {
+	detector, err := NewDetector(nil)
+	assert.NotNil(t, detector)
+	assert.NoError(t, err)
+}
+
+func TestDetector_Detect(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		provider ecsMetadataProvider
+		want     pdata.Resource
+		wantErr  bool
+	}{
+		{
+			name: "v4 on fargate",
+			env:  map[string]string{tmde4EnvVar: "http://169.254.170.2/v4/abc"},
+			provider: &mockMetadata{task: &TaskMetadata{
+				Cluster:          "arn:aws:ecs:us-west-2:123456789123:cluster/my-cluster",
+				TaskARN:          "arn:aws:ecs:us-west-2:123456789123:task/123",
+				Family:           "family",
+				Revision:         "26",
+				AvailabilityZone: "us-west-2a",
+				LaunchType:       "FARGATE",
+			}},
+			want: internal.NewResource(map[string]interface{}{
+				"cloud.provider":        "aws",
+				"cloud.account.id":      "123456789123",
+				"cloud.region":          "us-west-2",
+				"cloud.zone":            "us-west-2a",
+				"aws.ecs.cluster.arn":   "arn:aws:ecs:us-west-2:123456789123:cluster/my-cluster",
+				"aws.ecs.task.arn":      "arn:aws:ecs:us-west-2:123456789123:task/123",
+				"aws.ecs.task.family":   "family",
+				"aws.ecs.task.revision": "26",
+				"aws.ecs.launchtype":    "fargate",
+			}),
+		},
+		{
+			name: "v3 with cluster name only",
+			env:  map[string]string{tmde3EnvVar: "http://169.254.170.2/v3/abc"},
+			provider: &mockMetadata{task: &TaskMetadata{
+				Cluster:  "my-cluster",
+				TaskARN:  "arn:aws:ecs:us-east-1:123456789123:task/123",
+				Family:   "family",
+				Revision: "3",
+			}},
+			want: internal.NewResource(map[string]interface{}{
+				"cloud.provider":        "aws",
+				"cloud.account.id":      "123456789123",
+				"cloud.region":          "us-east-1",
+				"aws.ecs.cluster.arn":   "arn:aws:ecs:us-east-1:123456789123:cluster/my-cluster",
+				"aws.ecs.task.arn":      "arn:aws:ecs:us-east-1:123456789123:task/123",
+				"aws.ecs.task.family":   "family",
+				"aws.ecs.task.revision": "3",
+			}),
+		},
+		{
+			name:     "not on ecs",
+			provider: &mockMetadata{returnErr: errors.New("should not be called")},
+			want:     internal.NewResource(map[string]interface{}{}),
+		},
+		{
+			name:     "fetch fails",
+			env:      map[string]string{tmde4EnvVar: "http://169.254.170.2/v4/abc"},
+			provider: &mockMetadata{returnErr: errors.New("fetch failed")},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Unsetenv(tmde3EnvVar)
+			os.Unsetenv(tmde4EnvVar)
+			for k, v := range tt.env {
+				os.Setenv(k, v)
+				defer os.Unsetenv(k)
+			}
+
+			d := &Detector{provider: tt.provider}
+			got, err := d.Detect(context.Background())
+
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.False(t, got.IsNil())
+			assert.Equal(t, internal.AttributesToMap(tt.want.Attributes()), internal.AttributesToMap(got.Attributes()))
+		})
+	}
+}
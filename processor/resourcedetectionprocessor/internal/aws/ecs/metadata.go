@@ -0,0 +1,71 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ecs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// TaskMetadata is the subset of the ECS task metadata response
+// used to populate resource attributes.
+type TaskMetadata struct {
+	Cluster          string
+	TaskARN          string
+	Family           string
+	Revision         string
+	AvailabilityZone string
+	LaunchType       string
+}
+
+type ecsMetadataProvider interface {
+	fetchTask(ctx context.Context, endpoint string) (*TaskMetadata, error)
+}
+
+type ecsMetadataImpl struct {
+	client *http.Client
+}
+
+var _ ecsMetadataProvider = (*ecsMetadataImpl)(nil)
+
+func newECSMetadata() *ecsMetadataImpl {
+	return &ecsMetadataImpl{client: &http.Client{}}
+}
+
+func (md *ecsMetadataImpl) fetchTask(ctx context.Context, endpoint string) (*TaskMetadata, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(endpoint, "/")+"/task", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := md.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("received %d status code from task metadata endpoint", resp.StatusCode)
+	}
+
+	task := &TaskMetadata{}
+	if err := json.NewDecoder(resp.Body).Decode(task); err != nil {
+		return nil, fmt.Errorf("failed to decode task metadata: %w", err)
+	}
+	return task, nil
+}
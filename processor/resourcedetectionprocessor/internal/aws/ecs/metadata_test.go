@@ -0,0 +1,73 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ecs
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const taskResponse = `{
+	"Cluster": "arn:aws:ecs:us-west-2:123456789123:cluster/my-cluster",
+	"TaskARN": "arn:aws:ecs:us-west-2:123456789123:task/my-cluster/123",
+	"Family": "family",
+	"Revision": "26",
+	"DesiredStatus": "RUNNING",
+	"KnownStatus": "RUNNING",
+	"AvailabilityZone": "us-west-2a",
+	"LaunchType": "FARGATE"
+}`
+
+func Test_ecsMetadataImpl_fetchTask(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v4/abc/task" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(taskResponse))
+	}))
+	defer ts.Close()
+
+	md := newECSMetadata()
+
+	task, err := md.fetchTask(context.Background(), ts.URL+"/v4/abc")
+	require.NoError(t, err)
+	assert.Equal(t, &TaskMetadata{
+		Cluster:          "arn:aws:ecs:us-west-2:123456789123:cluster/my-cluster",
+		TaskARN:          "arn:aws:ecs:us-west-2:123456789123:task/my-cluster/123",
+		Family:           "family",
+		Revision:         "26",
+		AvailabilityZone: "us-west-2a",
+		LaunchType:       "FARGATE",
+	}, task)
+
+	_, err = md.fetchTask(context.Background(), ts.URL+"/v3/unknown")
+	assert.Error(t, err)
+}
+
+func Test_ecsMetadataImpl_fetchTaskInvalidResponse(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("not json"))
+	}))
+	defer ts.Close()
+
+	_, err := newECSMetadata().fetchTask(context.Background(), ts.URL)
+	assert.Error(t, err)
+}
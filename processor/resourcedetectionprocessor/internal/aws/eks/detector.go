@@ -0,0 +1,120 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package eks provides a detector that loads resource information from
+// the Kubernetes API of an EKS cluster
+package eks
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/k8sconfig"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/resourcedetectionprocessor/internal"
+)
+
+const (
+	TypeStr          = "eks"
+	cloudProviderAWS = "aws"
+
+	kubernetesServiceHostEnvVar = "KUBERNETES_SERVICE_HOST"
+
+	// The aws-auth configmap only exists on EKS clusters.
+	authConfigNamespace = "kube-system"
+	authConfigMap       = "aws-auth"
+
+	// The cluster-info configmap is provisioned by the CloudWatch agent setup.
+	clusterInfoNamespace = "amazon-cloudwatch"
+	clusterInfoConfigMap = "cluster-info"
+	clusterNameKey       = "cluster.name"
+)
+
+var _ internal.Detector = (*Detector)(nil)
+
+type Detector struct {
+	// newClient is called lazily so that the detector can be
+	// created outside of a Kubernetes cluster.
+	newClient func() (kubernetes.Interface, error)
+}
+
+func NewDetector(internal.DetectorConfig) (internal.Detector, error) {
+	return &Detector{newClient: func() (kubernetes.Interface, error) {
+		return k8sconfig.MakeClient(k8sconfig.APIConfig{AuthType: k8sconfig.AuthTypeServiceAccount})
+	}}, nil
+}
+
+func (d *Detector) Detect(ctx context.Context) (pdata.Resource, error) {
+	res := pdata.NewResource()
+	res.InitEmpty()
+
+	// Not running in a Kubernetes pod.
+	if os.Getenv(kubernetesServiceHostEnvVar) == "" {
+		return res, nil
+	}
+
+	client, err := d.newClient()
+	if err != nil {
+		return res, fmt.Errorf("failed to create Kubernetes client: %w", err)
+	}
+
+	isEKS, err := isEKS(ctx, client)
+	if err != nil {
+		return res, err
+	}
+	if !isEKS {
+		return res, nil
+	}
+
+	attr := res.Attributes()
+	attr.InsertString(conventions.AttributeCloudProvider, cloudProviderAWS)
+
+	clusterName, err := getClusterName(ctx, client)
+	if err != nil {
+		return res, err
+	}
+	if clusterName != "" {
+		attr.InsertString(conventions.AttributeK8sCluster, clusterName)
+	}
+
+	return res, nil
+}
+
+func isEKS(ctx context.Context, client kubernetes.Interface) (bool, error) {
+	_, err := client.CoreV1().ConfigMaps(authConfigNamespace).Get(ctx, authConfigMap, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get %s/%s configmap: %w", authConfigNamespace, authConfigMap, err)
+	}
+	return true, nil
+}
+
+func getClusterName(ctx context.Context, client kubernetes.Interface) (string, error) {
+	cm, err := client.CoreV1().ConfigMaps(clusterInfoNamespace).Get(ctx, clusterInfoConfigMap, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get %s/%s configmap: %w", clusterInfoNamespace, clusterInfoConfigMap, err)
+	}
+	return cm.Data[clusterNameKey], nil
+}
@@ -0,0 +1,120 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eks
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/resourcedetectionprocessor/internal"
+)
+
+func newTestDetector(objects ...runtime.Object) *Detector {
+	client := fake.NewSimpleClientset(objects...)
+	return &Detector{newClient: func() (kubernetes.Interface, error) {
+		return client, nil
+	}}
+}
+
+func configMap(namespace, name string, data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Data:       data,
+	}
+}
+
+func TestNewDetector(t *testing.T) {
+	d, err := NewDetector(nil)
+	assert.NotNil(t, d)
+	assert.NoError(t, err)
+}
+
+func TestNotInKubernetes(t *testing.T) {
+	os.Unsetenv(kubernetesServiceHostEnvVar)
+
+	d := &Detector{newClient: func() (kubernetes.Interface, error) {
+		return nil, errors.New("should not be called")
+	}}
+	res, err := d.Detect(context.Background())
+	require.NoError(t, err)
+	assert.True(t, internal.IsEmptyResource(res))
+}
+
+func TestDetect(t *testing.T) {
+	os.Setenv(kubernetesServiceHostEnvVar, "localhost")
+	defer os.Unsetenv(kubernetesServiceHostEnvVar)
+
+	tests := []struct {
+		name    string
+		objects []runtime.Object
+		want    map[string]interface{}
+	}{
+		{
+			name: "eks with cluster name",
+			objects: []runtime.Object{
+				configMap(authConfigNamespace, authConfigMap, nil),
+				configMap(clusterInfoNamespace, clusterInfoConfigMap, map[string]string{clusterNameKey: "my-cluster"}),
+			},
+			want: map[string]interface{}{
+				"cloud.provider":   "aws",
+				"k8s.cluster.name": "my-cluster",
+			},
+		},
+		{
+			name: "eks without cluster info",
+			objects: []runtime.Object{
+				configMap(authConfigNamespace, authConfigMap, nil),
+			},
+			want: map[string]interface{}{
+				"cloud.provider": "aws",
+			},
+		},
+		{
+			name: "not eks",
+			objects: []runtime.Object{
+				configMap(clusterInfoNamespace, clusterInfoConfigMap, map[string]string{clusterNameKey: "my-cluster"}),
+			},
+			want: map[string]interface{}{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := newTestDetector(tt.objects...).Detect(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, internal.AttributesToMap(res.Attributes()))
+		})
+	}
+}
+
+func TestClientError(t *testing.T) {
+	os.Setenv(kubernetesServiceHostEnvVar, "localhost")
+	defer os.Unsetenv(kubernetesServiceHostEnvVar)
+
+	d := &Detector{newClient: func() (kubernetes.Interface, error) {
+		return nil, errors.New("no config")
+	}}
+	_, err := d.Detect(context.Background())
+	assert.Error(t, err)
+}
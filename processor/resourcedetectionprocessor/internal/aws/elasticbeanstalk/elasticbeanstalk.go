@@ -0,0 +1,113 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package elasticbeanstalk provides a detector that loads resource information from
+// the Elastic Beanstalk environment configuration file
+package elasticbeanstalk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strconv"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/resourcedetectionprocessor/internal"
+)
+
+const (
+	TypeStr          = "elastic_beanstalk"
+	cloudProviderAWS = "aws"
+
+	linuxPath   = "/var/elasticbeanstalk/xray/environment.conf"
+	windowsPath = "C:\\Program Files\\Amazon\\XRay\\environment.conf"
+)
+
+var _ internal.Detector = (*Detector)(nil)
+
+type Detector struct {
+	fs fileSystem
+}
+
+// EbMetaData is the content of the Elastic Beanstalk environment configuration file.
+type EbMetaData struct {
+	DeploymentID    int    `json:"deployment_id"`
+	EnvironmentName string `json:"environment_name"`
+	VersionLabel    string `json:"version_label"`
+}
+
+func NewDetector(internal.DetectorConfig) (internal.Detector, error) {
+	return &Detector{fs: &ebFileSystem{}}, nil
+}
+
+func (d *Detector) Detect(context.Context) (pdata.Resource, error) {
+	res := pdata.NewResource()
+	res.InitEmpty()
+
+	var conf string
+	if d.fs.IsWindows() {
+		conf = windowsPath
+	} else {
+		conf = linuxPath
+	}
+
+	// Not running on Elastic Beanstalk.
+	if !d.fs.Exists(conf) {
+		return res, nil
+	}
+
+	f, err := d.fs.Open(conf)
+	if err != nil {
+		return res, err
+	}
+	defer f.Close()
+
+	ebmd := &EbMetaData{}
+	if err := json.NewDecoder(f).Decode(ebmd); err != nil {
+		return res, fmt.Errorf("failed to decode Elastic Beanstalk configuration: %w", err)
+	}
+
+	attr := res.Attributes()
+	attr.InsertString(conventions.AttributeCloudProvider, cloudProviderAWS)
+	attr.InsertString(conventions.AttributeServiceInstance, strconv.Itoa(ebmd.DeploymentID))
+	attr.InsertString(conventions.AttributeDeploymentEnvironment, ebmd.EnvironmentName)
+	attr.InsertString(conventions.AttributeServiceVersion, ebmd.VersionLabel)
+	return res, nil
+}
+
+type fileSystem interface {
+	Open(name string) (io.ReadCloser, error)
+	Exists(name string) bool
+	IsWindows() bool
+}
+
+type ebFileSystem struct{}
+
+func (fs *ebFileSystem) Open(name string) (io.ReadCloser, error) {
+	return os.Open(name)
+}
+
+func (fs *ebFileSystem) Exists(name string) bool {
+	_, err := os.Stat(name)
+	return err == nil
+}
+
+func (fs *ebFileSystem) IsWindows() bool {
+	return runtime.GOOS == "windows"
+}
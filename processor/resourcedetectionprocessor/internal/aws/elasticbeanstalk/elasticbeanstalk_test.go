@@ -0,0 +1,102 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticbeanstalk
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/resourcedetectionprocessor/internal"
+)
+
+const xrayConf = "{\"deployment_id\":23,\"version_label\":\"env-version-1234\",\"environment_name\":\"BETA\"}"
+
+type mockFileSystem struct {
+	windows  bool
+	exists   bool
+	path     string
+	contents string
+	openErr  error
+}
+
+func (mfs *mockFileSystem) Open(path string) (io.ReadCloser, error) {
+	if mfs.openErr != nil {
+		return nil, mfs.openErr
+	}
+	if !mfs.exists || path != mfs.path {
+		return nil, errors.New("file not found")
+	}
+	return ioutil.NopCloser(strings.NewReader(mfs.contents)), nil
+}
+
+func (mfs *mockFileSystem) Exists(path string) bool {
+	return mfs.exists && path == mfs.path
+}
+
+func (mfs *mockFileSystem) IsWindows() bool {
+	return mfs.windows
+}
+
+func TestNewDetector(t *testing.T) {
+	d, err := NewDetector(nil)
+	assert.NotNil(t, d)
+	assert.NoError(t, err)
+}
+
+func TestFileNotExists(t *testing.T) {
+	d := &Detector{fs: &mockFileSystem{exists: false}}
+	res, err := d.Detect(context.Background())
+	require.NoError(t, err)
+	assert.True(t, internal.IsEmptyResource(res))
+}
+
+func TestOpenFails(t *testing.T) {
+	d := &Detector{fs: &mockFileSystem{exists: true, path: linuxPath, openErr: errors.New("open failed")}}
+	_, err := d.Detect(context.Background())
+	assert.Error(t, err)
+}
+
+func TestFileMalformed(t *testing.T) {
+	d := &Detector{fs: &mockFileSystem{exists: true, path: linuxPath, contents: "some overwritten value"}}
+	res, err := d.Detect(context.Background())
+	assert.Error(t, err)
+	assert.True(t, internal.IsEmptyResource(res))
+}
+
+func TestAttributesDetectedSuccessfully(t *testing.T) {
+	for _, fs := range []*mockFileSystem{
+		{exists: true, path: linuxPath, contents: xrayConf},
+		{windows: true, exists: true, path: windowsPath, contents: xrayConf},
+	} {
+		d := &Detector{fs: fs}
+		res, err := d.Detect(context.Background())
+		require.NoError(t, err)
+
+		expected := internal.NewResource(map[string]interface{}{
+			"cloud.provider":         "aws",
+			"service.instance.id":    "23",
+			"deployment.environment": "BETA",
+			"service.version":        "env-version-1234",
+		})
+		assert.Equal(t, internal.AttributesToMap(expected.Attributes()), internal.AttributesToMap(res.Attributes()))
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package aks provides a detector that loads resource information
+// for Kubernetes nodes running on Azure Kubernetes Service
+package aks
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/resourcedetectionprocessor/internal"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/resourcedetectionprocessor/internal/azure"
+)
+
+const (
+	TypeStr = "aks"
+
+	// Environment variable that is set when running on Kubernetes
+	kubernetesServiceHostEnvVar = "KUBERNETES_SERVICE_HOST"
+
+	// AKS places the nodes of a cluster in a resource group
+	// named "MC_<resource group>_<cluster name>_<location>".
+	nodeResourceGroupPrefix = "MC_"
+)
+
+var _ internal.Detector = (*Detector)(nil)
+
+type Detector struct {
+	provider azure.Provider
+}
+
+// NewDetector creates a new AKS detector
+func NewDetector(internal.DetectorConfig) (internal.Detector, error) {
+	return &Detector{provider: azure.NewProvider()}, nil
+}
+
+func (d *Detector) Detect(ctx context.Context) (pdata.Resource, error) {
+	res := pdata.NewResource()
+	res.InitEmpty()
+
+	// Check if running on Kubernetes
+	if os.Getenv(kubernetesServiceHostEnvVar) == "" {
+		return res, nil
+	}
+
+	// Check if running on Azure
+	compute, err := d.provider.Metadata(ctx)
+	if errors.Is(err, azure.ErrNotOnAzure) {
+		return res, nil
+	}
+	if err != nil {
+		return res, err
+	}
+
+	attr := res.Attributes()
+	attr.InsertString(conventions.AttributeCloudProvider, azure.CloudProviderAzure)
+	if clusterName := parseClusterName(compute.ResourceGroupName, compute.Location); clusterName != "" {
+		attr.InsertString(conventions.AttributeK8sCluster, clusterName)
+	}
+
+	return res, nil
+}
+
+// parseClusterName extracts the cluster name from the node resource group. Since resource
+// group and cluster names may both contain underscores, the name is only returned when
+// it can be determined unambiguously.
+func parseClusterName(resourceGroup, location string) string {
+	suffix := "_" + location
+	if !strings.HasPrefix(resourceGroup, nodeResourceGroupPrefix) || !strings.HasSuffix(resourceGroup, suffix) {
+		return ""
+	}
+
+	parts := strings.Split(strings.TrimSuffix(strings.TrimPrefix(resourceGroup, nodeResourceGroupPrefix), suffix), "_")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return ""
+	}
+	return parts[1]
+}
@@ -0,0 +1,109 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/resourcedetectionprocessor/internal"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/resourcedetectionprocessor/internal/azure"
+)
+
+type mockProvider struct {
+	mock.Mock
+}
+
+func (m *mockProvider) Metadata(context.Context) (*azure.ComputeMetadata, error) {
+	args := m.MethodCalled("Metadata")
+	return args.Get(0).(*azure.ComputeMetadata), args.Error(1)
+}
+
+func TestNewDetector(t *testing.T) {
+	d, err := NewDetector(nil)
+	require.NoError(t, err)
+	assert.NotNil(t, d)
+}
+
+func TestDetector_Detect(t *testing.T) {
+	os.Unsetenv(kubernetesServiceHostEnvVar)
+
+	// Not on Kubernetes
+	mp := &mockProvider{}
+	detector := &Detector{provider: mp}
+	res, err := detector.Detect(context.Background())
+	require.NoError(t, err)
+	assert.True(t, internal.IsEmptyResource(res))
+	mp.AssertNotCalled(t, "Metadata")
+
+	os.Setenv(kubernetesServiceHostEnvVar, "localhost")
+	defer os.Unsetenv(kubernetesServiceHostEnvVar)
+
+	// On Kubernetes but not on Azure
+	mp = &mockProvider{}
+	mp.On("Metadata").Return(&azure.ComputeMetadata{}, fmt.Errorf("%w: not on azure", azure.ErrNotOnAzure))
+	detector = &Detector{provider: mp}
+	res, err = detector.Detect(context.Background())
+	require.NoError(t, err)
+	assert.True(t, internal.IsEmptyResource(res))
+
+	// On Azure but the IMDS reply is invalid
+	mp = &mockProvider{}
+	mp.On("Metadata").Return(&azure.ComputeMetadata{}, errors.New("invalid reply"))
+	detector = &Detector{provider: mp}
+	res, err = detector.Detect(context.Background())
+	assert.EqualError(t, err, "invalid reply")
+	assert.True(t, internal.IsEmptyResource(res))
+
+	// On AKS
+	mp = &mockProvider{}
+	mp.On("Metadata").Return(&azure.ComputeMetadata{
+		Location:          "westeurope",
+		ResourceGroupName: "MC_myResourceGroup_myCluster_westeurope",
+	}, nil)
+	detector = &Detector{provider: mp}
+	res, err = detector.Detect(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"cloud.provider":   "azure",
+		"k8s.cluster.name": "myCluster",
+	}, internal.AttributesToMap(res.Attributes()))
+}
+
+func TestParseClusterName(t *testing.T) {
+	tests := []struct {
+		name          string
+		resourceGroup string
+		location      string
+		want          string
+	}{
+		{name: "valid", resourceGroup: "MC_group_cluster_eastus", location: "eastus", want: "cluster"},
+		{name: "not a node resource group", resourceGroup: "group", location: "eastus", want: ""},
+		{name: "other location", resourceGroup: "MC_group_cluster_eastus", location: "westus", want: ""},
+		{name: "ambiguous", resourceGroup: "MC_my_group_cluster_eastus", location: "eastus", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseClusterName(tt.resourceGroup, tt.location))
+		})
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package azure provides a detector that loads resource information from
+// the Azure Instance Metadata Service
+package azure
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/resourcedetectionprocessor/internal"
+)
+
+const (
+	TypeStr            = "azure"
+	CloudProviderAzure = "azure"
+
+	attributeAzureResourceGroupName = "azure.resourcegroup.name"
+	attributeAzureVMScaleSetName    = "azure.vm.scaleset.name"
+)
+
+var _ internal.Detector = (*Detector)(nil)
+
+// Detector is an Azure metadata detector
+type Detector struct {
+	provider Provider
+}
+
+// NewDetector creates a new Azure metadata detector
+func NewDetector(internal.DetectorConfig) (internal.Detector, error) {
+	return &Detector{provider: NewProvider()}, nil
+}
+
+// Detect detects associated resources when running in Azure environment.
+func (d *Detector) Detect(ctx context.Context) (pdata.Resource, error) {
+	res := pdata.NewResource()
+	res.InitEmpty()
+
+	compute, err := d.provider.Metadata(ctx)
+	if errors.Is(err, ErrNotOnAzure) {
+		return res, nil
+	}
+	if err != nil {
+		return res, err
+	}
+
+	attr := res.Attributes()
+	attr.InsertString(conventions.AttributeCloudProvider, CloudProviderAzure)
+	attr.InsertString(conventions.AttributeHostName, compute.Name)
+	attr.InsertString(conventions.AttributeCloudRegion, compute.Location)
+	attr.InsertString(conventions.AttributeHostID, compute.VMID)
+	attr.InsertString(conventions.AttributeCloudAccount, compute.SubscriptionID)
+	attr.InsertString(conventions.AttributeHostType, compute.VMSize)
+	attr.InsertString(attributeAzureResourceGroupName, compute.ResourceGroupName)
+	if compute.VMScaleSetName != "" {
+		attr.InsertString(attributeAzureVMScaleSetName, compute.VMScaleSetName)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,111 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/translator/conventions"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/resourcedetectionprocessor/internal"
+)
+
+type mockProvider struct {
+	mock.Mock
+}
+
+func (m *mockProvider) Metadata(context.Context) (*ComputeMetadata, error) {
+	args := m.MethodCalled("Metadata")
+	return args.Get(0).(*ComputeMetadata), args.Error(1)
+}
+
+func TestNewDetector(t *testing.T) {
+	d, err := NewDetector(nil)
+	require.NoError(t, err)
+	assert.NotNil(t, d)
+}
+
+func TestDetectAzureAvailable(t *testing.T) {
+	mp := &mockProvider{}
+	mp.On("Metadata").Return(&ComputeMetadata{
+		Location:          "location",
+		Name:              "name",
+		VMID:              "vmID",
+		VMSize:            "vmSize",
+		SubscriptionID:    "subscriptionID",
+		ResourceGroupName: "resourceGroup",
+		VMScaleSetName:    "myScaleset",
+	}, nil)
+
+	detector := &Detector{provider: mp}
+	res, err := detector.Detect(context.Background())
+	require.NoError(t, err)
+	mp.AssertExpectations(t)
+
+	expected := internal.NewResource(map[string]interface{}{
+		conventions.AttributeCloudProvider: "azure",
+		conventions.AttributeHostName:      "name",
+		conventions.AttributeCloudRegion:   "location",
+		conventions.AttributeHostID:        "vmID",
+		conventions.AttributeCloudAccount:  "subscriptionID",
+		conventions.AttributeHostType:      "vmSize",
+		"azure.resourcegroup.name":         "resourceGroup",
+		"azure.vm.scaleset.name":           "myScaleset",
+	})
+
+	res.Attributes().Sort()
+	expected.Attributes().Sort()
+	assert.Equal(t, expected, res)
+}
+
+func TestDetectNotOnAzure(t *testing.T) {
+	mp := &mockProvider{}
+	mp.On("Metadata").Return(&ComputeMetadata{}, fmt.Errorf("%w: mock error", ErrNotOnAzure))
+
+	detector := &Detector{provider: mp}
+	res, err := detector.Detect(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, internal.IsEmptyResource(res))
+}
+
+func TestDetectError(t *testing.T) {
+	mp := &mockProvider{}
+	mp.On("Metadata").Return(&ComputeMetadata{}, errors.New("mock error"))
+
+	detector := &Detector{provider: mp}
+	res, err := detector.Detect(context.Background())
+	assert.EqualError(t, err, "mock error")
+	assert.True(t, internal.IsEmptyResource(res))
+}
+
+func TestDetectNullMetadata(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("null"))
+	}))
+	defer ts.Close()
+
+	detector := &Detector{provider: &azureProviderImpl{endpoint: ts.URL, client: &http.Client{}}}
+	res, err := detector.Detect(context.Background())
+	assert.EqualError(t, err, "Azure IMDS replied with no compute metadata")
+	assert.True(t, internal.IsEmptyResource(res))
+}
@@ -0,0 +1,130 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"syscall"
+)
+
+const (
+	// Azure IMDS compute endpoint, see https://aka.ms/azureimds
+	metadataEndpoint = "http://169.254.169.254/metadata/instance/compute"
+)
+
+// ErrNotOnAzure is returned by the provider when the Azure IMDS can't be
+// reached, which means the collector isn't running on an Azure VM.
+var ErrNotOnAzure = errors.New("Azure IMDS is unreachable")
+
+// Provider gets metadata from the Azure IMDS.
+type Provider interface {
+	Metadata(context.Context) (*ComputeMetadata, error)
+}
+
+type azureProviderImpl struct {
+	endpoint string
+	client   *http.Client
+}
+
+// NewProvider creates a new metadata provider
+func NewProvider() Provider {
+	return &azureProviderImpl{
+		endpoint: metadataEndpoint,
+		client:   &http.Client{},
+	}
+}
+
+// ComputeMetadata is the Azure IMDS compute metadata response format
+type ComputeMetadata struct {
+	Location          string `json:"location"`
+	Name              string `json:"name"`
+	VMID              string `json:"vmId"`
+	VMSize            string `json:"vmSize"`
+	SubscriptionID    string `json:"subscriptionId"`
+	ResourceGroupName string `json:"resourceGroupName"`
+	VMScaleSetName    string `json:"vmScaleSetName"`
+}
+
+// Metadata queries a given endpoint and parses the output to the Azure IMDS format
+func (p *azureProviderImpl) Metadata(ctx context.Context) (*ComputeMetadata, error) {
+	const (
+		// API version used
+		apiVersionKey = "api-version"
+		apiVersion    = "2020-09-01"
+
+		// format used
+		formatKey  = "format"
+		jsonFormat = "json"
+	)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Metadata", "True")
+	q := req.URL.Query()
+	q.Add(formatKey, jsonFormat)
+	q.Add(apiVersionKey, apiVersion)
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		if isUnreachable(err) {
+			return nil, fmt.Errorf("%w: %v", ErrNotOnAzure, err)
+		}
+		return nil, fmt.Errorf("failed to query Azure IMDS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Azure IMDS replied with status code: %s", resp.Status)
+	}
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Azure IMDS reply: %w", err)
+	}
+
+	var metadata *ComputeMetadata
+	err = json.Unmarshal(respBody, &metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode Azure IMDS reply: %w", err)
+	}
+	if metadata == nil {
+		return nil, errors.New("Azure IMDS replied with no compute metadata")
+	}
+
+	return metadata, nil
+}
+
+// isUnreachable returns whether the IMDS request failed because nothing
+// listens on the IMDS address, or because it didn't answer in time.
+func isUnreachable(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EHOSTUNREACH) ||
+		errors.Is(err, syscall.ENETUNREACH) ||
+		errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,61 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package docker provides a detector that loads resource information from
+// the local Docker daemon
+package docker
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/resourcedetectionprocessor/internal"
+)
+
+const (
+	TypeStr = "docker"
+)
+
+var _ internal.Detector = (*Detector)(nil)
+
+type Detector struct {
+	provider dockerMetadata
+}
+
+func NewDetector(internal.DetectorConfig) (internal.Detector, error) {
+	provider, err := newDockerMetadata()
+	if err != nil {
+		return nil, fmt.Errorf("failed creating detector: %w", err)
+	}
+	return &Detector{provider: provider}, nil
+}
+
+func (d *Detector) Detect(ctx context.Context) (pdata.Resource, error) {
+	res := pdata.NewResource()
+	res.InitEmpty()
+
+	info, err := d.provider.HostInfo(ctx)
+	if err != nil {
+		return res, fmt.Errorf("failed getting Docker host info: %w", err)
+	}
+
+	attr := res.Attributes()
+	attr.InsertString(conventions.AttributeHostName, info.Hostname)
+	attr.InsertString(conventions.AttributeOSType, info.OSType)
+
+	return res, nil
+}
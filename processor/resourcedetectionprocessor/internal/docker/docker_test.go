@@ -0,0 +1,72 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/translator/conventions"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/resourcedetectionprocessor/internal"
+)
+
+type mockMetadata struct {
+	mock.Mock
+}
+
+func (m *mockMetadata) HostInfo(context.Context) (hostInfo, error) {
+	args := m.MethodCalled("HostInfo")
+	return args.Get(0).(hostInfo), args.Error(1)
+}
+
+func TestNewDetector(t *testing.T) {
+	d, err := NewDetector(nil)
+	assert.NotNil(t, d)
+	assert.NoError(t, err)
+}
+
+func TestDetect(t *testing.T) {
+	md := &mockMetadata{}
+	md.On("HostInfo").Return(hostInfo{Hostname: "hostname", OSType: "DARWIN"}, nil).Once()
+
+	detector := &Detector{provider: md}
+	res, err := detector.Detect(context.Background())
+	require.NoError(t, err)
+	md.AssertExpectations(t)
+
+	expected := internal.NewResource(map[string]interface{}{
+		conventions.AttributeHostName: "hostname",
+		conventions.AttributeOSType:   "DARWIN",
+	})
+
+	res.Attributes().Sort()
+	expected.Attributes().Sort()
+	assert.Equal(t, expected, res)
+}
+
+func TestDetectError(t *testing.T) {
+	md := &mockMetadata{}
+	md.On("HostInfo").Return(hostInfo{}, errors.New("err"))
+
+	detector := &Detector{provider: md}
+	res, err := detector.Detect(context.Background())
+	assert.Error(t, err)
+	assert.True(t, internal.IsEmptyResource(res))
+}
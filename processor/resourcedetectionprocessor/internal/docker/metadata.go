@@ -0,0 +1,58 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"context"
+	"fmt"
+
+	docker "github.com/docker/docker/client"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/resourcedetectionprocessor/internal"
+)
+
+type dockerMetadata interface {
+	// HostInfo returns the hostname and operating system of the Docker daemon host
+	HostInfo(ctx context.Context) (hostInfo, error)
+}
+
+// hostInfo holds the metadata of the Docker daemon host.
+type hostInfo struct {
+	Hostname string
+	OSType   string
+}
+
+type dockerMetadataImpl struct {
+	dockerClient *docker.Client
+}
+
+var _ dockerMetadata = (*dockerMetadataImpl)(nil)
+
+func newDockerMetadata(opts ...docker.Opt) (*dockerMetadataImpl, error) {
+	opts = append([]docker.Opt{docker.FromEnv, docker.WithAPIVersionNegotiation()}, opts...)
+	cli, err := docker.NewClientWithOpts(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("could not initialize Docker client: %w", err)
+	}
+	return &dockerMetadataImpl{dockerClient: cli}, nil
+}
+
+func (d *dockerMetadataImpl) HostInfo(ctx context.Context) (hostInfo, error) {
+	info, err := d.dockerClient.Info(ctx)
+	if err != nil {
+		return hostInfo{}, fmt.Errorf("failed to fetch Docker information: %w", err)
+	}
+	return hostInfo{Hostname: info.Name, OSType: internal.GOOSToOSType(info.OSType)}, nil
+}
@@ -0,0 +1,67 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	docker "github.com/docker/docker/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMetadata(t *testing.T, handler http.HandlerFunc) *dockerMetadataImpl {
+	ts := httptest.NewServer(handler)
+	t.Cleanup(ts.Close)
+
+	provider, err := newDockerMetadata(
+		docker.WithHost("tcp://"+strings.TrimPrefix(ts.URL, "http://")),
+		docker.WithVersion("1.22"),
+	)
+	require.NoError(t, err)
+	return provider
+}
+
+func TestDockerMetadata(t *testing.T) {
+	calls := 0
+	provider := newTestMetadata(t, func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/info") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		calls++
+		json.NewEncoder(w).Encode(types.Info{Name: "docker-host", OSType: "linux"})
+	})
+
+	info, err := provider.HostInfo(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, hostInfo{Hostname: "docker-host", OSType: "LINUX"}, info)
+	assert.Equal(t, 1, calls)
+}
+
+func TestDockerMetadataError(t *testing.T) {
+	provider := newTestMetadata(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	_, err := provider.HostInfo(context.Background())
+	assert.Error(t, err)
+}
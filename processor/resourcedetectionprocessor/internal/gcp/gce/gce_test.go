@@ -66,7 +66,7 @@ func (m *mockMetadata) Get(suffix string) (string, error) {
 }
 
 func TestNewDetector(t *testing.T) {
-	d, err := NewDetector()
+	d, err := NewDetector(nil)
 	assert.NotNil(t, d)
 	assert.NoError(t, err)
 }
@@ -19,6 +19,7 @@ package internal
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -32,7 +33,16 @@ type Detector interface {
 	Detect(ctx context.Context) (pdata.Resource, error)
 }
 
-type DetectorFactory func() (Detector, error)
+// DetectorConfig holds the settings of a single detector. Detectors
+// without settings receive a nil config.
+type DetectorConfig interface{}
+
+// ResourceDetectorConfig resolves the settings of each detector type.
+type ResourceDetectorConfig interface {
+	GetConfigFromType(DetectorType) DetectorConfig
+}
+
+type DetectorFactory func(DetectorConfig) (Detector, error)
 
 type ResourceProviderFactory struct {
 	// detectors holds all possible detector types.
@@ -43,8 +53,12 @@ func NewProviderFactory(detectors map[DetectorType]DetectorFactory) *ResourcePro
 	return &ResourceProviderFactory{detectors: detectors}
 }
 
-func (f *ResourceProviderFactory) CreateResourceProvider(logger *zap.Logger, timeout time.Duration, detectorTypes ...DetectorType) (*ResourceProvider, error) {
-	detectors, err := f.getDetectors(detectorTypes)
+func (f *ResourceProviderFactory) CreateResourceProvider(
+	logger *zap.Logger,
+	timeout time.Duration,
+	detectorConfigs ResourceDetectorConfig,
+	detectorTypes ...DetectorType) (*ResourceProvider, error) {
+	detectors, err := f.getDetectors(detectorConfigs, detectorTypes)
 	if err != nil {
 		return nil, err
 	}
@@ -53,7 +67,7 @@ func (f *ResourceProviderFactory) CreateResourceProvider(logger *zap.Logger, tim
 	return provider, nil
 }
 
-func (f *ResourceProviderFactory) getDetectors(detectorTypes []DetectorType) ([]Detector, error) {
+func (f *ResourceProviderFactory) getDetectors(detectorConfigs ResourceDetectorConfig, detectorTypes []DetectorType) ([]Detector, error) {
 	detectors := make([]Detector, 0, len(detectorTypes))
 	for _, detectorType := range detectorTypes {
 		detectorFactory, ok := f.detectors[detectorType]
@@ -61,7 +75,12 @@ func (f *ResourceProviderFactory) getDetectors(detectorTypes []DetectorType) ([]
 			return nil, fmt.Errorf("invalid detector key: %v", detectorType)
 		}
 
-		detector, err := detectorFactory()
+		var detectorConfig DetectorConfig
+		if detectorConfigs != nil {
+			detectorConfig = detectorConfigs.GetConfigFromType(detectorType)
+		}
+
+		detector, err := detectorFactory(detectorConfig)
 		if err != nil {
 			return nil, fmt.Errorf("failed creating detector type %q: %w", detectorType, err)
 		}
@@ -168,3 +187,12 @@ func MergeResource(to, from pdata.Resource, overrideTo bool) {
 func IsEmptyResource(res pdata.Resource) bool {
 	return res.IsNil() || res.Attributes().Len() == 0
 }
+
+// GOOSToOSType maps a runtime.GOOS-like value to os.type style.
+func GOOSToOSType(goos string) string {
+	switch goos {
+	case "dragonfly":
+		return "DRAGONFLYBSD"
+	}
+	return strings.ToUpper(goos)
+}
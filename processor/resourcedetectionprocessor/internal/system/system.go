@@ -0,0 +1,74 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package system provides a detector that loads resource information from
+// the host operating system
+package system
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/resourcedetectionprocessor/internal"
+)
+
+const (
+	TypeStr = "system"
+)
+
+var _ internal.Detector = (*Detector)(nil)
+
+type Detector struct {
+	provider systemMetadata
+}
+
+func NewDetector(internal.DetectorConfig) (internal.Detector, error) {
+	return &Detector{provider: &systemMetadataImpl{}}, nil
+}
+
+func (d *Detector) Detect(ctx context.Context) (pdata.Resource, error) {
+	res := pdata.NewResource()
+	res.InitEmpty()
+
+	osType, err := d.provider.OSType()
+	if err != nil {
+		return res, fmt.Errorf("failed getting OS type: %w", err)
+	}
+
+	hostname, err := d.provider.FQDN()
+	if err != nil {
+		// Fall back to the hostname reported by the OS.
+		hostname, err = d.provider.Hostname()
+		if err != nil {
+			return res, fmt.Errorf("failed getting OS hostname: %w", err)
+		}
+	}
+
+	hostID, err := d.provider.HostID(ctx)
+	if err != nil {
+		return res, fmt.Errorf("failed getting host ID: %w", err)
+	}
+
+	attr := res.Attributes()
+	attr.InsertString(conventions.AttributeHostName, hostname)
+	attr.InsertString(conventions.AttributeOSType, osType)
+	if hostID != "" {
+		attr.InsertString(conventions.AttributeHostID, hostID)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,137 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package system
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/translator/conventions"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/resourcedetectionprocessor/internal"
+)
+
+type mockMetadata struct {
+	mock.Mock
+}
+
+func (m *mockMetadata) Hostname() (string, error) {
+	args := m.MethodCalled("Hostname")
+	return args.String(0), args.Error(1)
+}
+
+func (m *mockMetadata) FQDN() (string, error) {
+	args := m.MethodCalled("FQDN")
+	return args.String(0), args.Error(1)
+}
+
+func (m *mockMetadata) OSType() (string, error) {
+	args := m.MethodCalled("OSType")
+	return args.String(0), args.Error(1)
+}
+
+func (m *mockMetadata) HostID(context.Context) (string, error) {
+	args := m.MethodCalled("HostID")
+	return args.String(0), args.Error(1)
+}
+
+func TestNewDetector(t *testing.T) {
+	d, err := NewDetector(nil)
+	assert.NotNil(t, d)
+	assert.NoError(t, err)
+}
+
+func TestDetectFQDNAvailable(t *testing.T) {
+	md := &mockMetadata{}
+	md.On("FQDN").Return("fqdn", nil)
+	md.On("OSType").Return("DARWIN", nil)
+	md.On("HostID").Return("2", nil)
+
+	detector := &Detector{provider: md}
+	res, err := detector.Detect(context.Background())
+	require.NoError(t, err)
+	md.AssertExpectations(t)
+	md.AssertNotCalled(t, "Hostname")
+
+	expected := internal.NewResource(map[string]interface{}{
+		conventions.AttributeHostName: "fqdn",
+		conventions.AttributeOSType:   "DARWIN",
+		conventions.AttributeHostID:   "2",
+	})
+
+	res.Attributes().Sort()
+	expected.Attributes().Sort()
+	assert.Equal(t, expected, res)
+}
+
+func TestFallbackHostname(t *testing.T) {
+	mdHostname := &mockMetadata{}
+	mdHostname.On("Hostname").Return("hostname", nil)
+	mdHostname.On("FQDN").Return("", errors.New("err"))
+	mdHostname.On("OSType").Return("DARWIN", nil)
+	mdHostname.On("HostID").Return("", nil)
+
+	detector := &Detector{provider: mdHostname}
+	res, err := detector.Detect(context.Background())
+	require.NoError(t, err)
+	mdHostname.AssertExpectations(t)
+
+	expected := internal.NewResource(map[string]interface{}{
+		conventions.AttributeHostName: "hostname",
+		conventions.AttributeOSType:   "DARWIN",
+	})
+
+	res.Attributes().Sort()
+	expected.Attributes().Sort()
+	assert.Equal(t, expected, res)
+}
+
+func TestDetectError(t *testing.T) {
+	// FQDN and hostname fail
+	mdFQDN := &mockMetadata{}
+	mdFQDN.On("OSType").Return("WINDOWS", nil)
+	mdFQDN.On("FQDN").Return("", errors.New("err"))
+	mdFQDN.On("Hostname").Return("", errors.New("err"))
+
+	detector := &Detector{provider: mdFQDN}
+	res, err := detector.Detect(context.Background())
+	assert.Error(t, err)
+	assert.True(t, internal.IsEmptyResource(res))
+
+	// OS type fails
+	mdOSType := &mockMetadata{}
+	mdOSType.On("FQDN").Return("fqdn", nil)
+	mdOSType.On("OSType").Return("", errors.New("err"))
+
+	detector = &Detector{provider: mdOSType}
+	res, err = detector.Detect(context.Background())
+	assert.Error(t, err)
+	assert.True(t, internal.IsEmptyResource(res))
+
+	// host ID fails
+	mdHostID := &mockMetadata{}
+	mdHostID.On("FQDN").Return("fqdn", nil)
+	mdHostID.On("OSType").Return("LINUX", nil)
+	mdHostID.On("HostID").Return("", errors.New("err"))
+
+	detector = &Detector{provider: mdHostID}
+	res, err = detector.Detect(context.Background())
+	assert.Error(t, err)
+	assert.True(t, internal.IsEmptyResource(res))
+}
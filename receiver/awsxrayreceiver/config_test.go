@@ -26,6 +26,7 @@ import (
 	"go.opentelemetry.io/collector/config/configtest"
 	"go.opentelemetry.io/collector/config/configtls"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsutil"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/proxy"
 )
@@ -71,9 +72,9 @@ func TestLoadConfig(t *testing.T) {
 					Insecure:   false,
 					ServerName: "",
 				},
-				Region:      "",
-				RoleARN:     "",
-				AWSEndpoint: "",
+				Region:              "",
+				CredentialsSettings: awsutil.CredentialsSettings{},
+				AWSEndpoint:         "",
 			},
 		},
 		r1)
@@ -99,8 +100,13 @@ func TestLoadConfig(t *testing.T) {
 					Insecure:   true,
 					ServerName: "something",
 				},
-				Region:      "us-west-1",
-				RoleARN:     "arn:aws:iam::123456789012:role/awesome_role",
+				Region: "us-west-1",
+				CredentialsSettings: awsutil.CredentialsSettings{
+					Profile:     "collector",
+					RoleARN:     "arn:aws:iam::123456789012:role/awesome_role",
+					ExternalID:  "awesome_external_id",
+					STSEndpoint: "https://sts.us-west-1.amazonaws.com",
+				},
 				AWSEndpoint: "https://another.aws.endpoint.com",
 				LocalMode:   true,
 			},
@@ -5,6 +5,7 @@ go 1.14
 require (
 	github.com/aws/aws-sdk-go v1.34.30
 	github.com/google/uuid v1.1.2
+	github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsutil v0.0.0-00010101000000-000000000000
 	github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray v0.0.0-00010101000000-000000000000
 	github.com/stretchr/testify v1.6.1
 	go.opentelemetry.io/collector v0.11.1-0.20200924160956-8690937037da
@@ -12,3 +13,5 @@ require (
 )
 
 replace github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray => ./../../internal/awsxray
+
+replace github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsutil => ./../../internal/awsutil
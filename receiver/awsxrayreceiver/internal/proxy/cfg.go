@@ -17,6 +17,8 @@ package proxy
 import (
 	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/config/configtls"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsutil"
 )
 
 // Config is the configuration for the local TCP proxy server.
@@ -37,9 +39,10 @@ type Config struct {
 	// Region is the AWS region the local TCP server forwards requests to.
 	Region string `mapstructure:"region"`
 
-	// RoleARN is the IAM role used by the local TCP server when
-	// communicating with the AWS X-Ray service.
-	RoleARN string `mapstructure:"role_arn"`
+	// CredentialsSettings defines the credentials used by the local TCP
+	// server when communicating with the AWS X-Ray service, e.g. a shared
+	// credentials profile or the IAM role to assume.
+	awsutil.CredentialsSettings `mapstructure:",squash"`
 
 	// AWSEndpoint is the X-Ray service endpoint which the local
 	// TCP server forwards requests to.
@@ -61,8 +64,8 @@ func DefaultConfig() *Config {
 			Insecure:   false,
 			ServerName: "",
 		},
-		Region:      "",
-		RoleARN:     "",
-		AWSEndpoint: "",
+		Region:              "",
+		CredentialsSettings: awsutil.CredentialsSettings{},
+		AWSEndpoint:         "",
 	}
 }
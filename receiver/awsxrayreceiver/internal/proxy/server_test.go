@@ -25,10 +25,8 @@ import (
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/stretchr/testify/assert"
 	"go.opentelemetry.io/collector/testutil"
-	"go.uber.org/zap"
 )
 
 const (
@@ -201,15 +199,8 @@ func TestCantGetAWSConfigSession(t *testing.T) {
 	tcpAddr := testutil.GetAvailableLocalAddress(t)
 	cfg.TCPAddr.Endpoint = tcpAddr
 
-	real := newAWSSession
-	defer func() {
-		newAWSSession = real
-	}()
-
 	expectedErr := errors.New("expected newAWSSessionError")
-	newAWSSession = func(roleArn string, region string, log *zap.Logger) (*session.Session, error) {
-		return nil, expectedErr
-	}
+	defer setupMock(&mock{sessionErr: expectedErr})()
 	_, err := NewServer(cfg, logger)
 	assert.EqualError(t, err, expectedErr.Error())
 }
@@ -28,6 +28,8 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/cache"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/utils"
 )
 
 // TODO: Consider moving some of these constants to
@@ -46,18 +48,25 @@ const (
 	k8sKeyReplicationControllerUID = "k8s.replicationcontroller.uid"
 	k8sKeyHPAUID                   = "k8s.hpa.uid"
 	k8sKeyResourceQuotaUID         = "k8s.resourcequota.uid"
+	k8sKeyPersistentVolumeClaimUID = "k8s.persistentvolumeclaim.uid"
+	k8sKeyPersistentVolumeUID      = "k8s.persistentvolume.uid"
+	k8sKeyServiceUID               = "k8s.service.uid"
 
 	// Resource labels keys for Name.
 	k8sKeyNodeName                  = "k8s.node.name"
 	k8sKeyReplicationControllerName = "k8s.replicationcontroller.name"
 	k8sKeyHPAName                   = "k8s.hpa.name"
 	k8sKeyResourceQuotaName         = "k8s.resourcequota.name"
+	k8sKeyPersistentVolumeClaimName = "k8s.persistentvolumeclaim.name"
+	k8sKeyPersistentVolumeName      = "k8s.persistentvolume.name"
+	k8sKeyServiceName               = "k8s.service.name"
 
 	// Kubernetes resource kinds
 	k8sKindCronJob               = "CronJob"
 	k8sKindDaemonSet             = "DaemonSet"
 	k8sKindDeployment            = "Deployment"
 	k8sKindJob                   = "Job"
+	k8sKindPersistentVolumeClaim = "PersistentVolumeClaim"
 	k8sKindReplicationController = "ReplicationController"
 	k8sKindReplicaSet            = "ReplicaSet"
 	k8sKindService               = "Service"
@@ -131,6 +140,15 @@ func (dc *DataCollector) SyncMetrics(obj interface{}) {
 		rm = getMetricsForReplicationController(o)
 	case *corev1.ResourceQuota:
 		rm = getMetricsForResourceQuota(o)
+	case *corev1.PersistentVolumeClaim:
+		rm = getMetricsForPersistentVolumeClaim(o)
+	case *corev1.PersistentVolume:
+		rm = getMetricsForPersistentVolume(o)
+	case *corev1.Service:
+		rm = getMetricsForService(o)
+		dc.syncEndpointsOfService(o)
+	case *corev1.Endpoints:
+		rm = getMetricsForEndpoints(o, dc.metadataStore.services)
 	case *appsv1.Deployment:
 		rm = getMetricsForDeployment(o)
 	case *appsv1.ReplicaSet:
@@ -156,6 +174,23 @@ func (dc *DataCollector) SyncMetrics(obj interface{}) {
 	dc.UpdateMetricsStore(obj, rm)
 }
 
+// syncEndpointsOfService updates the metrics of the Endpoints of a service.
+// Endpoints metrics are only reported once their service is known, and the
+// Endpoints may have been added before the service.
+func (dc *DataCollector) syncEndpointsOfService(svc *corev1.Service) {
+	if dc.metadataStore.endpoints == nil {
+		return
+	}
+	obj, exists, err := dc.metadataStore.endpoints.GetByKey(utils.GetIDForCache(svc.Namespace, svc.Name))
+	if err != nil || !exists {
+		return
+	}
+	ep := obj.(*corev1.Endpoints)
+	if rm := getMetricsForEndpoints(ep, dc.metadataStore.services); len(rm) > 0 {
+		dc.UpdateMetricsStore(ep, rm)
+	}
+}
+
 // SyncMetadata updates the metric store with latest metrics from the kubernetes object
 func (dc *DataCollector) SyncMetadata(obj interface{}) map[ResourceID]*KubernetesMetadata {
 	km := map[ResourceID]*KubernetesMetadata{}
@@ -166,6 +201,12 @@ func (dc *DataCollector) SyncMetadata(obj interface{}) map[ResourceID]*Kubernete
 		km = getMetadataForNode(o)
 	case *corev1.ReplicationController:
 		km = getMetadataForReplicationController(o)
+	case *corev1.PersistentVolumeClaim:
+		km = getMetadataForPersistentVolumeClaim(o)
+	case *corev1.PersistentVolume:
+		km = getMetadataForPersistentVolume(o)
+	case *corev1.Service:
+		km = getMetadataForService(o)
 	case *appsv1.Deployment:
 		km = getMetadataForDeployment(o)
 	case *appsv1.ReplicaSet:
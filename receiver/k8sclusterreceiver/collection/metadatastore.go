@@ -27,15 +27,18 @@ import (
 // to correlate other Kubernetes objects with a Pod.
 type metadataStore struct {
 	services    cache.Store
+	endpoints   cache.Store
 	jobs        cache.Store
 	replicaSets cache.Store
 }
 
-// setupStore tracks metadata of services, jobs and replicasets.
+// setupStore tracks metadata of services, endpoints, jobs and replicasets.
 func (ms *metadataStore) setupStore(o runtime.Object, store cache.Store) {
 	switch o.(type) {
 	case *corev1.Service:
 		ms.services = store
+	case *corev1.Endpoints:
+		ms.endpoints = store
 	case *batchv1.Job:
 		ms.jobs = store
 	case *appsv1.ReplicaSet:
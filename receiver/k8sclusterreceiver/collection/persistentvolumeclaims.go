@@ -0,0 +1,115 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collection
+
+import (
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"go.opentelemetry.io/collector/translator/conventions"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/utils"
+)
+
+const (
+	// Keys for persistent volume claim metadata.
+	pvcStorageClass = "persistentvolumeclaim.storage_class"
+	pvcVolumeName   = "persistentvolumeclaim.volume_name"
+)
+
+func getMetricsForPersistentVolumeClaim(pvc *corev1.PersistentVolumeClaim) []*resourceMetrics {
+	metrics := []*metricspb.Metric{
+		{
+			MetricDescriptor: &metricspb.MetricDescriptor{
+				Name: "k8s/persistentvolumeclaim/phase",
+				Description: "Current phase of the persistent volume claim (1 - Pending, 2 - Bound," +
+					" 3 - Lost, 4 - Unknown)",
+				Type: metricspb.MetricDescriptor_GAUGE_INT64,
+			},
+			Timeseries: []*metricspb.TimeSeries{
+				utils.GetInt64TimeSeries(int64(pvcPhaseToInt(pvc.Status.Phase))),
+			},
+		},
+	}
+
+	if q, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+		metrics = append(metrics, &metricspb.Metric{
+			MetricDescriptor: &metricspb.MetricDescriptor{
+				Name:        "k8s/persistentvolumeclaim/storage_requested",
+				Description: "Amount of storage requested by the persistent volume claim",
+				Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+				Unit:        "By",
+			},
+			Timeseries: []*metricspb.TimeSeries{utils.GetInt64TimeSeries(q.Value())},
+		})
+	}
+
+	if q, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok {
+		metrics = append(metrics, &metricspb.Metric{
+			MetricDescriptor: &metricspb.MetricDescriptor{
+				Name:        "k8s/persistentvolumeclaim/storage_allocated",
+				Description: "Amount of storage allocated to the persistent volume claim by the underlying volume",
+				Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+				Unit:        "By",
+			},
+			Timeseries: []*metricspb.TimeSeries{utils.GetInt64TimeSeries(q.Value())},
+		})
+	}
+
+	return []*resourceMetrics{
+		{
+			resource: getResourceForPersistentVolumeClaim(pvc),
+			metrics:  metrics,
+		},
+	}
+}
+
+func getResourceForPersistentVolumeClaim(pvc *corev1.PersistentVolumeClaim) *resourcepb.Resource {
+	return &resourcepb.Resource{
+		Type: k8sType,
+		Labels: map[string]string{
+			k8sKeyPersistentVolumeClaimUID:    string(pvc.UID),
+			k8sKeyPersistentVolumeClaimName:   pvc.Name,
+			conventions.AttributeK8sNamespace: pvc.Namespace,
+			conventions.AttributeK8sCluster:   pvc.ClusterName,
+		},
+	}
+}
+
+func pvcPhaseToInt(phase corev1.PersistentVolumeClaimPhase) int32 {
+	switch phase {
+	case corev1.ClaimPending:
+		return 1
+	case corev1.ClaimBound:
+		return 2
+	case corev1.ClaimLost:
+		return 3
+	default:
+		return 4
+	}
+}
+
+func getMetadataForPersistentVolumeClaim(pvc *corev1.PersistentVolumeClaim) map[ResourceID]*KubernetesMetadata {
+	km := getGenericMetadata(&pvc.ObjectMeta, k8sKindPersistentVolumeClaim)
+
+	if pvc.Spec.StorageClassName != nil {
+		km.metadata[pvcStorageClass] = *pvc.Spec.StorageClassName
+	}
+	if pvc.Spec.VolumeName != "" {
+		km.metadata[pvcVolumeName] = pvc.Spec.VolumeName
+	}
+
+	return map[ResourceID]*KubernetesMetadata{ResourceID(pvc.UID): km}
+}
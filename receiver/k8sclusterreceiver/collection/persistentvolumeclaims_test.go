@@ -0,0 +1,112 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collection
+
+import (
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/testutils"
+)
+
+func TestPersistentVolumeClaimMetrics(t *testing.T) {
+	pvc := newPersistentVolumeClaim("1")
+
+	actualResourceMetrics := getMetricsForPersistentVolumeClaim(pvc)
+
+	require.Equal(t, 1, len(actualResourceMetrics))
+
+	require.Equal(t, 3, len(actualResourceMetrics[0].metrics))
+	testutils.AssertResource(t, actualResourceMetrics[0].resource, k8sType,
+		map[string]string{
+			"k8s.persistentvolumeclaim.uid":  "test-pvc-1-uid",
+			"k8s.persistentvolumeclaim.name": "test-pvc-1",
+			"k8s.namespace.name":             "test-namespace",
+			"k8s.cluster.name":               "test-cluster",
+		},
+	)
+
+	testutils.AssertMetrics(t, actualResourceMetrics[0].metrics[0], "k8s/persistentvolumeclaim/phase",
+		metricspb.MetricDescriptor_GAUGE_INT64, 2)
+
+	testutils.AssertMetrics(t, actualResourceMetrics[0].metrics[1], "k8s/persistentvolumeclaim/storage_requested",
+		metricspb.MetricDescriptor_GAUGE_INT64, 10737418240)
+
+	testutils.AssertMetrics(t, actualResourceMetrics[0].metrics[2], "k8s/persistentvolumeclaim/storage_allocated",
+		metricspb.MetricDescriptor_GAUGE_INT64, 21474836480)
+}
+
+func TestPendingPersistentVolumeClaimMetrics(t *testing.T) {
+	pvc := newPersistentVolumeClaim("1")
+	pvc.Status = corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending}
+
+	actualResourceMetrics := getMetricsForPersistentVolumeClaim(pvc)
+
+	require.Equal(t, 1, len(actualResourceMetrics))
+	require.Equal(t, 2, len(actualResourceMetrics[0].metrics))
+	testutils.AssertMetrics(t, actualResourceMetrics[0].metrics[0], "k8s/persistentvolumeclaim/phase",
+		metricspb.MetricDescriptor_GAUGE_INT64, 1)
+}
+
+func TestPersistentVolumeClaimMetadata(t *testing.T) {
+	pvc := newPersistentVolumeClaim("1")
+
+	actualMetadata := getMetadataForPersistentVolumeClaim(pvc)
+
+	require.Equal(t, 1, len(actualMetadata))
+	km := actualMetadata["test-pvc-1-uid"]
+	require.Equal(t, "k8s.persistentvolumeclaim.uid", km.resourceIDKey)
+	require.Equal(t, "bar", km.metadata["foo"])
+	require.Equal(t, "PersistentVolumeClaim", km.metadata["k8s.workload.kind"])
+	require.Equal(t, "test-pvc-1", km.metadata["k8s.workload.name"])
+	require.Equal(t, "standard", km.metadata["persistentvolumeclaim.storage_class"])
+	require.Equal(t, "test-pv-1", km.metadata["persistentvolumeclaim.volume_name"])
+}
+
+func newPersistentVolumeClaim(id string) *corev1.PersistentVolumeClaim {
+	storageClass := "standard"
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: v1.ObjectMeta{
+			Name:        "test-pvc-" + id,
+			UID:         types.UID("test-pvc-" + id + "-uid"),
+			ClusterName: "test-cluster",
+			Namespace:   "test-namespace",
+			Labels: map[string]string{
+				"foo": "bar",
+			},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			StorageClassName: &storageClass,
+			VolumeName:       "test-pv-" + id,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse("10Gi"),
+				},
+			},
+		},
+		Status: corev1.PersistentVolumeClaimStatus{
+			Phase: corev1.ClaimBound,
+			Capacity: corev1.ResourceList{
+				corev1.ResourceStorage: resource.MustParse("20Gi"),
+			},
+		},
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collection
+
+import (
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"go.opentelemetry.io/collector/translator/conventions"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/utils"
+)
+
+const (
+	// Keys for persistent volume metadata.
+	pvCreationTime   = "persistentvolume.creation_timestamp"
+	pvStorageClass   = "persistentvolume.storage_class"
+	pvReclaimPolicy  = "persistentvolume.reclaim_policy"
+	pvClaimName      = "persistentvolume.claim_name"
+	pvClaimNamespace = "persistentvolume.claim_namespace"
+)
+
+func getMetricsForPersistentVolume(pv *corev1.PersistentVolume) []*resourceMetrics {
+	metrics := []*metricspb.Metric{
+		{
+			MetricDescriptor: &metricspb.MetricDescriptor{
+				Name: "k8s/persistentvolume/phase",
+				Description: "Current phase of the persistent volume (1 - Pending, 2 - Available," +
+					" 3 - Bound, 4 - Released, 5 - Failed, 6 - Unknown)",
+				Type: metricspb.MetricDescriptor_GAUGE_INT64,
+			},
+			Timeseries: []*metricspb.TimeSeries{
+				utils.GetInt64TimeSeries(int64(pvPhaseToInt(pv.Status.Phase))),
+			},
+		},
+	}
+
+	if q, ok := pv.Spec.Capacity[corev1.ResourceStorage]; ok {
+		metrics = append(metrics, &metricspb.Metric{
+			MetricDescriptor: &metricspb.MetricDescriptor{
+				Name:        "k8s/persistentvolume/storage_capacity",
+				Description: "Storage capacity of the persistent volume",
+				Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+				Unit:        "By",
+			},
+			Timeseries: []*metricspb.TimeSeries{utils.GetInt64TimeSeries(q.Value())},
+		})
+	}
+
+	return []*resourceMetrics{
+		{
+			resource: getResourceForPersistentVolume(pv),
+			metrics:  metrics,
+		},
+	}
+}
+
+func getResourceForPersistentVolume(pv *corev1.PersistentVolume) *resourcepb.Resource {
+	return &resourcepb.Resource{
+		Type: k8sType,
+		Labels: map[string]string{
+			k8sKeyPersistentVolumeUID:       string(pv.UID),
+			k8sKeyPersistentVolumeName:      pv.Name,
+			conventions.AttributeK8sCluster: pv.ClusterName,
+		},
+	}
+}
+
+func pvPhaseToInt(phase corev1.PersistentVolumePhase) int32 {
+	switch phase {
+	case corev1.VolumePending:
+		return 1
+	case corev1.VolumeAvailable:
+		return 2
+	case corev1.VolumeBound:
+		return 3
+	case corev1.VolumeReleased:
+		return 4
+	case corev1.VolumeFailed:
+		return 5
+	default:
+		return 6
+	}
+}
+
+// getMetadataForPersistentVolume returns metadata for persistent volumes. These
+// are cluster scoped, so the claim they're bound to is reported instead of
+// workload information.
+func getMetadataForPersistentVolume(pv *corev1.PersistentVolume) map[ResourceID]*KubernetesMetadata {
+	metadata := utils.MergeStringMaps(map[string]string{}, pv.Labels)
+
+	metadata[k8sKeyPersistentVolumeName] = pv.Name
+	metadata[pvCreationTime] = pv.GetCreationTimestamp().Format(time.RFC3339)
+	metadata[pvReclaimPolicy] = string(pv.Spec.PersistentVolumeReclaimPolicy)
+	if pv.Spec.StorageClassName != "" {
+		metadata[pvStorageClass] = pv.Spec.StorageClassName
+	}
+	if pv.Spec.ClaimRef != nil {
+		metadata[pvClaimName] = pv.Spec.ClaimRef.Name
+		metadata[pvClaimNamespace] = pv.Spec.ClaimRef.Namespace
+	}
+
+	pvID := ResourceID(pv.UID)
+	return map[ResourceID]*KubernetesMetadata{
+		pvID: {
+			resourceIDKey: k8sKeyPersistentVolumeUID,
+			resourceID:    pvID,
+			metadata:      metadata,
+		},
+	}
+}
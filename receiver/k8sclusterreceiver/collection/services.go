@@ -0,0 +1,142 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collection
+
+import (
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"go.opentelemetry.io/collector/translator/conventions"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/utils"
+)
+
+const (
+	// Keys for service metadata.
+	serviceType      = "service.type"
+	serviceClusterIP = "service.cluster_ip"
+)
+
+var serviceTypeMetric = &metricspb.MetricDescriptor{
+	Name:        "k8s/service/type",
+	Description: "Type of the service, always reported as 1 with the type as a label",
+	Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+	LabelKeys: []*metricspb.LabelKey{{
+		Key: "service_type",
+	}},
+}
+
+func getMetricsForService(svc *corev1.Service) []*resourceMetrics {
+	metrics := []*metricspb.Metric{
+		{
+			MetricDescriptor: serviceTypeMetric,
+			Timeseries: []*metricspb.TimeSeries{
+				utils.GetInt64TimeSeriesWithLabels(1, []*metricspb.LabelValue{{
+					Value: string(serviceTypeOrDefault(svc.Spec.Type)),
+				}}),
+			},
+		},
+	}
+
+	return []*resourceMetrics{
+		{
+			resource: getResourceForService(svc.Name, svc.Namespace, svc.ClusterName, string(svc.UID)),
+			metrics:  metrics,
+		},
+	}
+}
+
+// getMetricsForEndpoints reports ready and not ready address counts of a
+// service. Endpoints share their name with the service they belong to, so
+// the metrics are attached to the service resource. No metrics are reported
+// until the service is in the services store, as its UID is part of the
+// resource. The Endpoints are synced again when their service is added.
+func getMetricsForEndpoints(ep *corev1.Endpoints, services cache.Store) []*resourceMetrics {
+	if services == nil {
+		return nil
+	}
+	obj, exists, err := services.GetByKey(utils.GetIDForCache(ep.Namespace, ep.Name))
+	if err != nil || !exists {
+		return nil
+	}
+	svc := obj.(*corev1.Service)
+
+	var ready, notReady int
+	for _, subset := range ep.Subsets {
+		ready += len(subset.Addresses)
+		notReady += len(subset.NotReadyAddresses)
+	}
+
+	metrics := []*metricspb.Metric{
+		{
+			MetricDescriptor: &metricspb.MetricDescriptor{
+				Name:        "k8s/service/endpoints_ready",
+				Description: "Number of endpoint addresses of the service that are ready to serve traffic",
+				Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+				Unit:        "1",
+			},
+			Timeseries: []*metricspb.TimeSeries{utils.GetInt64TimeSeries(int64(ready))},
+		},
+		{
+			MetricDescriptor: &metricspb.MetricDescriptor{
+				Name:        "k8s/service/endpoints_not_ready",
+				Description: "Number of endpoint addresses of the service that are not ready to serve traffic",
+				Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+				Unit:        "1",
+			},
+			Timeseries: []*metricspb.TimeSeries{utils.GetInt64TimeSeries(int64(notReady))},
+		},
+	}
+
+	return []*resourceMetrics{
+		{
+			resource: getResourceForService(svc.Name, svc.Namespace, svc.ClusterName, string(svc.UID)),
+			metrics:  metrics,
+		},
+	}
+}
+
+func getResourceForService(name, namespace, clusterName, uid string) *resourcepb.Resource {
+	return &resourcepb.Resource{
+		Type: k8sType,
+		Labels: map[string]string{
+			k8sKeyServiceUID:                  uid,
+			k8sKeyServiceName:                 name,
+			conventions.AttributeK8sNamespace: namespace,
+			conventions.AttributeK8sCluster:   clusterName,
+		},
+	}
+}
+
+// serviceTypeOrDefault returns the type of a service, falling back to
+// ClusterIP which is what the API server defaults an empty type to.
+func serviceTypeOrDefault(t corev1.ServiceType) corev1.ServiceType {
+	if t == "" {
+		return corev1.ServiceTypeClusterIP
+	}
+	return t
+}
+
+func getMetadataForService(svc *corev1.Service) map[ResourceID]*KubernetesMetadata {
+	km := getGenericMetadata(&svc.ObjectMeta, k8sKindService)
+
+	km.metadata[serviceType] = string(serviceTypeOrDefault(svc.Spec.Type))
+	if svc.Spec.ClusterIP != "" {
+		km.metadata[serviceClusterIP] = svc.Spec.ClusterIP
+	}
+
+	return map[ResourceID]*KubernetesMetadata{ResourceID(svc.UID): km}
+}
@@ -0,0 +1,168 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collection
+
+import (
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/testutils"
+)
+
+func TestServiceMetrics(t *testing.T) {
+	svc := newService("1")
+
+	actualResourceMetrics := getMetricsForService(svc)
+
+	require.Equal(t, 1, len(actualResourceMetrics))
+
+	require.Equal(t, 1, len(actualResourceMetrics[0].metrics))
+	testutils.AssertResource(t, actualResourceMetrics[0].resource, k8sType,
+		map[string]string{
+			"k8s.service.uid":    "test-service-1-uid",
+			"k8s.service.name":   "test-service-1",
+			"k8s.namespace.name": "test-namespace",
+			"k8s.cluster.name":   "test-cluster",
+		},
+	)
+
+	testutils.AssertMetricsWithLabels(t, actualResourceMetrics[0].metrics[0], "k8s/service/type",
+		metricspb.MetricDescriptor_GAUGE_INT64, map[string]string{"service_type": "LoadBalancer"}, 1)
+}
+
+func TestEndpointsMetrics(t *testing.T) {
+	ep := &corev1.Endpoints{
+		ObjectMeta: v1.ObjectMeta{
+			Name:        "test-service-1",
+			UID:         types.UID("test-endpoints-1-uid"),
+			ClusterName: "test-cluster",
+			Namespace:   "test-namespace",
+		},
+		Subsets: []corev1.EndpointSubset{
+			{
+				Addresses:         []corev1.EndpointAddress{{IP: "10.0.0.1"}, {IP: "10.0.0.2"}},
+				NotReadyAddresses: []corev1.EndpointAddress{{IP: "10.0.0.3"}},
+			},
+			{
+				Addresses: []corev1.EndpointAddress{{IP: "10.0.0.4"}},
+			},
+		},
+	}
+
+	services := &testutils.MockStore{Cache: map[string]interface{}{
+		"test-namespace/test-service-1": newService("1"),
+	}}
+	actualResourceMetrics := getMetricsForEndpoints(ep, services)
+
+	require.Equal(t, 1, len(actualResourceMetrics))
+	require.Equal(t, 2, len(actualResourceMetrics[0].metrics))
+	testutils.AssertResource(t, actualResourceMetrics[0].resource, k8sType,
+		map[string]string{
+			"k8s.service.uid":    "test-service-1-uid",
+			"k8s.service.name":   "test-service-1",
+			"k8s.namespace.name": "test-namespace",
+			"k8s.cluster.name":   "test-cluster",
+		},
+	)
+
+	testutils.AssertMetrics(t, actualResourceMetrics[0].metrics[0], "k8s/service/endpoints_ready",
+		metricspb.MetricDescriptor_GAUGE_INT64, 3)
+	testutils.AssertMetrics(t, actualResourceMetrics[0].metrics[1], "k8s/service/endpoints_not_ready",
+		metricspb.MetricDescriptor_GAUGE_INT64, 1)
+}
+
+func TestEndpointsMetricsWithoutService(t *testing.T) {
+	ep := &corev1.Endpoints{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "test-service-1",
+			Namespace: "test-namespace",
+		},
+		Subsets: []corev1.EndpointSubset{{Addresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}}}},
+	}
+
+	// The metrics are skipped until the service, and so its UID, is known.
+	require.Empty(t, getMetricsForEndpoints(ep, &testutils.MockStore{Cache: map[string]interface{}{}}))
+	require.Empty(t, getMetricsForEndpoints(ep, nil))
+}
+
+func TestEndpointsMetricsSyncedOnServiceAdd(t *testing.T) {
+	ep := &corev1.Endpoints{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "test-service-1",
+			UID:       types.UID("test-endpoints-1-uid"),
+			Namespace: "test-namespace",
+		},
+		Subsets: []corev1.EndpointSubset{{NotReadyAddresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}}}},
+	}
+	svc := newService("1")
+
+	services := &testutils.MockStore{Cache: map[string]interface{}{}}
+	endpoints := &testutils.MockStore{Cache: map[string]interface{}{"test-namespace/test-service-1": ep}}
+	dc := NewDataCollector(zap.NewNop(), []string{})
+	dc.SetupMetadataStore(&corev1.Service{}, services)
+	dc.SetupMetadataStore(&corev1.Endpoints{}, endpoints)
+
+	// The Endpoints are added before their service.
+	dc.SyncMetrics(ep)
+	require.NotContains(t, dc.metricsStore.metricsCache, ep.UID)
+
+	services.Cache["test-namespace/test-service-1"] = svc
+	dc.SyncMetrics(svc)
+
+	mds := dc.metricsStore.metricsCache[ep.UID]
+	require.Equal(t, 1, len(mds))
+	require.Equal(t, "test-service-1-uid", mds[0].Resource.Labels["k8s.service.uid"])
+	testutils.AssertMetrics(t, mds[0].Metrics[0], "k8s/service/endpoints_ready",
+		metricspb.MetricDescriptor_GAUGE_INT64, 0)
+	testutils.AssertMetrics(t, mds[0].Metrics[1], "k8s/service/endpoints_not_ready",
+		metricspb.MetricDescriptor_GAUGE_INT64, 1)
+}
+
+func TestServiceMetadata(t *testing.T) {
+	svc := newService("1")
+
+	actualMetadata := getMetadataForService(svc)
+
+	require.Equal(t, 1, len(actualMetadata))
+	km := actualMetadata["test-service-1-uid"]
+	require.Equal(t, "k8s.service.uid", km.resourceIDKey)
+	require.Equal(t, "bar", km.metadata["foo"])
+	require.Equal(t, "LoadBalancer", km.metadata["service.type"])
+	require.Equal(t, "10.96.0.10", km.metadata["service.cluster_ip"])
+}
+
+func newService(id string) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: v1.ObjectMeta{
+			Name:        "test-service-" + id,
+			UID:         types.UID("test-service-" + id + "-uid"),
+			ClusterName: "test-cluster",
+			Namespace:   "test-namespace",
+			Labels: map[string]string{
+				"foo": "bar",
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Type:      corev1.ServiceTypeLoadBalancer,
+			ClusterIP: "10.96.0.10",
+		},
+	}
+}
@@ -36,6 +36,9 @@ type Config struct {
 	NodeConditionTypesToReport []string `mapstructure:"node_conditions_to_report"`
 	// List of exporters to which metadata from this receiver should be forwarded to.
 	MetadataExporters []string `mapstructure:"metadata_exporters"`
+	// Namespaces to watch Kubernetes events in when the receiver is part of a logs
+	// pipeline. Events of all namespaces are watched by default.
+	EventNamespaces []string `mapstructure:"event_namespaces"`
 
 	// For mocking.
 	makeClient func(apiConf k8sconfig.APIConfig) (k8s.Interface, error)
@@ -0,0 +1,255 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sclusterreceiver
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/translator/conventions"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// Attributes of the log records converted from Kubernetes events.
+	eventAttributeName      = "k8s.event.name"
+	eventAttributeUID       = "k8s.event.uid"
+	eventAttributeReason    = "k8s.event.reason"
+	eventAttributeType      = "k8s.event.type"
+	eventAttributeCount     = "k8s.event.count"
+	eventAttributeAction    = "k8s.event.action"
+	eventAttributeComponent = "k8s.event.component"
+	eventAttributeHost      = "k8s.event.host"
+	eventAttributeStartTime = "k8s.event.start_time"
+
+	// Resource attributes of the object involved in a Kubernetes event.
+	objectAttributeKind            = "k8s.object.kind"
+	objectAttributeName            = "k8s.object.name"
+	objectAttributeUID             = "k8s.object.uid"
+	objectAttributeAPIVersion      = "k8s.object.api_version"
+	objectAttributeFieldPath       = "k8s.object.fieldpath"
+	objectAttributeResourceVersion = "k8s.object.resource_version"
+	nodeAttributeName              = "k8s.node.name"
+	nodeAttributeUID               = "k8s.node.uid"
+)
+
+// eventWatcher watches Kubernetes events and sends them as logs.
+type eventWatcher struct {
+	logger    *zap.Logger
+	consumer  consumer.LogsConsumer
+	factories []informers.SharedInformerFactory
+	// startTime is when the watcher was started. Events that last occurred before
+	// are not sent, they were seen by the previous instance of the receiver.
+	startTime time.Time
+
+	mu  sync.Mutex
+	ctx context.Context
+	// sentVersions maps the UIDs of the events sent to the resource version sent.
+	// Informers replay the existing events when they relist, the events are only
+	// sent again when they changed, e.g. their count was incremented.
+	sentVersions map[types.UID]string
+}
+
+// newEventWatcher creates a watcher of the events of the given namespaces, or of
+// all namespaces if none is given.
+func newEventWatcher(logger *zap.Logger, client kubernetes.Interface, namespaces []string, consumer consumer.LogsConsumer) *eventWatcher {
+	ew := &eventWatcher{
+		logger:       logger,
+		consumer:     consumer,
+		sentVersions: map[types.UID]string{},
+	}
+
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+	for _, ns := range namespaces {
+		factory := informers.NewSharedInformerFactoryWithOptions(client, 0, informers.WithNamespace(ns))
+		factory.Core().V1().Events().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: ew.onAdd,
+			UpdateFunc: func(_, newObj interface{}) {
+				ew.onAdd(newObj)
+			},
+			DeleteFunc: ew.onDelete,
+		})
+		ew.factories = append(ew.factories, factory)
+	}
+
+	return ew
+}
+
+// start watching events until the context is done.
+func (ew *eventWatcher) start(ctx context.Context) {
+	ew.mu.Lock()
+	ew.ctx = ctx
+	ew.startTime = time.Now()
+	ew.mu.Unlock()
+
+	for _, factory := range ew.factories {
+		factory.Start(ctx.Done())
+	}
+}
+
+func (ew *eventWatcher) onAdd(obj interface{}) {
+	event, ok := obj.(*corev1.Event)
+	if !ok {
+		return
+	}
+
+	ew.mu.Lock()
+	if eventTime(event).Before(ew.startTime) || ew.sentVersions[event.UID] == event.ResourceVersion {
+		ew.mu.Unlock()
+		return
+	}
+	ew.sentVersions[event.UID] = event.ResourceVersion
+	ctx := ew.ctx
+	ew.mu.Unlock()
+
+	ld := eventToLogs(event)
+
+	c := obsreport.StartMetricsReceiveOp(ctx, typeStr, transport)
+	err := ew.consumer.ConsumeLogs(c, ld)
+	obsreport.EndMetricsReceiveOp(c, typeStr, 1, 1, err)
+	if err != nil {
+		ew.logger.Debug("failed to send event", zap.String("event", event.Name), zap.Error(err))
+	}
+}
+
+func (ew *eventWatcher) onDelete(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	event, ok := obj.(*corev1.Event)
+	if !ok {
+		return
+	}
+
+	ew.mu.Lock()
+	delete(ew.sentVersions, event.UID)
+	ew.mu.Unlock()
+}
+
+// eventTime returns the last time an event occurred.
+func eventTime(event *corev1.Event) time.Time {
+	switch {
+	case event.Series != nil && !event.Series.LastObservedTime.IsZero():
+		return event.Series.LastObservedTime.Time
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	default:
+		return event.FirstTimestamp.Time
+	}
+}
+
+// eventToLogs converts a Kubernetes event to a log record, its message being the
+// body. The object involved in the event is described by the resource.
+func eventToLogs(event *corev1.Event) pdata.Logs {
+	ld := pdata.NewLogs()
+	rls := ld.ResourceLogs()
+	rls.Resize(1)
+	rl := rls.At(0)
+
+	resource := rl.Resource()
+	resource.InitEmpty()
+	resourceAttrs := resource.Attributes()
+	obj := event.InvolvedObject
+	putNonEmptyString(resourceAttrs, objectAttributeKind, obj.Kind)
+	putNonEmptyString(resourceAttrs, objectAttributeName, obj.Name)
+	putNonEmptyString(resourceAttrs, objectAttributeUID, string(obj.UID))
+	putNonEmptyString(resourceAttrs, objectAttributeAPIVersion, obj.APIVersion)
+	putNonEmptyString(resourceAttrs, objectAttributeFieldPath, obj.FieldPath)
+	putNonEmptyString(resourceAttrs, objectAttributeResourceVersion, obj.ResourceVersion)
+	putNonEmptyString(resourceAttrs, conventions.AttributeK8sNamespace, obj.Namespace)
+	// Also describe pods and nodes with their own attributes, to correlate events
+	// with the other data of the pods and nodes.
+	switch obj.Kind {
+	case "Pod":
+		putNonEmptyString(resourceAttrs, conventions.AttributeK8sPod, obj.Name)
+		putNonEmptyString(resourceAttrs, conventions.AttributeK8sPodUID, string(obj.UID))
+	case "Node":
+		putNonEmptyString(resourceAttrs, nodeAttributeName, obj.Name)
+		putNonEmptyString(resourceAttrs, nodeAttributeUID, string(obj.UID))
+	}
+
+	ills := rl.InstrumentationLibraryLogs()
+	ills.Resize(1)
+	logs := ills.At(0).Logs()
+	logs.Resize(1)
+	lr := logs.At(0)
+	lr.InitEmpty()
+
+	lr.SetName(event.Reason)
+	lr.SetTimestamp(pdata.TimestampUnixNano(uint64(eventTime(event).UnixNano())))
+	lr.SetSeverityText(event.Type)
+	if event.Type == corev1.EventTypeWarning {
+		lr.SetSeverityNumber(pdata.SeverityNumberWARN)
+	} else {
+		lr.SetSeverityNumber(pdata.SeverityNumberINFO)
+	}
+	lr.Body().SetStringVal(event.Message)
+
+	attrs := lr.Attributes()
+	attrs.InitEmptyWithCapacity(9)
+	putNonEmptyString(attrs, eventAttributeName, event.Name)
+	putNonEmptyString(attrs, eventAttributeUID, string(event.UID))
+	putNonEmptyString(attrs, eventAttributeReason, event.Reason)
+	putNonEmptyString(attrs, eventAttributeType, event.Type)
+	putNonEmptyString(attrs, eventAttributeAction, event.Action)
+	putNonEmptyString(attrs, eventAttributeComponent, eventComponent(event))
+	putNonEmptyString(attrs, eventAttributeHost, event.Source.Host)
+	attrs.InsertInt(eventAttributeCount, int64(eventCount(event)))
+	if !event.FirstTimestamp.IsZero() {
+		attrs.InsertString(eventAttributeStartTime, event.FirstTimestamp.UTC().Format(time.RFC3339))
+	}
+
+	return ld
+}
+
+// eventCount returns the number of times an event occurred.
+func eventCount(event *corev1.Event) int32 {
+	if event.Series != nil && event.Series.Count > 0 {
+		return event.Series.Count
+	}
+	if event.Count > 0 {
+		return event.Count
+	}
+	return 1
+}
+
+// eventComponent returns the component that reported an event.
+func eventComponent(event *corev1.Event) string {
+	if event.ReportingController != "" {
+		return event.ReportingController
+	}
+	return event.Source.Component
+}
+
+func putNonEmptyString(attrs pdata.AttributeMap, key, value string) {
+	if strings.TrimSpace(value) != "" {
+		attrs.InsertString(key, value)
+	}
+}
@@ -0,0 +1,209 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sclusterreceiver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+func newEvent(name, namespace string, lastTimestamp time.Time) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       namespace,
+			UID:             types.UID(name + "-uid"),
+			ResourceVersion: "1",
+		},
+		InvolvedObject: corev1.ObjectReference{
+			Kind:            "Pod",
+			Namespace:       namespace,
+			Name:            "pod-1",
+			UID:             "pod-1-uid",
+			APIVersion:      "v1",
+			ResourceVersion: "1234",
+			FieldPath:       "spec.containers{redis}",
+		},
+		Reason:         "BackOff",
+		Message:        "Back-off restarting failed container",
+		Type:           corev1.EventTypeWarning,
+		Count:          3,
+		FirstTimestamp: metav1.NewTime(lastTimestamp.Add(-time.Minute)),
+		LastTimestamp:  metav1.NewTime(lastTimestamp),
+		Source: corev1.EventSource{
+			Component: "kubelet",
+			Host:      "node-1",
+		},
+	}
+}
+
+func attributesToMap(attrs pdata.AttributeMap) map[string]interface{} {
+	out := map[string]interface{}{}
+	attrs.ForEach(func(k string, v pdata.AttributeValue) {
+		switch v.Type() {
+		case pdata.AttributeValueINT:
+			out[k] = v.IntVal()
+		default:
+			out[k] = v.StringVal()
+		}
+	})
+	return out
+}
+
+func TestEventToLogs(t *testing.T) {
+	lastTimestamp := time.Date(2020, 10, 1, 10, 0, 0, 0, time.UTC)
+	ld := eventToLogs(newEvent("event-1", "default", lastTimestamp))
+
+	require.Equal(t, 1, ld.LogRecordCount())
+	rl := ld.ResourceLogs().At(0)
+	assert.Equal(t, map[string]interface{}{
+		"k8s.object.kind":             "Pod",
+		"k8s.object.name":             "pod-1",
+		"k8s.object.uid":              "pod-1-uid",
+		"k8s.object.api_version":      "v1",
+		"k8s.object.fieldpath":        "spec.containers{redis}",
+		"k8s.object.resource_version": "1234",
+		"k8s.namespace.name":          "default",
+		"k8s.pod.name":                "pod-1",
+		"k8s.pod.uid":                 "pod-1-uid",
+	}, attributesToMap(rl.Resource().Attributes()))
+
+	lr := rl.InstrumentationLibraryLogs().At(0).Logs().At(0)
+	assert.Equal(t, "BackOff", lr.Name())
+	assert.Equal(t, "Back-off restarting failed container", lr.Body().StringVal())
+	assert.Equal(t, "Warning", lr.SeverityText())
+	assert.Equal(t, pdata.SeverityNumberWARN, lr.SeverityNumber())
+	assert.Equal(t, pdata.TimestampUnixNano(lastTimestamp.UnixNano()), lr.Timestamp())
+	assert.Equal(t, map[string]interface{}{
+		"k8s.event.name":       "event-1",
+		"k8s.event.uid":        "event-1-uid",
+		"k8s.event.reason":     "BackOff",
+		"k8s.event.type":       "Warning",
+		"k8s.event.count":      int64(3),
+		"k8s.event.component":  "kubelet",
+		"k8s.event.host":       "node-1",
+		"k8s.event.start_time": "2020-10-01T09:59:00Z",
+	}, attributesToMap(lr.Attributes()))
+}
+
+func TestEventToLogsNodeEvent(t *testing.T) {
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{Name: "event-1", UID: "event-1-uid"},
+		InvolvedObject: corev1.ObjectReference{
+			Kind: "Node",
+			Name: "node-1",
+			UID:  "node-1-uid",
+		},
+		Reason:              "NodeNotReady",
+		Type:                corev1.EventTypeNormal,
+		EventTime:           metav1.NewMicroTime(time.Unix(100, 0)),
+		ReportingController: "node-controller",
+		Series:              &corev1.EventSeries{Count: 5, LastObservedTime: metav1.NewMicroTime(time.Unix(200, 0))},
+	}
+	ld := eventToLogs(event)
+
+	rl := ld.ResourceLogs().At(0)
+	assert.Equal(t, map[string]interface{}{
+		"k8s.object.kind": "Node",
+		"k8s.object.name": "node-1",
+		"k8s.object.uid":  "node-1-uid",
+		"k8s.node.name":   "node-1",
+		"k8s.node.uid":    "node-1-uid",
+	}, attributesToMap(rl.Resource().Attributes()))
+
+	lr := rl.InstrumentationLibraryLogs().At(0).Logs().At(0)
+	assert.Equal(t, pdata.SeverityNumberINFO, lr.SeverityNumber())
+	assert.Equal(t, pdata.TimestampUnixNano(time.Unix(200, 0).UnixNano()), lr.Timestamp())
+	attrs := attributesToMap(lr.Attributes())
+	assert.Equal(t, int64(5), attrs["k8s.event.count"])
+	assert.Equal(t, "node-controller", attrs["k8s.event.component"])
+}
+
+func TestEventWatcher(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	sink := &exportertest.SinkLogsExporter{}
+	ew := newEventWatcher(zap.NewNop(), client, []string{"default", "kube-system"}, sink)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ew.start(ctx)
+
+	now := time.Now()
+	for _, event := range []*corev1.Event{
+		newEvent("event-1", "default", now),
+		newEvent("event-2", "kube-system", now),
+		// Filtered out by namespace.
+		newEvent("event-3", "other", now),
+		// Occurred before the watcher was started.
+		newEvent("event-4", "default", now.Add(-time.Hour)),
+	} {
+		_, err := client.CoreV1().Events(event.Namespace).Create(ctx, event, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	require.Eventually(t, func() bool {
+		return sink.LogRecordsCount() == 2
+	}, 10*time.Second, 100*time.Millisecond, "events not collected")
+
+	names := map[string]bool{}
+	for _, ld := range sink.AllLogs() {
+		attrs := attributesToMap(ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0).Attributes())
+		names[attrs["k8s.event.name"].(string)] = true
+	}
+	assert.True(t, names["event-1"])
+	assert.True(t, names["event-2"])
+}
+
+func TestEventWatcherDeduplication(t *testing.T) {
+	sink := &exportertest.SinkLogsExporter{}
+	ew := newEventWatcher(zap.NewNop(), fake.NewSimpleClientset(), nil, sink)
+	ew.ctx = context.Background()
+	ew.startTime = time.Now().Add(-time.Minute)
+
+	event := newEvent("event-1", "default", time.Now())
+	ew.onAdd(event)
+	assert.Equal(t, 1, sink.LogRecordsCount())
+
+	// Informers replay the events they already know about when relisting.
+	ew.onAdd(event)
+	assert.Equal(t, 1, sink.LogRecordsCount())
+
+	// The count of the event was incremented.
+	updated := event.DeepCopy()
+	updated.ResourceVersion = "2"
+	updated.Count = 4
+	ew.onAdd(updated)
+	assert.Equal(t, 2, sink.LogRecordsCount())
+
+	ew.onDelete(cache.DeletedFinalStateUnknown{Key: "default/event-1", Obj: updated})
+	assert.Empty(t, ew.sentVersions)
+
+	// Objects other than events are ignored.
+	ew.onAdd(&corev1.Pod{})
+	ew.onDelete(&corev1.Pod{})
+	assert.Equal(t, 2, sink.LogRecordsCount())
+}
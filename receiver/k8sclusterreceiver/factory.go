@@ -16,12 +16,14 @@ package k8sclusterreceiver
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/receiver/receiverhelper"
+	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/k8sconfig"
 )
@@ -53,19 +55,52 @@ func createDefaultConfig() configmodels.Receiver {
 func createMetricsReceiver(
 	_ context.Context, params component.ReceiverCreateParams, cfg configmodels.Receiver,
 	consumer consumer.MetricsConsumer) (component.MetricsReceiver, error) {
-	rCfg := cfg.(*Config)
+	r, err := getReceiver(params.Logger, cfg.(*Config))
+	if err != nil {
+		return nil, err
+	}
+	r.metricsConsumer = consumer
+	return r, nil
+}
+
+func createLogsReceiver(
+	_ context.Context, params component.ReceiverCreateParams, cfg configmodels.Receiver,
+	consumer consumer.LogsConsumer) (component.LogsReceiver, error) {
+	r, err := getReceiver(params.Logger, cfg.(*Config))
+	if err != nil {
+		return nil, err
+	}
+	r.logsConsumer = consumer
+	return r, nil
+}
+
+// getReceiver returns the receiver of the given config, the same instance being
+// shared by the metrics and logs pipelines.
+func getReceiver(logger *zap.Logger, rCfg *Config) (*kubernetesReceiver, error) {
+	receiversLock.Lock()
+	defer receiversLock.Unlock()
+
+	if r := receivers[rCfg]; r != nil {
+		return r, nil
+	}
 
 	k8sClient, err := rCfg.getK8sClient()
 	if err != nil {
 		return nil, err
 	}
-	return newReceiver(params.Logger, rCfg, consumer, k8sClient)
+	r := newReceiver(logger, rCfg, k8sClient)
+	receivers[rCfg] = r
+	return r, nil
 }
 
+var receiversLock sync.Mutex
+var receivers = map[*Config]*kubernetesReceiver{}
+
 // NewFactory creates a factory for k8s_cluster receiver.
 func NewFactory() component.ReceiverFactory {
 	return receiverhelper.NewFactory(
 		typeStr,
 		createDefaultConfig,
-		receiverhelper.WithMetrics(createMetricsReceiver))
+		receiverhelper.WithMetrics(createMetricsReceiver),
+		receiverhelper.WithLogs(createLogsReceiver))
 }
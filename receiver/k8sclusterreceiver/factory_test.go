@@ -75,6 +75,16 @@ func TestFactory(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, r)
 
+	// The same receiver is used by the logs pipelines.
+	lr, err := f.CreateLogsReceiver(
+		context.Background(), component.ReceiverCreateParams{Logger: zap.NewNop()},
+		rCfg, &exportertest.SinkLogsExporter{},
+	)
+	require.NoError(t, err)
+	require.Same(t, r, lr)
+	require.NotNil(t, r.(*kubernetesReceiver).logsConsumer)
+	r.(*kubernetesReceiver).logsConsumer = nil
+
 	// Test metadata exporters setup.
 	ctx := context.Background()
 	require.NoError(t, r.Start(ctx, nopHostWithExporters{}))
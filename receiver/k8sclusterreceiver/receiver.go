@@ -35,24 +35,42 @@ const (
 )
 
 var _ component.MetricsReceiver = (*kubernetesReceiver)(nil)
+var _ component.LogsReceiver = (*kubernetesReceiver)(nil)
 
+// kubernetesReceiver collects metrics about the objects of the cluster and sends its
+// events as logs. A single instance is shared by the metrics and logs pipelines.
 type kubernetesReceiver struct {
 	resourceWatcher *resourceWatcher
-
-	config   *Config
-	logger   *zap.Logger
-	consumer consumer.MetricsConsumer
-	cancel   context.CancelFunc
+	eventWatcher    *eventWatcher
+
+	config          *Config
+	logger          *zap.Logger
+	client          kubernetes.Interface
+	metricsConsumer consumer.MetricsConsumer
+	logsConsumer    consumer.LogsConsumer
+	cancel          context.CancelFunc
 }
 
 func (kr *kubernetesReceiver) Start(ctx context.Context, host component.Host) error {
 	var c context.Context
 	c, kr.cancel = context.WithCancel(obsreport.ReceiverContext(ctx, typeStr, transport, kr.config.Name()))
 
-	exporters := host.GetExporters()
-	if err := kr.resourceWatcher.setupMetadataExporters(
-		exporters[configmodels.MetricsDataType], kr.config.MetadataExporters); err != nil {
-		return err
+	if kr.metricsConsumer != nil {
+		exporters := host.GetExporters()
+		if err := kr.resourceWatcher.setupMetadataExporters(
+			exporters[configmodels.MetricsDataType], kr.config.MetadataExporters); err != nil {
+			return err
+		}
+	}
+
+	if kr.logsConsumer != nil {
+		kr.logger.Info("Starting to watch Kubernetes events.")
+		kr.eventWatcher = newEventWatcher(kr.logger, kr.client, kr.config.EventNamespaces, kr.logsConsumer)
+		kr.eventWatcher.start(c)
+	}
+
+	if kr.metricsConsumer == nil {
+		return nil
 	}
 
 	go func() {
@@ -105,20 +123,19 @@ func (kr *kubernetesReceiver) dispatchMetrics(ctx context.Context) {
 
 	numTimeseries, numPoints := resourceMetrics.MetricAndDataPointCount()
 
-	err := kr.consumer.ConsumeMetrics(c, resourceMetrics)
+	err := kr.metricsConsumer.ConsumeMetrics(c, resourceMetrics)
 	obsreport.EndMetricsReceiveOp(c, typeStr, numPoints, numTimeseries, err)
 }
 
 // newReceiver creates the Kubernetes cluster receiver with the given configuration.
-func newReceiver(
-	logger *zap.Logger, config *Config, consumer consumer.MetricsConsumer,
-	client kubernetes.Interface) (component.MetricsReceiver, error) {
+// The consumers are set when the receiver is created for a pipeline.
+func newReceiver(logger *zap.Logger, config *Config, client kubernetes.Interface) *kubernetesReceiver {
 	resourceWatcher := newResourceWatcher(logger, client, config.NodeConditionTypesToReport, defaultInitialSyncTimeout)
 
 	return &kubernetesReceiver{
 		resourceWatcher: resourceWatcher,
 		logger:          logger,
 		config:          config,
-		consumer:        consumer,
-	}, nil
+		client:          client,
+	}
 }
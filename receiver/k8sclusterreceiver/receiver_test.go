@@ -156,6 +156,30 @@ func TestReceiverWithMetadata(t *testing.T) {
 	r.Shutdown(ctx)
 }
 
+func TestReceiverWithEvents(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	logsConsumer := &exportertest.SinkLogsExporter{}
+
+	r := newReceiver(zap.NewNop(), &Config{CollectionInterval: time.Second}, client)
+	r.logsConsumer = logsConsumer
+
+	ctx := context.Background()
+	require.NoError(t, r.Start(ctx, componenttest.NewNopHost()))
+	// Only the events are watched when the receiver is not part of a metrics pipeline.
+	require.Nil(t, r.resourceWatcher.timedContextForInitialSync)
+
+	event := newEvent("event-1", "default", time.Now())
+	_, err := client.CoreV1().Events("default").Create(ctx, event, v1.CreateOptions{})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return logsConsumer.LogRecordsCount() == 1
+	}, 10*time.Second, 100*time.Millisecond,
+		"events not collected")
+
+	require.NoError(t, r.Shutdown(ctx))
+}
+
 func getUpdatedPod(pod *corev1.Pod) interface{} {
 	return &corev1.Pod{
 		ObjectMeta: v1.ObjectMeta{
@@ -187,6 +211,6 @@ func setupReceiver(
 		resourceWatcher: rw,
 		logger:          logger,
 		config:          config,
-		consumer:        consumer,
+		metricsConsumer: consumer,
 	}, nil
 }
@@ -81,6 +81,10 @@ func (rw *resourceWatcher) prepareSharedInformerFactory() {
 	)
 	rw.setupInformers(&corev1.ResourceQuota{}, factory.Core().V1().ResourceQuotas().Informer())
 	rw.setupInformers(&corev1.Service{}, factory.Core().V1().Services().Informer())
+	rw.setupInformers(&corev1.Endpoints{}, factory.Core().V1().Endpoints().Informer())
+	rw.setupInformers(&corev1.PersistentVolumeClaim{},
+		factory.Core().V1().PersistentVolumeClaims().Informer())
+	rw.setupInformers(&corev1.PersistentVolume{}, factory.Core().V1().PersistentVolumes().Informer())
 	rw.setupInformers(&appsv1.DaemonSet{}, factory.Apps().V1().DaemonSets().Informer())
 	rw.setupInformers(&appsv1.Deployment{}, factory.Apps().V1().Deployments().Informer())
 	rw.setupInformers(&appsv1.ReplicaSet{}, factory.Apps().V1().ReplicaSets().Informer())
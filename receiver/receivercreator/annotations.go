@@ -0,0 +1,168 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package receivercreator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
+)
+
+const (
+	// receiverConfigAnnotation is the pod annotation, or container label, holding the
+	// JSON config of the receiver to create for the endpoint. The receiver type is
+	// set by its "type" key, e.g. {"type":"redis","collection_interval":"10s"}.
+	receiverConfigAnnotation = "otel.receiver/config"
+	// annotationTypeKey is the key of the receiver type in receiverConfigAnnotation.
+	annotationTypeKey = "type"
+	// annotationReceiverName is the name of the receivers created from receiverConfigAnnotation.
+	annotationReceiverName = "annotation"
+	// prometheusReceiverName is the name of the receivers created from the prometheus
+	// annotations, distinct from annotationReceiverName so that a prometheus_simple
+	// receiver requested by both does not replace the other.
+	prometheusReceiverName = "prometheus_annotation"
+
+	prometheusScrapeAnnotation = "prometheus.io/scrape"
+	prometheusPortAnnotation   = "prometheus.io/port"
+	prometheusPathAnnotation   = "prometheus.io/path"
+	prometheusDefaultPort      = "9090"
+	prometheusSimpleType       = configmodels.Type("prometheus_simple")
+)
+
+var errMissingAnnotationType = errors.New("receiver type is missing")
+
+// isAllowed returns whether receivers of the given type can be created from annotations.
+func (cfg *AnnotationDiscoveryConfig) isAllowed(typeStr configmodels.Type) bool {
+	for _, allowed := range cfg.AllowedReceivers {
+		if allowed == typeStr {
+			return true
+		}
+	}
+	return false
+}
+
+// receiversFromAnnotations returns the receivers requested by the annotations of a pod
+// endpoint or the labels of a container endpoint, with their config merged on top of
+// the configured defaults. The configs are not expanded yet. An error is returned along
+// with the valid receivers if the receiverConfigAnnotation is invalid.
+func (cfg *AnnotationDiscoveryConfig) receiversFromAnnotations(e observer.Endpoint) ([]receiverConfig, error) {
+	var annotations map[string]string
+	switch o := e.Details.(type) {
+	case observer.Pod:
+		annotations = o.Annotations
+	case observer.Container:
+		annotations = o.Labels
+	default:
+		return nil, nil
+	}
+
+	var receivers []receiverConfig
+	var err error
+
+	if value, ok := annotations[receiverConfigAnnotation]; ok {
+		var rcvr receiverConfig
+		if rcvr, err = cfg.parseReceiverConfigAnnotation(value); err == nil {
+			receivers = append(receivers, rcvr)
+		} else {
+			err = fmt.Errorf("invalid %s annotation: %v", receiverConfigAnnotation, err)
+		}
+	}
+
+	// The Prometheus annotations are a Kubernetes convention only looked for on pods.
+	if _, isPod := e.Details.(observer.Pod); isPod && annotations[prometheusScrapeAnnotation] == "true" &&
+		cfg.isAllowed(prometheusSimpleType) {
+		rcvr, promErr := cfg.prometheusReceiverConfig(annotations)
+		if promErr == nil {
+			receivers = append(receivers, rcvr)
+		} else if err == nil {
+			err = promErr
+		}
+	}
+
+	return receivers, err
+}
+
+// parseReceiverConfigAnnotation parses the value of receiverConfigAnnotation.
+func (cfg *AnnotationDiscoveryConfig) parseReceiverConfigAnnotation(value string) (receiverConfig, error) {
+	var annotated map[string]interface{}
+	if err := json.Unmarshal([]byte(value), &annotated); err != nil {
+		return receiverConfig{}, err
+	}
+
+	typeStr, _ := annotated[annotationTypeKey].(string)
+	if typeStr == "" {
+		return receiverConfig{}, errMissingAnnotationType
+	}
+	if !cfg.isAllowed(configmodels.Type(typeStr)) {
+		return receiverConfig{}, fmt.Errorf("receiver type %q is not allowed", typeStr)
+	}
+	delete(annotated, annotationTypeKey)
+
+	return cfg.newAnnotationReceiverConfig(configmodels.Type(typeStr), annotationReceiverName, annotated), nil
+}
+
+// prometheusReceiverConfig returns the prometheus_simple receiver scraping the pod
+// according to the prometheus.io/port and prometheus.io/path annotations. The
+// port defaults to 9090 and the path to the one of the receiver.
+func (cfg *AnnotationDiscoveryConfig) prometheusReceiverConfig(annotations map[string]string) (receiverConfig, error) {
+	port := prometheusDefaultPort
+	if value, ok := annotations[prometheusPortAnnotation]; ok {
+		if _, err := strconv.ParseUint(value, 10, 16); err != nil {
+			return receiverConfig{}, fmt.Errorf("invalid %s annotation %q", prometheusPortAnnotation, value)
+		}
+		port = value
+	}
+
+	config := map[string]interface{}{
+		endpointConfigKey: "`endpoint`:" + port,
+	}
+	if value, ok := annotations[prometheusPathAnnotation]; ok {
+		config["metrics_path"] = value
+	}
+
+	return cfg.newAnnotationReceiverConfig(prometheusSimpleType, prometheusReceiverName, config), nil
+}
+
+func (cfg *AnnotationDiscoveryConfig) newAnnotationReceiverConfig(typeStr configmodels.Type, name string, config map[string]interface{}) receiverConfig {
+	return receiverConfig{
+		fullName: fmt.Sprintf("%s/%s", typeStr, name),
+		typeStr:  typeStr,
+		config:   mergeConfigMaps(cfg.Defaults[string(typeStr)], config),
+	}
+}
+
+// mergeConfigMaps returns a copy of base with the values of override set on top of it.
+// Nested maps are merged recursively.
+func mergeConfigMaps(base, override map[string]interface{}) userConfigMap {
+	merged := userConfigMap{}
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range override {
+		if overrideMap, ok := v.(map[string]interface{}); ok {
+			if baseMap, ok := merged[k].(map[string]interface{}); ok {
+				merged[k] = map[string]interface{}(mergeConfigMaps(baseMap, overrideMap))
+				continue
+			}
+		}
+		merged[k] = v
+	}
+	return merged
+}
@@ -0,0 +1,202 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package receivercreator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config/configmodels"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
+)
+
+func podWithAnnotations(annotations map[string]string) observer.Endpoint {
+	return observer.Endpoint{
+		ID:      "pod-1",
+		Target:  "10.0.0.2",
+		Details: observer.Pod{Name: "pod-1", Annotations: annotations},
+	}
+}
+
+func TestReceiversFromAnnotations(t *testing.T) {
+	cfg := &AnnotationDiscoveryConfig{
+		Enabled:          true,
+		AllowedReceivers: []configmodels.Type{"redis", "prometheus_simple"},
+		Defaults: map[string]map[string]interface{}{
+			"redis": {
+				"collection_interval": "30s",
+				"password":            "secret",
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		endpoint observer.Endpoint
+		want     []receiverConfig
+		wantErr  string
+	}{
+		{
+			name: "receiver config merged with defaults",
+			endpoint: podWithAnnotations(map[string]string{
+				receiverConfigAnnotation: `{"type":"redis","collection_interval":"10s","endpoint":"` + "`endpoint`" + `:6379"}`,
+			}),
+			want: []receiverConfig{{
+				fullName: "redis/annotation",
+				typeStr:  "redis",
+				config: userConfigMap{
+					"collection_interval": "10s",
+					"password":            "secret",
+					"endpoint":            "`endpoint`:6379",
+				},
+			}},
+		},
+		{
+			name: "container labels",
+			endpoint: observer.Endpoint{
+				ID:     "container-1",
+				Target: "172.17.0.2:6379",
+				Details: observer.Container{
+					Name:   "redis",
+					Labels: map[string]string{receiverConfigAnnotation: `{"type":"redis"}`},
+				},
+			},
+			want: []receiverConfig{{
+				fullName: "redis/annotation",
+				typeStr:  "redis",
+				config: userConfigMap{
+					"collection_interval": "30s",
+					"password":            "secret",
+				},
+			}},
+		},
+		{
+			name: "prometheus annotations",
+			endpoint: podWithAnnotations(map[string]string{
+				prometheusScrapeAnnotation: "true",
+				prometheusPortAnnotation:   "8080",
+				prometheusPathAnnotation:   "/stats",
+			}),
+			want: []receiverConfig{{
+				fullName: "prometheus_simple/prometheus_annotation",
+				typeStr:  "prometheus_simple",
+				config: userConfigMap{
+					"endpoint":     "`endpoint`:8080",
+					"metrics_path": "/stats",
+				},
+			}},
+		},
+		{
+			name:     "prometheus default port",
+			endpoint: podWithAnnotations(map[string]string{prometheusScrapeAnnotation: "true"}),
+			want: []receiverConfig{{
+				fullName: "prometheus_simple/prometheus_annotation",
+				typeStr:  "prometheus_simple",
+				config:   userConfigMap{"endpoint": "`endpoint`:9090"},
+			}},
+		},
+		{
+			name: "prometheus invalid port",
+			endpoint: podWithAnnotations(map[string]string{
+				prometheusScrapeAnnotation: "true",
+				prometheusPortAnnotation:   "http",
+			}),
+			wantErr: `invalid prometheus.io/port annotation "http"`,
+		},
+		{
+			name:     "prometheus scrape disabled",
+			endpoint: podWithAnnotations(map[string]string{prometheusScrapeAnnotation: "false"}),
+		},
+		{
+			name:     "invalid json",
+			endpoint: podWithAnnotations(map[string]string{receiverConfigAnnotation: `{"type":`}),
+			wantErr:  "invalid otel.receiver/config annotation: unexpected end of JSON input",
+		},
+		{
+			name:     "missing type",
+			endpoint: podWithAnnotations(map[string]string{receiverConfigAnnotation: `{"endpoint":"localhost"}`}),
+			wantErr:  "invalid otel.receiver/config annotation: receiver type is missing",
+		},
+		{
+			name:     "type not allowed",
+			endpoint: podWithAnnotations(map[string]string{receiverConfigAnnotation: `{"type":"mysql"}`}),
+			wantErr:  `invalid otel.receiver/config annotation: receiver type "mysql" is not allowed`,
+		},
+		{
+			name: "invalid annotation does not prevent prometheus",
+			endpoint: podWithAnnotations(map[string]string{
+				receiverConfigAnnotation:   `{"type":"mysql"}`,
+				prometheusScrapeAnnotation: "true",
+			}),
+			want: []receiverConfig{{
+				fullName: "prometheus_simple/prometheus_annotation",
+				typeStr:  "prometheus_simple",
+				config:   userConfigMap{"endpoint": "`endpoint`:9090"},
+			}},
+			wantErr: `invalid otel.receiver/config annotation: receiver type "mysql" is not allowed`,
+		},
+		{
+			name:     "unsupported endpoint",
+			endpoint: portEndpoint,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cfg.receiversFromAnnotations(tt.endpoint)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestReceiversFromAnnotationsPrometheusNotAllowed(t *testing.T) {
+	cfg := &AnnotationDiscoveryConfig{Enabled: true, AllowedReceivers: []configmodels.Type{"redis"}}
+	got, err := cfg.receiversFromAnnotations(podWithAnnotations(map[string]string{prometheusScrapeAnnotation: "true"}))
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func TestMergeConfigMaps(t *testing.T) {
+	base := map[string]interface{}{
+		"a": "base",
+		"b": "base",
+		"nested": map[string]interface{}{
+			"c": "base",
+			"d": "base",
+		},
+	}
+	override := map[string]interface{}{
+		"b": "override",
+		"nested": map[string]interface{}{
+			"d": "override",
+		},
+	}
+	assert.Equal(t, userConfigMap{
+		"a": "base",
+		"b": "override",
+		"nested": map[string]interface{}{
+			"c": "base",
+			"d": "override",
+		},
+	}, mergeConfigMaps(base, override))
+	assert.Equal(t, "base", base["b"])
+	assert.Equal(t, userConfigMap{}, mergeConfigMaps(nil, nil))
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/spf13/viper"
 	otelconfig "go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/translator/conventions"
 )
 
 const (
@@ -30,6 +31,9 @@ const (
 	endpointConfigKey = "endpoint"
 	// configKey is the key name in a subreceiver.
 	configKey = "config"
+	// resourceAttributesConfigKey is the config key name of the resource attributes
+	// added to the data of the subreceivers.
+	resourceAttributesConfigKey = "resource_attributes"
 )
 
 // receiverConfig describes a receiver instance with a default config.
@@ -77,8 +81,59 @@ func newReceiverTemplate(name string, config userConfigMap) (receiverTemplate, e
 type Config struct {
 	configmodels.ReceiverSettings `mapstructure:",squash"`
 	receiverTemplates             map[string]receiverTemplate
+	// resourceAttributes are the resource attributes added to the data of the
+	// subreceivers, set under the "resource_attributes" key. Its keys contain dots
+	// so it is read by the custom unmarshaler.
+	resourceAttributes resourceAttributes
 	// WatchObservers are the extensions to listen to endpoints from.
 	WatchObservers []configmodels.Type `mapstructure:"watch_observers"`
+	// AnnotationDiscovery configures the receivers created from the annotations of
+	// discovered pods and the labels of discovered containers.
+	AnnotationDiscovery AnnotationDiscoveryConfig `mapstructure:"annotation_discovery"`
+}
+
+// AnnotationDiscoveryConfig configures the creation of receivers from the
+// annotations and labels set on discovered endpoints.
+type AnnotationDiscoveryConfig struct {
+	// Enabled turns on the creation of receivers from annotations and labels.
+	Enabled bool `mapstructure:"enabled"`
+	// AllowedReceivers is the list of receiver types that can be created from
+	// annotations and labels. Endpoints requesting other types are reported as invalid.
+	AllowedReceivers []configmodels.Type `mapstructure:"allowed_receivers"`
+	// Defaults maps receiver types to the config used as a base for the receivers
+	// of that type, the config from the annotation being merged on top of it.
+	Defaults map[string]map[string]interface{} `mapstructure:"defaults"`
+}
+
+// resourceAttributes maps endpoint types to the resource attributes added to the
+// data of the receivers created for endpoints of that type. The attribute values
+// can contain dynamic values evaluated against the endpoint, like receiver configs.
+type resourceAttributes map[string]map[string]string
+
+// defaultResourceAttributes returns the resource attributes set by default for
+// each endpoint type.
+func defaultResourceAttributes() resourceAttributes {
+	return resourceAttributes{
+		"pod": {
+			conventions.AttributeK8sPod: "`name`",
+		},
+		"port": {
+			conventions.AttributeK8sPod: "`pod.name`",
+		},
+		"container": {
+			conventions.AttributeContainerName:  "`name`",
+			conventions.AttributeContainerImage: "`image`",
+			conventions.AttributeContainerID:    "`container_id`",
+		},
+		"k8s_node": {
+			"k8s.node.name": "`name`",
+			"k8s.node.uid":  "`uid`",
+		},
+		"k8s_service": {
+			conventions.AttributeK8sNamespace: "`namespace`",
+			"k8s.service.name":                "`name`",
+		},
+	}
 }
 
 // Copied from the Viper but changed to use the same delimiter.
@@ -86,4 +86,27 @@ func TestLoadConfig(t *testing.T) {
 		endpointConfigKey: "localhost:12345",
 	}, r1.receiverTemplates["examplereceiver/1"].config)
 	assert.Equal(t, []configmodels.Type{"mock_observer"}, r1.WatchObservers)
+
+	expectedAttrs := defaultResourceAttributes()
+	expectedAttrs["port"]["service.name"] = "`pod.labels[\"app\"]`"
+	delete(expectedAttrs["container"], "container.image.name")
+	assert.Equal(t, expectedAttrs, r1.resourceAttributes)
+
+	assert.Equal(t, AnnotationDiscoveryConfig{
+		Enabled:          true,
+		AllowedReceivers: []configmodels.Type{"examplereceiver", "prometheus_simple"},
+		Defaults: map[string]map[string]interface{}{
+			"examplereceiver": {endpointConfigKey: "localhost:12346"},
+		},
+	}, r1.AnnotationDiscovery)
+}
+
+func TestInvalidResourceAttributesEndpointType(t *testing.T) {
+	factories, err := componenttest.ExampleComponents()
+	require.Nil(t, err)
+	factories.Receivers[configmodels.Type(typeStr)] = NewFactory()
+
+	_, err = configtest.LoadConfigFile(t, path.Join(".", "testdata", "invalid-resource-attributes.yaml"), factories)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `resource attributes for unsupported endpoint type "unknown"`)
 }
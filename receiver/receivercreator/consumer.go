@@ -0,0 +1,80 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package receivercreator
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+var _ consumer.MetricsConsumer = (*enhancingConsumer)(nil)
+var _ consumer.TraceConsumer = (*enhancingConsumer)(nil)
+var _ consumer.LogsConsumer = (*enhancingConsumer)(nil)
+
+// enhancingConsumer adds the resource attributes of the discovered endpoint to the
+// data of a receiver created at runtime before passing it to the next consumers of
+// the receiver_creator. Attributes already set by the receiver are kept. The next
+// consumers of the signals the receiver_creator is not used for are nil.
+type enhancingConsumer struct {
+	metrics consumer.MetricsConsumer
+	traces  consumer.TraceConsumer
+	logs    consumer.LogsConsumer
+	attrs   map[string]string
+}
+
+func (ec *enhancingConsumer) ConsumeMetrics(ctx context.Context, md pdata.Metrics) error {
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		if rm := rms.At(i); !rm.IsNil() {
+			ec.putAttrs(rm.Resource())
+		}
+	}
+	return ec.metrics.ConsumeMetrics(ctx, md)
+}
+
+func (ec *enhancingConsumer) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		if rs := rss.At(i); !rs.IsNil() {
+			ec.putAttrs(rs.Resource())
+		}
+	}
+	return ec.traces.ConsumeTraces(ctx, td)
+}
+
+func (ec *enhancingConsumer) ConsumeLogs(ctx context.Context, ld pdata.Logs) error {
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		if rl := rls.At(i); !rl.IsNil() {
+			ec.putAttrs(rl.Resource())
+		}
+	}
+	return ec.logs.ConsumeLogs(ctx, ld)
+}
+
+func (ec *enhancingConsumer) putAttrs(resource pdata.Resource) {
+	if len(ec.attrs) == 0 {
+		return
+	}
+	if resource.IsNil() {
+		resource.InitEmpty()
+	}
+	attrs := resource.Attributes()
+	for k, v := range ec.attrs {
+		attrs.InsertString(k, v)
+	}
+}
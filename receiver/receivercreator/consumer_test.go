@@ -0,0 +1,90 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package receivercreator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+)
+
+func assertResourceAttrs(t *testing.T, expected map[string]string, resource pdata.Resource) {
+	require.False(t, resource.IsNil())
+	actual := map[string]string{}
+	resource.Attributes().ForEach(func(k string, v pdata.AttributeValue) {
+		actual[k] = v.StringVal()
+	})
+	assert.Equal(t, expected, actual)
+}
+
+func TestEnhancingConsumer(t *testing.T) {
+	metricsSink := &exportertest.SinkMetricsExporter{}
+	tracesSink := &exportertest.SinkTraceExporter{}
+	logsSink := &exportertest.SinkLogsExporter{}
+	ec := &enhancingConsumer{
+		metrics: metricsSink,
+		traces:  tracesSink,
+		logs:    logsSink,
+		attrs: map[string]string{
+			"k8s.pod.name": "pod-1",
+			"service.name": "redis",
+		},
+	}
+	expected := map[string]string{
+		"k8s.pod.name": "pod-1",
+		"service.name": "from-receiver",
+	}
+
+	md := pdata.NewMetrics()
+	md.ResourceMetrics().Resize(1)
+	md.ResourceMetrics().At(0).InitEmpty()
+	md.ResourceMetrics().At(0).Resource().InitEmpty()
+	md.ResourceMetrics().At(0).Resource().Attributes().InsertString("service.name", "from-receiver")
+	require.NoError(t, ec.ConsumeMetrics(context.Background(), md))
+	require.Len(t, metricsSink.AllMetrics(), 1)
+	assertResourceAttrs(t, expected, metricsSink.AllMetrics()[0].ResourceMetrics().At(0).Resource())
+
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(1)
+	td.ResourceSpans().At(0).InitEmpty()
+	require.NoError(t, ec.ConsumeTraces(context.Background(), td))
+	require.Len(t, tracesSink.AllTraces(), 1)
+	assertResourceAttrs(t, ec.attrs, tracesSink.AllTraces()[0].ResourceSpans().At(0).Resource())
+
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(1)
+	ld.ResourceLogs().At(0).InitEmpty()
+	ld.ResourceLogs().At(0).Resource().InitEmpty()
+	ld.ResourceLogs().At(0).Resource().Attributes().InsertString("service.name", "from-receiver")
+	require.NoError(t, ec.ConsumeLogs(context.Background(), ld))
+	require.Len(t, logsSink.AllLogs(), 1)
+	assertResourceAttrs(t, expected, logsSink.AllLogs()[0].ResourceLogs().At(0).Resource())
+}
+
+func TestEnhancingConsumerNoAttrs(t *testing.T) {
+	sink := &exportertest.SinkMetricsExporter{}
+	ec := &enhancingConsumer{metrics: sink}
+
+	md := pdata.NewMetrics()
+	md.ResourceMetrics().Resize(1)
+	md.ResourceMetrics().At(0).InitEmpty()
+	require.NoError(t, ec.ConsumeMetrics(context.Background(), md))
+	require.Len(t, sink.AllMetrics(), 1)
+	assert.True(t, sink.AllMetrics()[0].ResourceMetrics().At(0).Resource().IsNil())
+}
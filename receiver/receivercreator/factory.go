@@ -17,12 +17,15 @@ package receivercreator
 import (
 	"context"
 	"fmt"
+	"sync"
 
+	"github.com/spf13/cast"
 	"github.com/spf13/viper"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/receiver/receiverhelper"
+	"go.uber.org/zap"
 )
 
 // This file implements factory for receiver_creator. A receiver_creator can create other receivers at runtime.
@@ -37,7 +40,9 @@ func NewFactory() component.ReceiverFactory {
 		typeStr,
 		createDefaultConfig,
 		receiverhelper.WithCustomUnmarshaler(customUnmarshaler),
-		receiverhelper.WithMetrics(createMetricsReceiver))
+		receiverhelper.WithMetrics(createMetricsReceiver),
+		receiverhelper.WithTraces(createTraceReceiver),
+		receiverhelper.WithLogs(createLogsReceiver))
 }
 
 func createDefaultConfig() configmodels.Receiver {
@@ -46,7 +51,8 @@ func createDefaultConfig() configmodels.Receiver {
 			TypeVal: configmodels.Type(typeStr),
 			NameVal: typeStr,
 		},
-		receiverTemplates: map[string]receiverTemplate{},
+		receiverTemplates:  map[string]receiverTemplate{},
+		resourceAttributes: defaultResourceAttributes(),
 	}
 }
 
@@ -56,9 +62,62 @@ func createMetricsReceiver(
 	cfg configmodels.Receiver,
 	consumer consumer.MetricsConsumer,
 ) (component.MetricsReceiver, error) {
-	return newReceiverCreator(params.Logger, consumer, cfg.(*Config))
+	if consumer == nil {
+		return nil, errNilNextConsumer
+	}
+
+	r := getReceiverCreator(params.Logger, cfg.(*Config))
+	r.nextMetricsConsumer = consumer
+	return r, nil
 }
 
+func createTraceReceiver(
+	ctx context.Context,
+	params component.ReceiverCreateParams,
+	cfg configmodels.Receiver,
+	consumer consumer.TraceConsumer,
+) (component.TraceReceiver, error) {
+	if consumer == nil {
+		return nil, errNilNextConsumer
+	}
+
+	r := getReceiverCreator(params.Logger, cfg.(*Config))
+	r.nextTracesConsumer = consumer
+	return r, nil
+}
+
+func createLogsReceiver(
+	ctx context.Context,
+	params component.ReceiverCreateParams,
+	cfg configmodels.Receiver,
+	consumer consumer.LogsConsumer,
+) (component.LogsReceiver, error) {
+	if consumer == nil {
+		return nil, errNilNextConsumer
+	}
+
+	r := getReceiverCreator(params.Logger, cfg.(*Config))
+	r.nextLogsConsumer = consumer
+	return r, nil
+}
+
+// getReceiverCreator returns the receiver_creator of the given config, the same
+// instance being shared by all the pipelines the receiver_creator is part of.
+func getReceiverCreator(logger *zap.Logger, cfg *Config) *receiverCreator {
+	receiversLock.Lock()
+	defer receiversLock.Unlock()
+
+	r := receivers[cfg]
+	if r == nil {
+		r = newReceiverCreator(logger, cfg)
+		receivers[cfg] = r
+	}
+	return r
+}
+
+var receiversLock sync.Mutex
+var receivers = map[*Config]*receiverCreator{}
+
 func customUnmarshaler(sourceViperSection *viper.Viper, intoCfg interface{}) error {
 	if sourceViperSection == nil {
 		// Nothing to do if there is no config given.
@@ -92,5 +151,21 @@ func customUnmarshaler(sourceViperSection *viper.Viper, intoCfg interface{}) err
 		c.receiverTemplates[subreceiverKey] = subreceiver
 	}
 
+	// Read from the raw map as viper would split the attribute names on dots.
+	for endpointType, attrs := range cast.ToStringMap(sourceViperSection.Get(resourceAttributesConfigKey)) {
+		typeAttrs, ok := c.resourceAttributes[endpointType]
+		if !ok {
+			return fmt.Errorf("resource attributes for unsupported endpoint type %q", endpointType)
+		}
+		for attr, value := range cast.ToStringMapString(attrs) {
+			// An empty value removes an attribute set by default.
+			if value == "" {
+				delete(typeAttrs, attr)
+				continue
+			}
+			typeAttrs[attr] = value
+		}
+	}
+
 	return nil
 }
@@ -20,7 +20,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"go.opentelemetry.io/collector/component"
-	"go.opentelemetry.io/collector/config/configerror"
+	"go.opentelemetry.io/collector/exporter/exportertest"
 	"go.uber.org/zap"
 )
 
@@ -37,8 +37,20 @@ func TestCreateReceiver(t *testing.T) {
 	assert.NoError(t, err, "receiver creation failed")
 	assert.NotNil(t, tReceiver, "receiver creation failed")
 
-	mReceiver, err := factory.CreateTraceReceiver(context.Background(), params, cfg, nil)
-	assert.Error(t, err)
-	assert.Equal(t, err, configerror.ErrDataTypeIsNotSupported)
-	assert.Nil(t, mReceiver)
+	trReceiver, err := factory.CreateTraceReceiver(context.Background(), params, cfg, &exportertest.SinkTraceExporter{})
+	assert.NoError(t, err, "receiver creation failed")
+	// The same receiver is shared by all the pipelines of the config.
+	assert.Same(t, tReceiver, trReceiver)
+
+	lReceiver, err := factory.CreateLogsReceiver(context.Background(), params, cfg, &exportertest.SinkLogsExporter{})
+	assert.NoError(t, err, "receiver creation failed")
+	assert.Same(t, tReceiver, lReceiver)
+
+	rc := tReceiver.(*receiverCreator)
+	assert.NotNil(t, rc.nextMetricsConsumer)
+	assert.NotNil(t, rc.nextTracesConsumer)
+	assert.NotNil(t, rc.nextLogsConsumer)
+
+	_, err = factory.CreateTraceReceiver(context.Background(), params, cfg, nil)
+	assert.Equal(t, errNilNextConsumer, err)
 }
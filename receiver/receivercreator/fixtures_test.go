@@ -46,6 +46,35 @@ var portEndpoint = observer.Endpoint{
 	},
 }
 
+var containerEndpoint = observer.Endpoint{
+	ID:     "container-1",
+	Target: "172.17.0.2:6379",
+	Details: observer.Container{
+		Name:        "redis",
+		Image:       "redis:6",
+		Port:        6379,
+		ContainerID: "container-1",
+		Host:        "172.17.0.2",
+		Transport:   observer.ProtocolTCP,
+		Labels: map[string]string{
+			"env": "prod",
+		},
+	},
+}
+
+var nodeEndpoint = observer.Endpoint{
+	ID:     "node-1",
+	Target: "10.0.0.1:10250",
+	Details: observer.K8sNode{
+		Name:                "node-1",
+		InternalIP:          "10.0.0.1",
+		KubeletEndpointPort: 10250,
+		Labels: map[string]string{
+			"zone": "us-east-1a",
+		},
+	},
+}
+
 var unsupportedEndpoint = observer.Endpoint{
 	ID:      "endpoint-1",
 	Target:  "localhost:1234",
@@ -0,0 +1,110 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package receivercreator
+
+import (
+	"context"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+)
+
+func init() {
+	view.Register(
+		viewInvalidAnnotations,
+		viewReceiversStarted,
+		viewReceiversStopped,
+		viewReceiversRestarted,
+		viewReceiverStartFailures,
+	)
+}
+
+var (
+	mInvalidAnnotations = stats.Int64(
+		"otelcol/receiver_creator/invalid_annotations",
+		"Number of endpoint annotations or labels that could not be turned into a running receiver",
+		"1")
+	mReceiversStarted = stats.Int64(
+		"otelcol/receiver_creator/receivers_started",
+		"Number of receivers started for added or changed endpoints",
+		"1")
+	mReceiversStopped = stats.Int64(
+		"otelcol/receiver_creator/receivers_stopped",
+		"Number of receivers stopped for removed endpoints or endpoints they no longer apply to",
+		"1")
+	mReceiversRestarted = stats.Int64(
+		"otelcol/receiver_creator/receivers_restarted",
+		"Number of receivers restarted because the effective config changed",
+		"1")
+	mReceiverStartFailures = stats.Int64(
+		"otelcol/receiver_creator/receiver_start_failures",
+		"Number of receivers that failed to start",
+		"1")
+)
+
+var viewInvalidAnnotations = &view.View{
+	Name:        mInvalidAnnotations.Name(),
+	Description: mInvalidAnnotations.Description(),
+	Measure:     mInvalidAnnotations,
+	Aggregation: view.Sum(),
+}
+
+var viewReceiversStarted = &view.View{
+	Name:        mReceiversStarted.Name(),
+	Description: mReceiversStarted.Description(),
+	Measure:     mReceiversStarted,
+	Aggregation: view.Sum(),
+}
+
+var viewReceiversStopped = &view.View{
+	Name:        mReceiversStopped.Name(),
+	Description: mReceiversStopped.Description(),
+	Measure:     mReceiversStopped,
+	Aggregation: view.Sum(),
+}
+
+var viewReceiversRestarted = &view.View{
+	Name:        mReceiversRestarted.Name(),
+	Description: mReceiversRestarted.Description(),
+	Measure:     mReceiversRestarted,
+	Aggregation: view.Sum(),
+}
+
+var viewReceiverStartFailures = &view.View{
+	Name:        mReceiverStartFailures.Name(),
+	Description: mReceiverStartFailures.Description(),
+	Measure:     mReceiverStartFailures,
+	Aggregation: view.Sum(),
+}
+
+func recordInvalidAnnotation() {
+	stats.Record(context.Background(), mInvalidAnnotations.M(int64(1)))
+}
+
+func recordReceiverStarted() {
+	stats.Record(context.Background(), mReceiversStarted.M(int64(1)))
+}
+
+func recordReceiverStopped() {
+	stats.Record(context.Background(), mReceiversStopped.M(int64(1)))
+}
+
+func recordReceiverRestarted() {
+	stats.Record(context.Background(), mReceiversRestarted.M(int64(1)))
+}
+
+func recordReceiverStartFailed() {
+	stats.Record(context.Background(), mReceiverStartFailures.M(int64(1)))
+}
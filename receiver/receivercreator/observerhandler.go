@@ -16,9 +16,11 @@ package receivercreator
 
 import (
 	"fmt"
+	"reflect"
 	"sync"
 
 	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
@@ -32,10 +34,19 @@ type observerHandler struct {
 	logger *zap.Logger
 	// receiverTemplates maps receiver template full name to a receiverTemplate value.
 	receiverTemplates map[string]receiverTemplate
+	// annotationDiscovery configures the receivers created from endpoint annotations.
+	annotationDiscovery AnnotationDiscoveryConfig
+	// resourceAttributes are the templated resource attributes added to the data
+	// of the receivers, by endpoint type.
+	resourceAttributes resourceAttributes
 	// receiversByEndpointID is a map of endpoint IDs to a receiver instance.
 	receiversByEndpointID receiverMap
 	// runner starts and stops receiver instances.
 	runner runner
+
+	nextMetricsConsumer consumer.MetricsConsumer
+	nextTracesConsumer  consumer.TraceConsumer
+	nextLogsConsumer    consumer.LogsConsumer
 }
 
 // Shutdown all receivers started at runtime.
@@ -66,83 +77,232 @@ func (obs *observerHandler) OnAdd(added []observer.Endpoint) {
 	defer obs.Unlock()
 
 	for _, e := range added {
-		env, err := observer.EndpointToEnv(e)
+		for _, resolved := range obs.resolveReceivers(e) {
+			if rcvr, ok := obs.startReceiver(e, resolved); ok {
+				obs.receiversByEndpointID.Put(e.ID, rcvr)
+				recordReceiverStarted()
+			}
+		}
+	}
+}
+
+// resolveReceivers returns the effective config of the receivers to run for the
+// endpoint: the ones of the templates whose rule matches the endpoint and the ones
+// requested by its annotations. Receivers whose config can't be resolved are logged
+// and skipped.
+func (obs *observerHandler) resolveReceivers(e observer.Endpoint) []resolvedReceiver {
+	env, err := observer.EndpointToEnv(e)
+	if err != nil {
+		obs.logger.Error("unable to convert endpoint to environment map", zap.String("endpoint", string(e.ID)), zap.Error(err))
+		return nil
+	}
+
+	var receivers []resolvedReceiver
+
+	for _, template := range obs.receiverTemplates {
+		if matches, err := template.rule.eval(env); err != nil {
+			obs.logger.Error("failed matching rule", zap.String("rule", template.Rule), zap.Error(err))
+			continue
+		} else if !matches {
+			continue
+		}
+
+		resolved, err := obs.resolveReceiver(template.receiverConfig, e, env)
 		if err != nil {
-			obs.logger.Error("unable to convert endpoint to environment map", zap.String("endpoint", string(e.ID)), zap.Error(err))
+			obs.logger.Error("failed to resolve receiver config", zap.String("receiver", template.fullName), zap.Error(err))
 			continue
 		}
+		receivers = append(receivers, resolved)
+	}
 
-		for _, template := range obs.receiverTemplates {
-			if matches, err := template.rule.eval(env); err != nil {
-				obs.logger.Error("failed matching rule", zap.String("rule", template.Rule), zap.Error(err))
-				continue
-			} else if !matches {
-				continue
-			}
+	if !obs.annotationDiscovery.Enabled {
+		return receivers
+	}
 
-			obs.logger.Info("starting receiver",
-				zap.String("name", template.fullName),
-				zap.String("type", string(template.typeStr)),
-				zap.String("endpoint", e.Target),
-				zap.String("endpoint_id", string(e.ID)))
+	annotated, err := obs.annotationDiscovery.receiversFromAnnotations(e)
+	if err != nil {
+		obs.logger.Warn("ignoring invalid annotation", zap.String("endpoint_id", string(e.ID)), zap.Error(err))
+		recordInvalidAnnotation()
+	}
 
-			resolvedConfig, err := expandMap(template.config, env)
-			if err != nil {
-				obs.logger.Error("unable to resolve template config", zap.String("receiver", template.fullName), zap.Error(err))
-				continue
-			}
+	for _, rcvrCfg := range annotated {
+		resolved, err := obs.resolveReceiver(rcvrCfg, e, env)
+		if err != nil {
+			obs.logger.Warn("failed to resolve receiver config from annotation",
+				zap.String("receiver", rcvrCfg.fullName), zap.String("endpoint_id", string(e.ID)), zap.Error(err))
+			recordInvalidAnnotation()
+			continue
+		}
+		resolved.fromAnnotation = true
+		receivers = append(receivers, resolved)
+	}
 
-			discoveredConfig := userConfigMap{}
+	return receivers
+}
 
-			// If user didn't set endpoint set to default value.
-			if _, ok := resolvedConfig[endpointConfigKey]; !ok {
-				discoveredConfig[endpointConfigKey] = e.Target
-			}
+// resolveReceiver expands the given receiver config for the endpoint.
+func (obs *observerHandler) resolveReceiver(rcvrCfg receiverConfig, e observer.Endpoint, env observer.EndpointEnv) (resolvedReceiver, error) {
+	resolvedConfig, err := expandMap(rcvrCfg.config, env)
+	if err != nil {
+		return resolvedReceiver{}, fmt.Errorf("unable to resolve template config: %v", err)
+	}
 
-			resolvedDiscoveredConfig, err := expandMap(discoveredConfig, env)
+	discoveredConfig := userConfigMap{}
 
-			if err != nil {
-				obs.logger.Error("unable to resolve discovered config", zap.String("receiver", template.fullName), zap.Error(err))
-				continue
-			}
+	// If user didn't set endpoint set to default value.
+	if _, ok := resolvedConfig[endpointConfigKey]; !ok {
+		discoveredConfig[endpointConfigKey] = e.Target
+	}
 
-			rcvr, err := obs.runner.start(receiverConfig{
-				fullName: template.fullName,
-				typeStr:  template.typeStr,
-				config:   resolvedConfig,
-			}, resolvedDiscoveredConfig)
+	resolvedDiscoveredConfig, err := expandMap(discoveredConfig, env)
+	if err != nil {
+		return resolvedReceiver{}, fmt.Errorf("unable to resolve discovered config: %v", err)
+	}
 
-			if err != nil {
-				obs.logger.Error("failed to start receiver", zap.String("receiver", template.fullName))
-				continue
-			}
+	return resolvedReceiver{
+		receiverConfig: receiverConfig{
+			fullName: rcvrCfg.fullName,
+			typeStr:  rcvrCfg.typeStr,
+			config:   resolvedConfig,
+		},
+		discoveredConfig: resolvedDiscoveredConfig,
+		resourceAttrs:    obs.resolveResourceAttributes(e, env),
+	}, nil
+}
 
-			obs.receiversByEndpointID.Put(e.ID, rcvr)
+// startReceiver starts a receiver with the given effective config. Failures are
+// logged and reported by the returned bool.
+func (obs *observerHandler) startReceiver(e observer.Endpoint, resolved resolvedReceiver) (runningReceiver, bool) {
+	obs.logger.Info("starting receiver",
+		zap.String("name", resolved.fullName),
+		zap.String("type", string(resolved.typeStr)),
+		zap.String("endpoint", e.Target),
+		zap.String("endpoint_id", string(e.ID)))
+
+	rcvr, err := obs.runner.start(resolved.receiverConfig, resolved.discoveredConfig, &enhancingConsumer{
+		metrics: obs.nextMetricsConsumer,
+		traces:  obs.nextTracesConsumer,
+		logs:    obs.nextLogsConsumer,
+		attrs:   resolved.resourceAttrs,
+	})
+	if err != nil {
+		obs.logger.Error("failed to start receiver",
+			zap.String("receiver", resolved.fullName), zap.String("endpoint_id", string(e.ID)), zap.Error(err))
+		recordReceiverStartFailed()
+		if resolved.fromAnnotation {
+			recordInvalidAnnotation()
 		}
+		return runningReceiver{}, false
 	}
+
+	return runningReceiver{resolvedReceiver: resolved, receiver: rcvr}, true
+}
+
+// stopReceiver stops a running receiver, logging failures.
+func (obs *observerHandler) stopReceiver(e observer.Endpoint, rcvr runningReceiver) {
+	obs.logger.Info("stopping receiver", zap.String("receiver", rcvr.fullName), zap.String("endpoint_id", string(e.ID)))
+
+	if err := obs.runner.shutdown(rcvr.receiver); err != nil {
+		obs.logger.Error("failed to stop receiver", zap.String("receiver", rcvr.fullName), zap.Error(err))
+	}
+}
+
+// resolveResourceAttributes evaluates the resource attributes of the endpoint type.
+// Attributes whose value can't be evaluated for the endpoint, like the pod name of a
+// port discovered on a host, or is empty are not set.
+func (obs *observerHandler) resolveResourceAttributes(e observer.Endpoint, env observer.EndpointEnv) map[string]string {
+	attrs := map[string]string{}
+	for attr, template := range obs.resourceAttributes[endpointType(env)] {
+		value, err := evalBackticksInConfigValue(template, env)
+		if err != nil {
+			obs.logger.Debug("unable to resolve resource attribute",
+				zap.String("attribute", attr), zap.String("endpoint_id", string(e.ID)), zap.Error(err))
+			continue
+		}
+		if value == nil {
+			continue
+		}
+		if str := fmt.Sprintf("%v", value); str != "" {
+			attrs[attr] = str
+		}
+	}
+	return attrs
+}
+
+// endpointType returns the type of the endpoint the environment was created for,
+// e.g. "pod" or "port".
+func endpointType(env observer.EndpointEnv) string {
+	types, _ := env["type"].(map[string]interface{})
+	for typeStr, matches := range types {
+		if matches == true {
+			return typeStr
+		}
+	}
+	return ""
 }
 
-// OnRemove responds to endpoint removal notifications.
 func (obs *observerHandler) OnRemove(removed []observer.Endpoint) {
 	obs.Lock()
 	defer obs.Unlock()
 
 	for _, e := range removed {
 		for _, rcvr := range obs.receiversByEndpointID.Get(e.ID) {
-			obs.logger.Info("stopping receiver", zap.Reflect("receiver", rcvr), zap.String("endpoint_id", string(e.ID)))
-
-			if err := obs.runner.shutdown(rcvr); err != nil {
-				obs.logger.Error("failed to stop receiver", zap.Reflect("receiver", rcvr))
-				continue
-			}
+			obs.stopReceiver(e, rcvr)
+			recordReceiverStopped()
 		}
 		obs.receiversByEndpointID.RemoveAll(e.ID)
 	}
 }
 
-// OnChange responds to endpoint change notifications.
+// OnChange responds to endpoint change notifications. The receivers of the changed
+// endpoints are re-evaluated and only the ones whose effective config changed are
+// restarted. Receivers that no longer apply to an endpoint are stopped and new ones
+// started.
 func (obs *observerHandler) OnChange(changed []observer.Endpoint) {
-	// TODO: optimize to only restart if effective config has changed.
-	obs.OnRemove(changed)
-	obs.OnAdd(changed)
+	obs.Lock()
+	defer obs.Unlock()
+
+	for _, e := range changed {
+		desired := map[string]resolvedReceiver{}
+		for _, resolved := range obs.resolveReceivers(e) {
+			desired[resolved.key()] = resolved
+		}
+
+		var running []runningReceiver
+
+		for _, rcvr := range obs.receiversByEndpointID.Get(e.ID) {
+			resolved, ok := desired[rcvr.key()]
+			if !ok {
+				obs.stopReceiver(e, rcvr)
+				recordReceiverStopped()
+				continue
+			}
+			delete(desired, rcvr.key())
+
+			if reflect.DeepEqual(resolved, rcvr.resolvedReceiver) {
+				running = append(running, rcvr)
+				continue
+			}
+
+			obs.logger.Info("effective config changed, restarting receiver",
+				zap.String("receiver", rcvr.fullName), zap.String("endpoint_id", string(e.ID)))
+			obs.stopReceiver(e, rcvr)
+			if restarted, ok := obs.startReceiver(e, resolved); ok {
+				running = append(running, restarted)
+				recordReceiverRestarted()
+			}
+		}
+
+		for _, resolved := range desired {
+			if rcvr, ok := obs.startReceiver(e, resolved); ok {
+				running = append(running, rcvr)
+				recordReceiverStarted()
+			}
+		}
+
+		obs.receiversByEndpointID.RemoveAll(e.ID)
+		for _, rcvr := range running {
+			obs.receiversByEndpointID.Put(e.ID, rcvr)
+		}
+	}
 }
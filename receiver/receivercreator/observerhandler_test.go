@@ -19,9 +19,12 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/exporter/exportertest"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
@@ -31,8 +34,8 @@ type mockRunner struct {
 	mock.Mock
 }
 
-func (run *mockRunner) start(receiver receiverConfig, discoveredConfig userConfigMap) (component.Receiver, error) {
-	args := run.Called(receiver, discoveredConfig)
+func (run *mockRunner) start(receiver receiverConfig, discoveredConfig userConfigMap, nextConsumer *enhancingConsumer) (component.Receiver, error) {
+	args := run.Called(receiver, discoveredConfig, nextConsumer)
 	return args.Get(0).(component.Receiver), args.Error(1)
 }
 
@@ -55,7 +58,7 @@ func TestOnAdd(t *testing.T) {
 		runner:                runner,
 	}
 
-	runner.On("start", rcvrCfg, userConfigMap{endpointConfigKey: "localhost:1234"}).Return(&componenttest.ExampleReceiverProducer{}, nil)
+	runner.On("start", rcvrCfg, userConfigMap{endpointConfigKey: "localhost:1234"}, mock.Anything).Return(&componenttest.ExampleReceiverProducer{}, nil)
 
 	handler.OnAdd([]observer.Endpoint{
 		portEndpoint,
@@ -75,7 +78,7 @@ func TestOnRemove(t *testing.T) {
 		runner:                runner,
 	}
 
-	handler.receiversByEndpointID.Put("port-1", rcvr)
+	handler.receiversByEndpointID.Put("port-1", runningReceiver{receiver: rcvr})
 
 	runner.On("shutdown", rcvr).Return(nil)
 
@@ -85,30 +88,133 @@ func TestOnRemove(t *testing.T) {
 	assert.Equal(t, 0, handler.receiversByEndpointID.Size())
 }
 
+// namedReceiver is a receiver that the mocks can tell apart from other receivers.
+type namedReceiver struct {
+	componenttest.ExampleReceiverProducer
+	name string
+}
+
+func viewSum(t *testing.T, v *view.View) float64 {
+	rows, err := view.RetrieveData(v.Name)
+	require.NoError(t, err)
+	if len(rows) == 0 {
+		return 0
+	}
+	return rows[0].Data.(*view.SumData).Value
+}
+
 func TestOnChange(t *testing.T) {
+	unchangedCfg := receiverConfig{typeStr: configmodels.Type("unchanged"), config: userConfigMap{"foo": "bar"}, fullName: "unchanged/1"}
+	changedCfg := receiverConfig{typeStr: configmodels.Type("changed"), config: userConfigMap{"app": "`pod.labels[\"app\"]`"}, fullName: "changed/1"}
+	removedCfg := receiverConfig{typeStr: configmodels.Type("removed"), config: userConfigMap{}, fullName: "removed/1"}
+	addedCfg := receiverConfig{typeStr: configmodels.Type("added"), config: userConfigMap{}, fullName: "added/1"}
+
+	runner := &mockRunner{}
+	handler := &observerHandler{
+		logger: zap.NewNop(),
+		receiverTemplates: map[string]receiverTemplate{
+			"unchanged/1": {unchangedCfg, "", newRuleOrPanic(`type.port`)},
+			"changed/1":   {changedCfg, "", newRuleOrPanic(`type.port`)},
+			"removed/1":   {removedCfg, "", newRuleOrPanic(`type.port && pod.labels["app"] == "redis"`)},
+			"added/1":     {addedCfg, "", newRuleOrPanic(`type.port && pod.labels["app"] == "redis-cluster"`)},
+		},
+		resourceAttributes:    defaultResourceAttributes(),
+		receiversByEndpointID: receiverMap{},
+		runner:                runner,
+	}
+
+	rcvrs := map[string]*namedReceiver{}
+	for _, name := range []string{"unchanged/1", "changed/1", "removed/1"} {
+		name := name
+		rcvr := &namedReceiver{name: name}
+		rcvrs[name] = rcvr
+		runner.On("start", mock.MatchedBy(func(cfg receiverConfig) bool { return cfg.fullName == name }), mock.Anything, mock.Anything).
+			Return(rcvr, nil).Once()
+	}
+	handler.OnAdd([]observer.Endpoint{portEndpoint})
+	runner.AssertExpectations(t)
+	assert.Equal(t, 3, handler.receiversByEndpointID.Size())
+
+	changedPod := pod
+	changedPod.Labels = map[string]string{"app": "redis-cluster"}
+	changedEndpoint := portEndpoint
+	changedEndpoint.Details = observer.Port{
+		Name:      "http",
+		Pod:       changedPod,
+		Port:      1234,
+		Transport: observer.ProtocolTCP,
+	}
+
+	restarted := &namedReceiver{name: "changed/1 restarted"}
+	added := &namedReceiver{name: "added/1"}
+	runner.On("shutdown", rcvrs["changed/1"]).Return(nil).Once()
+	runner.On("shutdown", rcvrs["removed/1"]).Return(nil).Once()
+	runner.On("start", receiverConfig{
+		fullName: "changed/1",
+		typeStr:  "changed",
+		config:   userConfigMap{"app": "redis-cluster"},
+	}, userConfigMap{endpointConfigKey: "localhost:1234"}, mock.Anything).Return(restarted, nil).Once()
+	runner.On("start", receiverConfig{
+		fullName: "added/1",
+		typeStr:  "added",
+		config:   map[string]interface{}{},
+	}, userConfigMap{endpointConfigKey: "localhost:1234"}, mock.Anything).Return(added, nil).Once()
+
+	started, stopped, restartedCount := viewSum(t, viewReceiversStarted), viewSum(t, viewReceiversStopped), viewSum(t, viewReceiversRestarted)
+	handler.OnChange([]observer.Endpoint{changedEndpoint})
+
+	assert.Equal(t, started+1, viewSum(t, viewReceiversStarted))
+	assert.Equal(t, stopped+1, viewSum(t, viewReceiversStopped))
+	assert.Equal(t, restartedCount+1, viewSum(t, viewReceiversRestarted))
+
+	runner.AssertExpectations(t)
+	// The receiver whose effective config did not change is neither stopped nor restarted.
+	runner.AssertNotCalled(t, "shutdown", rcvrs["unchanged/1"])
+	assert.ElementsMatch(t,
+		[]component.Receiver{rcvrs["unchanged/1"], restarted, added},
+		handler.receiversByEndpointID.Values())
+}
+
+func TestOnChangeNoChange(t *testing.T) {
 	runner := &mockRunner{}
 	rcvrCfg := receiverConfig{typeStr: configmodels.Type("name"), config: userConfigMap{"foo": "bar"}, fullName: "name/1"}
-	oldRcvr := &componenttest.ExampleReceiverProducer{}
-	newRcvr := &componenttest.ExampleReceiverProducer{}
+	rcvr := &componenttest.ExampleReceiverProducer{}
 	handler := &observerHandler{
 		logger: zap.NewNop(),
 		receiverTemplates: map[string]receiverTemplate{
 			"name/1": {rcvrCfg, "", newRuleOrPanic(`type.port`)},
 		},
+		resourceAttributes:    defaultResourceAttributes(),
 		receiversByEndpointID: receiverMap{},
 		runner:                runner,
 	}
 
-	handler.receiversByEndpointID.Put("port-1", oldRcvr)
+	runner.On("start", rcvrCfg, userConfigMap{endpointConfigKey: "localhost:1234"}, mock.Anything).Return(rcvr, nil).Once()
+	handler.OnAdd([]observer.Endpoint{portEndpoint})
 
-	runner.On("shutdown", oldRcvr).Return(nil)
-	runner.On("start", rcvrCfg, userConfigMap{endpointConfigKey: "localhost:1234"}).Return(newRcvr, nil)
+	// Annotations not used by the config or resource attributes don't restart the receiver.
+	changedPod := pod
+	changedPod.Annotations = map[string]string{"unrelated": "value"}
+	changedEndpoint := portEndpoint
+	changedEndpoint.Details = observer.Port{Name: "http", Pod: changedPod, Port: 1234, Transport: observer.ProtocolTCP}
+	handler.OnChange([]observer.Endpoint{changedEndpoint})
 
-	handler.OnChange([]observer.Endpoint{portEndpoint})
+	runner.AssertExpectations(t)
+	runner.AssertNotCalled(t, "shutdown", mock.Anything)
+	assert.Equal(t, []component.Receiver{rcvr}, handler.receiversByEndpointID.Values())
+
+	// The resource attributes are part of the effective config.
+	restarted := &componenttest.ExampleReceiverProducer{}
+	changedPod.Name = "pod-2"
+	changedEndpoint.Details = observer.Port{Name: "http", Pod: changedPod, Port: 1234, Transport: observer.ProtocolTCP}
+	runner.On("shutdown", rcvr).Return(nil).Once()
+	runner.On("start", rcvrCfg, userConfigMap{endpointConfigKey: "localhost:1234"}, &enhancingConsumer{
+		attrs: map[string]string{"k8s.pod.name": "pod-2"},
+	}).Return(restarted, nil).Once()
+	handler.OnChange([]observer.Endpoint{changedEndpoint})
 
 	runner.AssertExpectations(t)
-	assert.Equal(t, 1, handler.receiversByEndpointID.Size())
-	assert.Same(t, newRcvr, handler.receiversByEndpointID.Get("port-1")[0])
+	assert.Equal(t, []component.Receiver{restarted}, handler.receiversByEndpointID.Values())
 }
 
 func TestDynamicConfig(t *testing.T) {
@@ -129,10 +235,203 @@ func TestDynamicConfig(t *testing.T) {
 		fullName: "name/1",
 		typeStr:  "name",
 		config:   userConfigMap{endpointConfigKey: "localhost:6379"},
-	}, userConfigMap{}).Return(&componenttest.ExampleReceiverProducer{}, nil)
+	}, userConfigMap{}, mock.Anything).Return(&componenttest.ExampleReceiverProducer{}, nil)
 	handler.OnAdd([]observer.Endpoint{
 		podEndpoint,
 	})
 
 	runner.AssertExpectations(t)
 }
+
+func TestAnnotationDiscovery(t *testing.T) {
+	runner := &mockRunner{}
+	handler := &observerHandler{
+		logger:                zap.NewNop(),
+		receiversByEndpointID: receiverMap{},
+		runner:                runner,
+		annotationDiscovery: AnnotationDiscoveryConfig{
+			Enabled:          true,
+			AllowedReceivers: []configmodels.Type{"redis"},
+			Defaults: map[string]map[string]interface{}{
+				"redis": {"password": "secret"},
+			},
+		},
+	}
+
+	annotated := podWithAnnotations(map[string]string{
+		receiverConfigAnnotation: `{"type":"redis","endpoint":"` + "`endpoint`" + `:6379","collection_interval":"10s"}`,
+	})
+	invalid := podWithAnnotations(map[string]string{receiverConfigAnnotation: `{"type":"mysql"}`})
+	invalid.ID = "pod-2"
+
+	runner.On("start", receiverConfig{
+		fullName: "redis/annotation",
+		typeStr:  "redis",
+		config: userConfigMap{
+			"endpoint":            "10.0.0.2:6379",
+			"collection_interval": "10s",
+			"password":            "secret",
+		},
+	}, userConfigMap{}, mock.Anything).Return(&componenttest.ExampleReceiverProducer{}, nil)
+
+	handler.OnAdd([]observer.Endpoint{annotated, invalid, podEndpoint})
+
+	runner.AssertExpectations(t)
+	assert.Equal(t, 1, handler.receiversByEndpointID.Size())
+	assert.Len(t, handler.receiversByEndpointID.Get("pod-1"), 1)
+}
+
+func TestAnnotationDiscoveryNameCollision(t *testing.T) {
+	runner := &mockRunner{}
+	handler := &observerHandler{
+		logger:                zap.NewNop(),
+		receiversByEndpointID: receiverMap{},
+		runner:                runner,
+		receiverTemplates: map[string]receiverTemplate{
+			"prometheus_simple/annotation": {
+				receiverConfig: receiverConfig{typeStr: prometheusSimpleType, config: userConfigMap{"endpoint": "`endpoint`:1234"}, fullName: "prometheus_simple/annotation"},
+				rule:           newRuleOrPanic("type.pod"),
+			},
+		},
+		annotationDiscovery: AnnotationDiscoveryConfig{
+			Enabled:          true,
+			AllowedReceivers: []configmodels.Type{prometheusSimpleType},
+		},
+	}
+
+	// The template, the receiver config annotation and the prometheus annotations all
+	// request a prometheus_simple receiver for the same endpoint.
+	annotated := podWithAnnotations(map[string]string{
+		receiverConfigAnnotation:   `{"type":"prometheus_simple","endpoint":"` + "`endpoint`" + `:8080"}`,
+		prometheusScrapeAnnotation: "true",
+	})
+
+	rcvrs := map[string]*namedReceiver{}
+	for name, endpoint := range map[string]string{
+		"template":              "10.0.0.2:1234",
+		"annotation":            "10.0.0.2:8080",
+		"prometheus_annotation": "10.0.0.2:9090",
+	} {
+		endpoint := endpoint
+		rcvr := &namedReceiver{name: name}
+		rcvrs[name] = rcvr
+		runner.On("start", mock.MatchedBy(func(cfg receiverConfig) bool { return cfg.config[endpointConfigKey] == endpoint }), mock.Anything, mock.Anything).
+			Return(rcvr, nil).Once()
+	}
+
+	handler.OnAdd([]observer.Endpoint{annotated})
+
+	runner.AssertExpectations(t)
+	assert.ElementsMatch(t,
+		[]component.Receiver{rcvrs["template"], rcvrs["annotation"], rcvrs["prometheus_annotation"]},
+		handler.receiversByEndpointID.Values())
+
+	// None of the receivers replaces another when the endpoint changes.
+	handler.OnChange([]observer.Endpoint{annotated})
+
+	runner.AssertExpectations(t)
+	runner.AssertNotCalled(t, "shutdown", mock.Anything)
+	assert.ElementsMatch(t,
+		[]component.Receiver{rcvrs["template"], rcvrs["annotation"], rcvrs["prometheus_annotation"]},
+		handler.receiversByEndpointID.Values())
+}
+
+func TestAnnotationDiscoveryDisabled(t *testing.T) {
+	runner := &mockRunner{}
+	handler := &observerHandler{
+		logger:                zap.NewNop(),
+		receiversByEndpointID: receiverMap{},
+		runner:                runner,
+		annotationDiscovery:   AnnotationDiscoveryConfig{AllowedReceivers: []configmodels.Type{"redis"}},
+	}
+
+	handler.OnAdd([]observer.Endpoint{podWithAnnotations(map[string]string{receiverConfigAnnotation: `{"type":"redis"}`})})
+
+	runner.AssertExpectations(t)
+	assert.Equal(t, 0, handler.receiversByEndpointID.Size())
+}
+
+func TestResolveResourceAttributes(t *testing.T) {
+	handler := &observerHandler{
+		logger:             zap.NewNop(),
+		resourceAttributes: defaultResourceAttributes(),
+	}
+	handler.resourceAttributes["pod"]["app"] = "`labels[\"app\"]`"
+	handler.resourceAttributes["pod"]["missing"] = "`labels[\"missing\"]`"
+
+	tests := []struct {
+		name     string
+		endpoint observer.Endpoint
+		want     map[string]string
+	}{
+		{
+			name:     "pod",
+			endpoint: podEndpoint,
+			want:     map[string]string{"k8s.pod.name": "pod-1", "app": "redis"},
+		},
+		{
+			name:     "port",
+			endpoint: portEndpoint,
+			want:     map[string]string{"k8s.pod.name": "pod-1"},
+		},
+		{
+			name:     "container",
+			endpoint: containerEndpoint,
+			want: map[string]string{
+				"container.name":       "redis",
+				"container.image.name": "redis:6",
+				"container.id":         "container-1",
+			},
+		},
+		{
+			name:     "k8s_node",
+			endpoint: nodeEndpoint,
+			want:     map[string]string{"k8s.node.name": "node-1"},
+		},
+		{
+			name: "host port without pod",
+			endpoint: observer.Endpoint{
+				ID:      "port-2",
+				Target:  "localhost:6379",
+				Details: observer.HostPort{Name: "redis-server", Port: 6379},
+			},
+			want: map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, err := observer.EndpointToEnv(tt.endpoint)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, handler.resolveResourceAttributes(tt.endpoint, env))
+		})
+	}
+}
+
+func TestOnAddNextConsumers(t *testing.T) {
+	runner := &mockRunner{}
+	rcvrCfg := receiverConfig{typeStr: configmodels.Type("name"), config: userConfigMap{"foo": "bar"}, fullName: "name/1"}
+	metricsConsumer := &mockMetricsConsumer{}
+	logsConsumer := &exportertest.SinkLogsExporter{}
+	handler := &observerHandler{
+		logger: zap.NewNop(),
+		receiverTemplates: map[string]receiverTemplate{
+			"name/1": {rcvrCfg, "", newRuleOrPanic(`type.port`)},
+		},
+		resourceAttributes:    defaultResourceAttributes(),
+		receiversByEndpointID: receiverMap{},
+		runner:                runner,
+		nextMetricsConsumer:   metricsConsumer,
+		nextLogsConsumer:      logsConsumer,
+	}
+
+	runner.On("start", rcvrCfg, userConfigMap{endpointConfigKey: "localhost:1234"}, &enhancingConsumer{
+		metrics: metricsConsumer,
+		logs:    logsConsumer,
+		attrs:   map[string]string{"k8s.pod.name": "pod-1"},
+	}).Return(&componenttest.ExampleReceiverProducer{}, nil)
+
+	handler.OnAdd([]observer.Endpoint{portEndpoint})
+
+	runner.AssertExpectations(t)
+	assert.Equal(t, 1, handler.receiversByEndpointID.Size())
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configmodels"
@@ -32,27 +33,30 @@ var (
 )
 
 var _ component.MetricsReceiver = (*receiverCreator)(nil)
+var _ component.TraceReceiver = (*receiverCreator)(nil)
+var _ component.LogsReceiver = (*receiverCreator)(nil)
 
-// receiverCreator implements consumer.MetricsConsumer.
+// receiverCreator starts receivers for the discovered endpoints, passing their data
+// to the next consumers of the pipelines it is part of. A single instance is shared
+// by all these pipelines.
 type receiverCreator struct {
-	nextConsumer    consumer.MetricsConsumer
-	logger          *zap.Logger
-	cfg             *Config
-	observerHandler observerHandler
+	nextMetricsConsumer consumer.MetricsConsumer
+	nextTracesConsumer  consumer.TraceConsumer
+	nextLogsConsumer    consumer.LogsConsumer
+	logger              *zap.Logger
+	cfg                 *Config
+	observerHandler     observerHandler
+	startOnce           sync.Once
+	shutdownOnce        sync.Once
 }
 
-// newReceiverCreator creates the receiver_creator with the given parameters.
-func newReceiverCreator(logger *zap.Logger, nextConsumer consumer.MetricsConsumer, cfg *Config) (component.MetricsReceiver, error) {
-	if nextConsumer == nil {
-		return nil, errNilNextConsumer
+// newReceiverCreator creates the receiver_creator with the given parameters. The
+// next consumers are set when the receiver_creator is created for a pipeline.
+func newReceiverCreator(logger *zap.Logger, cfg *Config) *receiverCreator {
+	return &receiverCreator{
+		logger: logger,
+		cfg:    cfg,
 	}
-
-	r := &receiverCreator{
-		logger:       logger,
-		nextConsumer: nextConsumer,
-		cfg:          cfg,
-	}
-	return r, nil
 }
 
 // loggingHost provides a safer version of host that logs errors instead of exiting the process.
@@ -68,16 +72,28 @@ func (h *loggingHost) ReportFatalError(err error) {
 
 var _ component.Host = (*loggingHost)(nil)
 
-// Start receiver_creator.
+// Start receiver_creator. It is only started once even when part of several pipelines.
 func (rc *receiverCreator) Start(ctx context.Context, host component.Host) error {
+	var err error
+	rc.startOnce.Do(func() {
+		err = rc.start(host)
+	})
+	return err
+}
+
+func (rc *receiverCreator) start(host component.Host) error {
 	rc.observerHandler = observerHandler{
 		logger:                rc.logger,
 		receiverTemplates:     rc.cfg.receiverTemplates,
+		annotationDiscovery:   rc.cfg.AnnotationDiscovery,
+		resourceAttributes:    rc.cfg.resourceAttributes,
+		nextMetricsConsumer:   rc.nextMetricsConsumer,
+		nextTracesConsumer:    rc.nextTracesConsumer,
+		nextLogsConsumer:      rc.nextLogsConsumer,
 		receiversByEndpointID: receiverMap{},
 		runner: &receiverRunner{
-			logger:       rc.logger,
-			nextConsumer: rc.nextConsumer,
-			idNamespace:  rc.cfg.Name(),
+			logger:      rc.logger,
+			idNamespace: rc.cfg.Name(),
 			// TODO: not really sure what context should be used here for starting subreceivers
 			// as don't think it makes sense to use Start context as the lifetimes are different.
 			ctx:  context.Background(),
@@ -123,5 +139,9 @@ func (rc *receiverCreator) Start(ctx context.Context, host component.Host) error
 
 // Shutdown stops the receiver_creator and all its receivers started at runtime.
 func (rc *receiverCreator) Shutdown(ctx context.Context) error {
-	return rc.observerHandler.Shutdown()
+	var err error
+	rc.shutdownOnce.Do(func() {
+		err = rc.observerHandler.Shutdown()
+	})
+	return err
 }
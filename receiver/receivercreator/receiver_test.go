@@ -144,6 +144,14 @@ func TestMockedEndToEnd(t *testing.T) {
 
 	// TODO: Will have to rework once receivers are started asynchronously to Start().
 	assert.Len(t, mockConsumer.Metrics, 1)
+	// The endpoint resource attributes are added without overriding the ones of the receiver.
+	attrs := mockConsumer.Metrics[0].ResourceMetrics().At(0).Resource().Attributes()
+	podName, ok := attrs.Get("k8s.pod.name")
+	assert.True(t, ok)
+	assert.Equal(t, "pod-1", podName.StringVal())
+	serviceName, ok := attrs.Get("service.name")
+	assert.True(t, ok)
+	assert.Equal(t, "dynamictest", serviceName.StringVal())
 
 	shutdown()
 
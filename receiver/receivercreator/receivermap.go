@@ -20,17 +20,45 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
 )
 
+// resolvedReceiver is the effective config of a receiver for an endpoint. A receiver
+// only needs to be restarted when an endpoint changes if its effective config changed.
+type resolvedReceiver struct {
+	// receiverConfig is the receiver config with its dynamic values expanded.
+	receiverConfig
+	// discoveredConfig is the config discovered from the endpoint.
+	discoveredConfig userConfigMap
+	// resourceAttrs are the resource attributes added to the data of the receiver.
+	resourceAttrs map[string]string
+	// fromAnnotation is whether the receiver was requested by an endpoint annotation.
+	fromAnnotation bool
+}
+
+// key identifies the receiver among the receivers of an endpoint. A template may
+// have the same name as a receiver requested by an annotation.
+func (r resolvedReceiver) key() string {
+	if r.fromAnnotation {
+		return "annotation:" + r.fullName
+	}
+	return "template:" + r.fullName
+}
+
+// runningReceiver is a receiver started for an endpoint.
+type runningReceiver struct {
+	resolvedReceiver
+	receiver component.Receiver
+}
+
 // receiverMap is a multimap for mapping one id to many receivers. It does
 // not deduplicate the same value being associated with the same key.
-type receiverMap map[observer.EndpointID][]component.Receiver
+type receiverMap map[observer.EndpointID][]runningReceiver
 
 // Put rcvr into key id. If rcvr is a duplicate it will still be added.
-func (rm receiverMap) Put(id observer.EndpointID, rcvr component.Receiver) {
+func (rm receiverMap) Put(id observer.EndpointID, rcvr runningReceiver) {
 	rm[id] = append(rm[id], rcvr)
 }
 
 // Get receivers by id.
-func (rm receiverMap) Get(id observer.EndpointID) []component.Receiver {
+func (rm receiverMap) Get(id observer.EndpointID) []runningReceiver {
 	return rm[id]
 }
 
@@ -42,7 +70,9 @@ func (rm receiverMap) RemoveAll(id observer.EndpointID) {
 // Get all receivers in the map.
 func (rm receiverMap) Values() (out []component.Receiver) {
 	for _, m := range rm {
-		out = append(out, m...)
+		for _, r := range m {
+			out = append(out, r.receiver)
+		}
 	}
 	return
 }
@@ -26,9 +26,11 @@ func TestReceiverMap(t *testing.T) {
 	rm := receiverMap{}
 	assert.Equal(t, 0, rm.Size())
 
-	r1 := &componenttest.ExampleReceiverProducer{}
-	r2 := &componenttest.ExampleReceiverProducer{}
-	r3 := &componenttest.ExampleReceiverProducer{}
+	rcvr1 := &componenttest.ExampleReceiverProducer{}
+	rcvr2 := &componenttest.ExampleReceiverProducer{}
+	r1 := runningReceiver{receiver: rcvr1}
+	r2 := runningReceiver{receiver: rcvr2}
+	r3 := runningReceiver{receiver: &componenttest.ExampleReceiverProducer{}}
 
 	rm.Put("a", r1)
 	assert.Equal(t, 1, rm.Size())
@@ -39,7 +41,7 @@ func TestReceiverMap(t *testing.T) {
 	rm.Put("b", r3)
 	assert.Equal(t, 3, rm.Size())
 
-	assert.Equal(t, []component.Receiver{r1, r2}, rm.Get("a"))
+	assert.Equal(t, []runningReceiver{r1, r2}, rm.Get("a"))
 	assert.Nil(t, rm.Get("missing"))
 
 	rm.RemoveAll("missing")
@@ -54,5 +56,5 @@ func TestReceiverMap(t *testing.T) {
 	rm.Put("a", r1)
 	rm.Put("b", r2)
 	assert.Equal(t, 2, rm.Size())
-	assert.Equal(t, []component.Receiver{r1, r2}, rm.Values())
+	assert.ElementsMatch(t, []component.Receiver{rcvr1, rcvr2}, rm.Values())
 }
@@ -30,7 +30,7 @@ type rule struct {
 }
 
 // ruleRe is used to verify the rule starts type check.
-var ruleRe = regexp.MustCompile(`^type\.(pod|port)`)
+var ruleRe = regexp.MustCompile(`^type\.(pod|port|container|k8s_node|k8s_service)`)
 
 // newRule creates a new rule instance.
 func newRule(ruleStr string) (rule, error) {
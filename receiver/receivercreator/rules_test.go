@@ -47,6 +47,8 @@ func Test_ruleEval(t *testing.T) {
 		{"basic port", args{`type.port && name == "http" && pod.labels["app"] == "redis"`, portEndpoint}, true, false},
 		{"basic pod", args{`type.pod && labels["region"] == "west-1"`, podEndpoint}, true, false},
 		{"annotations", args{`type.pod && annotations["scrape"] == "true"`, podEndpoint}, true, false},
+		{"basic node", args{`type.k8s_node && labels["zone"] == "us-east-1a" && kubelet_endpoint_port == 10250`, nodeEndpoint}, true, false},
+		{"basic container", args{`type.container && image matches "redis" && labels["env"] == "prod"`, containerEndpoint}, true, false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -19,33 +19,38 @@ import (
 	"fmt"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
 	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configerror"
 	"go.opentelemetry.io/collector/config/configmodels"
-	"go.opentelemetry.io/collector/consumer"
 	"go.uber.org/zap"
 )
 
 // runner starts and stops receiver instances.
 type runner interface {
-	// start a receiver instance from its static config and discovered config.
-	start(receiver receiverConfig, discoveredConfig userConfigMap) (component.Receiver, error)
+	// start a receiver instance from its static config and discovered config, passing
+	// its data to the given consumer.
+	start(receiver receiverConfig, discoveredConfig userConfigMap, nextConsumer *enhancingConsumer) (component.Receiver, error)
 	// shutdown a receiver.
 	shutdown(rcvr component.Receiver) error
 }
 
 // receiverRunner handles starting/stopping of a concrete subreceiver instance.
 type receiverRunner struct {
-	logger       *zap.Logger
-	nextConsumer consumer.MetricsConsumer
-	idNamespace  string
-	ctx          context.Context
-	host         component.Host
+	logger      *zap.Logger
+	idNamespace string
+	ctx         context.Context
+	host        component.Host
 }
 
 var _ runner = (*receiverRunner)(nil)
 
 // start a receiver instance from its static config and discovered config.
-func (run *receiverRunner) start(receiver receiverConfig, discoveredConfig userConfigMap) (component.Receiver, error) {
+func (run *receiverRunner) start(
+	receiver receiverConfig,
+	discoveredConfig userConfigMap,
+	nextConsumer *enhancingConsumer,
+) (component.Receiver, error) {
 	factory := run.host.GetFactory(component.KindReceiver, receiver.typeStr)
 
 	if factory == nil {
@@ -58,7 +63,7 @@ func (run *receiverRunner) start(receiver receiverConfig, discoveredConfig userC
 	if err != nil {
 		return nil, err
 	}
-	recvr, err := run.createRuntimeReceiver(receiverFactory, cfg)
+	recvr, err := run.createRuntimeReceiver(receiverFactory, cfg, nextConsumer)
 	if err != nil {
 		return nil, err
 	}
@@ -104,8 +109,85 @@ func (run *receiverRunner) loadRuntimeReceiverConfig(
 	return receiverConfig, nil
 }
 
-// createRuntimeReceiver creates a receiver that is discovered at runtime.
-func (run *receiverRunner) createRuntimeReceiver(factory component.ReceiverFactory, cfg configmodels.Receiver) (component.MetricsReceiver, error) {
+// createRuntimeReceiver creates a receiver that is discovered at runtime, for each of
+// the signals of the next consumer supported by the receiver factory.
+func (run *receiverRunner) createRuntimeReceiver(
+	factory component.ReceiverFactory,
+	cfg configmodels.Receiver,
+	nextConsumer *enhancingConsumer,
+) (component.Receiver, error) {
+	ctx := context.Background()
 	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
-	return factory.CreateMetricsReceiver(context.Background(), params, cfg, run.nextConsumer)
+
+	var receivers []component.Receiver
+	addReceiver := func(rcvr component.Receiver, err error) error {
+		if err == configerror.ErrDataTypeIsNotSupported {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		// Factories can return the same receiver for all signals.
+		for _, r := range receivers {
+			if r == rcvr {
+				return nil
+			}
+		}
+		receivers = append(receivers, rcvr)
+		return nil
+	}
+
+	if nextConsumer.metrics != nil {
+		if err := addReceiver(factory.CreateMetricsReceiver(ctx, params, cfg, nextConsumer)); err != nil {
+			return nil, err
+		}
+	}
+	if nextConsumer.traces != nil {
+		if err := addReceiver(factory.CreateTraceReceiver(ctx, params, cfg, nextConsumer)); err != nil {
+			return nil, err
+		}
+	}
+	if nextConsumer.logs != nil {
+		if err := addReceiver(factory.CreateLogsReceiver(ctx, params, cfg, nextConsumer)); err != nil {
+			return nil, err
+		}
+	}
+
+	switch len(receivers) {
+	case 0:
+		return nil, fmt.Errorf("receiver %s does not support the data types of the receiver_creator pipelines", cfg.Name())
+	case 1:
+		return receivers[0], nil
+	default:
+		return receiverGroup(receivers), nil
+	}
+}
+
+// receiverGroup is a receiver made of the receivers created for different signals
+// when the factory does not share a single instance between them.
+type receiverGroup []component.Receiver
+
+var _ component.Receiver = (receiverGroup)(nil)
+
+func (rg receiverGroup) Start(ctx context.Context, host component.Host) error {
+	for i, rcvr := range rg {
+		if err := rcvr.Start(ctx, host); err != nil {
+			// Stop the receivers already started.
+			for _, started := range rg[:i] {
+				_ = started.Shutdown(ctx)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (rg receiverGroup) Shutdown(ctx context.Context) error {
+	var errs []error
+	for _, rcvr := range rg {
+		if err := rcvr.Shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return componenterror.CombineErrors(errs)
 }
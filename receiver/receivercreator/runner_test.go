@@ -15,16 +15,18 @@
 package receivercreator
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/exporter/exportertest"
 	"go.uber.org/zap"
 )
 
 func Test_loadAndCreateRuntimeReceiver(t *testing.T) {
-	run := &receiverRunner{logger: zap.NewNop(), nextConsumer: &mockMetricsConsumer{}, idNamespace: "receiver_creator/1"}
+	run := &receiverRunner{logger: zap.NewNop(), idNamespace: "receiver_creator/1"}
 	exampleFactory := &componenttest.ExampleReceiverFactory{}
 	template, err := newReceiverTemplate("examplereceiver/1", nil)
 	require.NoError(t, err)
@@ -41,10 +43,63 @@ func Test_loadAndCreateRuntimeReceiver(t *testing.T) {
 
 	// Test that metric receiver can be created from loaded config.
 	t.Run("test create receiver from loaded config", func(t *testing.T) {
-		recvr, err := run.createRuntimeReceiver(exampleFactory, loadedConfig)
+		nextConsumer := &enhancingConsumer{metrics: &mockMetricsConsumer{}}
+		recvr, err := run.createRuntimeReceiver(exampleFactory, loadedConfig, nextConsumer)
 		require.NoError(t, err)
 		assert.NotNil(t, recvr)
 		exampleReceiver := recvr.(*componenttest.ExampleReceiverProducer)
-		assert.Equal(t, run.nextConsumer, exampleReceiver.MetricsConsumer)
+		assert.Same(t, nextConsumer, exampleReceiver.MetricsConsumer)
+		assert.Nil(t, exampleReceiver.TraceConsumer)
+		assert.Nil(t, exampleReceiver.LogConsumer)
 	})
 }
+
+func Test_createRuntimeReceiverAllSignals(t *testing.T) {
+	run := &receiverRunner{logger: zap.NewNop(), idNamespace: "receiver_creator/1"}
+	exampleFactory := &componenttest.ExampleReceiverFactory{}
+	nextConsumer := &enhancingConsumer{
+		metrics: &mockMetricsConsumer{},
+		traces:  &exportertest.SinkTraceExporter{},
+		logs:    &exportertest.SinkLogsExporter{},
+	}
+
+	recvr, err := run.createRuntimeReceiver(exampleFactory, exampleFactory.CreateDefaultConfig(), nextConsumer)
+	require.NoError(t, err)
+	// The example factory shares a single receiver between signals.
+	exampleReceiver := recvr.(*componenttest.ExampleReceiverProducer)
+	assert.Same(t, nextConsumer, exampleReceiver.MetricsConsumer)
+	assert.Same(t, nextConsumer, exampleReceiver.TraceConsumer)
+	assert.Same(t, nextConsumer, exampleReceiver.LogConsumer)
+}
+
+func Test_createRuntimeReceiverUnsupportedSignal(t *testing.T) {
+	run := &receiverRunner{logger: zap.NewNop(), idNamespace: "receiver_creator/1"}
+	exampleFactory := &componenttest.ExampleReceiverFactory{}
+	cfg := exampleFactory.CreateDefaultConfig().(*componenttest.ExampleReceiver)
+	cfg.FailTraceCreation = true
+
+	recvr, err := run.createRuntimeReceiver(exampleFactory, cfg, &enhancingConsumer{traces: &exportertest.SinkTraceExporter{}})
+	assert.EqualError(t, err, "receiver examplereceiver does not support the data types of the receiver_creator pipelines")
+	assert.Nil(t, recvr)
+
+	recvr, err = run.createRuntimeReceiver(exampleFactory, cfg, &enhancingConsumer{
+		traces:  &exportertest.SinkTraceExporter{},
+		metrics: &mockMetricsConsumer{},
+	})
+	require.NoError(t, err)
+	assert.IsType(t, &componenttest.ExampleReceiverProducer{}, recvr)
+}
+
+func TestReceiverGroup(t *testing.T) {
+	first := &componenttest.ExampleReceiverProducer{}
+	second := &componenttest.ExampleReceiverProducer{}
+	group := receiverGroup{first, second}
+
+	require.NoError(t, group.Start(context.Background(), componenttest.NewNopHost()))
+	assert.True(t, first.Started)
+	assert.True(t, second.Started)
+
+	require.NoError(t, group.Shutdown(context.Background()))
+	assert.True(t, first.Stopped)
+	assert.True(t, second.Stopped)
+}
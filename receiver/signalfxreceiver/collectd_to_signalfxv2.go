@@ -0,0 +1,162 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signalfxreceiver
+
+import (
+	"encoding/json"
+	"strings"
+
+	sfxpb "github.com/signalfx/com_signalfx_metrics_protobuf/model"
+)
+
+const (
+	collectdMetricDerive   = "derive"
+	collectdMetricCounter  = "counter"
+	collectdMetricGauge    = "gauge"
+	collectdMetricAbsolute = "absolute"
+)
+
+// collectdRecord is a single value list, or notification, as sent by the
+// collectd write_http plugin in JSON format.
+type collectdRecord struct {
+	Dsnames        []string       `json:"dsnames"`
+	Dstypes        []string       `json:"dstypes"`
+	Host           string         `json:"host"`
+	Interval       float64        `json:"interval"`
+	Plugin         string         `json:"plugin"`
+	PluginInstance string         `json:"plugin_instance"`
+	Time           float64        `json:"time"`
+	TypeS          string         `json:"type"`
+	TypeInstance   string         `json:"type_instance"`
+	Values         []*json.Number `json:"values"`
+	Message        *string        `json:"message"`
+	Severity       *string        `json:"severity"`
+}
+
+func (r *collectdRecord) isEvent() bool {
+	return r.Severity != nil && r.Message != nil
+}
+
+// collectdJSONToDatapoints converts a collectd write_http JSON message to
+// SignalFx proto data points. The same naming and dimension rules of the
+// SignalFx Gateway are used: the metric name is "type[.type_instance]", with
+// ".dsname" appended when a record has several values. Dimensions encoded as
+// "name[k=v,...]" in the plugin instance, host and type instance are extracted.
+// Notifications are ignored.
+func collectdJSONToDatapoints(body []byte, defaultDims map[string]string) ([]*sfxpb.DataPoint, error) {
+	var records []*collectdRecord
+	if err := unmarshalJSON(body, &records); err != nil {
+		return nil, err
+	}
+
+	var sfxDataPoints []*sfxpb.DataPoint
+	for _, record := range records {
+		if record == nil || record.isEvent() {
+			continue
+		}
+		for i := range record.Dsnames {
+			if i >= len(record.Dstypes) || i >= len(record.Values) || record.Values[i] == nil {
+				continue
+			}
+			sfxDataPoints = append(sfxDataPoints, record.toDatapoint(i, defaultDims))
+		}
+	}
+	return sfxDataPoints, nil
+}
+
+func (r *collectdRecord) toDatapoint(index int, defaultDims map[string]string) *sfxpb.DataPoint {
+	dims := make(map[string]string, len(defaultDims)+4)
+	for k, v := range defaultDims {
+		dims[k] = v
+	}
+
+	nameParts := make([]string, 0, 3)
+	if r.TypeS != "" {
+		nameParts = append(nameParts, r.TypeS)
+	}
+	if r.TypeInstance != "" {
+		if instanceName := addDimensionsFromName(dims, r.TypeInstance); instanceName != "" {
+			nameParts = append(nameParts, instanceName)
+		}
+	}
+	if len(r.Dsnames) > 1 && r.Dsnames[index] != "" {
+		nameParts = append(nameParts, r.Dsnames[index])
+	} else if r.Dsnames[index] != "" {
+		dims["dsname"] = r.Dsnames[index]
+	}
+
+	if r.Plugin != "" {
+		dims["plugin"] = r.Plugin
+	}
+	if pluginInstance := addDimensionsFromName(dims, r.PluginInstance); pluginInstance != "" {
+		dims["plugin_instance"] = pluginInstance
+	}
+	if host := addDimensionsFromName(dims, r.Host); host != "" {
+		dims["host"] = host
+	}
+
+	mt := sfxpb.MetricType_GAUGE
+	switch r.Dstypes[index] {
+	case collectdMetricCounter, collectdMetricDerive:
+		mt = sfxpb.MetricType_CUMULATIVE_COUNTER
+	case collectdMetricGauge, collectdMetricAbsolute:
+		mt = sfxpb.MetricType_GAUGE
+	}
+
+	dimensions := make([]*sfxpb.Dimension, 0, len(dims))
+	for k, v := range dims {
+		dimensions = append(dimensions, &sfxpb.Dimension{Key: k, Value: v})
+	}
+
+	return &sfxpb.DataPoint{
+		Metric:     strings.Join(nameParts, "."),
+		Timestamp:  int64(r.Time * 1e3),
+		Value:      jsonValueToDatum(*r.Values[index]),
+		MetricType: &mt,
+		Dimensions: dimensions,
+	}
+}
+
+// addDimensionsFromName extracts the dimensions of a name in the format
+// "name[k=v,f=x]-more_name", adding them to dims unless already present, and
+// returns the remaining name, "name-more_name" in the example. If the
+// dimensions are malformed the original name is returned.
+func addDimensionsFromName(dims map[string]string, name string) string {
+	start := strings.Index(name, "[")
+	if start == -1 {
+		return name
+	}
+	end := strings.Index(name[start:], "]")
+	if end == -1 {
+		return name
+	}
+	end += start
+
+	extracted := map[string]string{}
+	for _, pair := range strings.Split(name[start+1:end], ",") {
+		kv := strings.Split(pair, "=")
+		if len(kv) != 2 || kv[0] == "" {
+			return name
+		}
+		extracted[kv[0]] = kv[1]
+	}
+
+	for k, v := range extracted {
+		if _, exists := dims[k]; !exists && v != "" {
+			dims[k] = v
+		}
+	}
+	return name[:start] + name[end+1:]
+}
@@ -0,0 +1,146 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signalfxreceiver
+
+import (
+	"testing"
+
+	sfxpb "github.com/signalfx/com_signalfx_metrics_protobuf/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_collectdJSONToDatapoints(t *testing.T) {
+	body := []byte(`[{
+		"dsnames": ["value"],
+		"dstypes": ["derive"],
+		"host": "host1[cluster=c1]",
+		"interval": 10,
+		"plugin": "cpu",
+		"plugin_instance": "0",
+		"time": 1415062577.494,
+		"type": "cpu",
+		"type_instance": "idle",
+		"values": [100]
+	}, {
+		"dsnames": ["rx", "tx"],
+		"dstypes": ["counter", "gauge"],
+		"host": "host1",
+		"plugin": "interface",
+		"plugin_instance": "eth0[k=v]",
+		"time": 1415062577,
+		"type": "if_octets",
+		"values": [1, 2.5]
+	}, {
+		"severity": "failure",
+		"message": "a notification",
+		"host": "host1",
+		"time": 1415062577
+	}]`)
+
+	got, err := collectdJSONToDatapoints(body, map[string]string{"env": "prod", "cluster": "default"})
+	require.NoError(t, err)
+	sortDatapoints(got)
+
+	want := []*sfxpb.DataPoint{
+		{
+			Metric:     "cpu.idle",
+			Timestamp:  1415062577494,
+			Value:      sfxpb.Datum{IntValue: int64Ptr(100)},
+			MetricType: sfxTypePtr(sfxpb.MetricType_CUMULATIVE_COUNTER),
+			Dimensions: []*sfxpb.Dimension{
+				{Key: "cluster", Value: "default"},
+				{Key: "dsname", Value: "value"},
+				{Key: "env", Value: "prod"},
+				{Key: "host", Value: "host1"},
+				{Key: "plugin", Value: "cpu"},
+				{Key: "plugin_instance", Value: "0"},
+			},
+		},
+		{
+			Metric:     "if_octets.rx",
+			Timestamp:  1415062577000,
+			Value:      sfxpb.Datum{IntValue: int64Ptr(1)},
+			MetricType: sfxTypePtr(sfxpb.MetricType_CUMULATIVE_COUNTER),
+			Dimensions: []*sfxpb.Dimension{
+				{Key: "cluster", Value: "default"},
+				{Key: "env", Value: "prod"},
+				{Key: "host", Value: "host1"},
+				{Key: "k", Value: "v"},
+				{Key: "plugin", Value: "interface"},
+				{Key: "plugin_instance", Value: "eth0"},
+			},
+		},
+		{
+			Metric:     "if_octets.tx",
+			Timestamp:  1415062577000,
+			Value:      sfxpb.Datum{DoubleValue: float64Ptr(2.5)},
+			MetricType: sfxTypePtr(sfxpb.MetricType_GAUGE),
+			Dimensions: []*sfxpb.Dimension{
+				{Key: "cluster", Value: "default"},
+				{Key: "env", Value: "prod"},
+				{Key: "host", Value: "host1"},
+				{Key: "k", Value: "v"},
+				{Key: "plugin", Value: "interface"},
+				{Key: "plugin_instance", Value: "eth0"},
+			},
+		},
+	}
+	assert.Equal(t, want, got)
+
+	_, err = collectdJSONToDatapoints([]byte(`{"dsnames": "value"}`), nil)
+	assert.Error(t, err)
+}
+
+func Test_addDimensionsFromName(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       string
+		wantName string
+		wantDims map[string]string
+	}{
+		{
+			name:     "no_dimensions",
+			in:       "eth0",
+			wantName: "eth0",
+			wantDims: map[string]string{},
+		},
+		{
+			name:     "dimensions",
+			in:       "name[k=v,f=x]-more_name",
+			wantName: "name-more_name",
+			wantDims: map[string]string{"k": "v", "f": "x"},
+		},
+		{
+			name:     "unclosed",
+			in:       "name[k=v",
+			wantName: "name[k=v",
+			wantDims: map[string]string{},
+		},
+		{
+			name:     "malformed",
+			in:       "name[k]",
+			wantName: "name[k]",
+			wantDims: map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dims := map[string]string{}
+			assert.Equal(t, tt.wantName, addDimensionsFromName(dims, tt.in))
+			assert.Equal(t, tt.wantDims, dims)
+		})
+	}
+}
@@ -5,9 +5,12 @@ go 1.14
 require (
 	github.com/census-instrumentation/opencensus-proto v0.3.0
 	github.com/gorilla/mux v1.8.0
+	github.com/jaegertracing/jaeger v1.19.2
 	github.com/open-telemetry/opentelemetry-collector-contrib/exporter/signalfxexporter v0.0.0-00010101000000-000000000000
 	github.com/open-telemetry/opentelemetry-collector-contrib/internal/common v0.0.0-00010101000000-000000000000
+	github.com/openzipkin/zipkin-go v0.2.4-0.20200818204336-dc18516bbb4c
 	github.com/signalfx/com_signalfx_metrics_protobuf v0.0.2
+	github.com/signalfx/sapm-proto v0.5.3
 	github.com/stretchr/testify v1.6.1
 	go.opencensus.io v0.22.4
 	go.opentelemetry.io/collector v0.11.1-0.20200924160956-8690937037da
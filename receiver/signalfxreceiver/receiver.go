@@ -21,15 +21,19 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"mime"
 	"net"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 	"unsafe"
 
 	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
 	"github.com/gorilla/mux"
+	zipkinmodel "github.com/openzipkin/zipkin-go/model"
 	sfxpb "github.com/signalfx/com_signalfx_metrics_protobuf/model"
+	splunksapm "github.com/signalfx/sapm-proto/gen"
 	"go.opencensus.io/trace"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenterror"
@@ -38,6 +42,8 @@ import (
 	"go.opentelemetry.io/collector/obsreport"
 	"go.opentelemetry.io/collector/translator/conventions"
 	"go.opentelemetry.io/collector/translator/internaldata"
+	jaegertranslator "go.opentelemetry.io/collector/translator/trace/jaeger"
+	"go.opentelemetry.io/collector/translator/trace/zipkin"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/splunk"
@@ -48,7 +54,8 @@ const (
 
 	responseOK                 = "OK"
 	responseInvalidMethod      = "Only \"POST\" method is supported"
-	responseInvalidContentType = "\"Content-Type\" must be \"application/x-protobuf\""
+	responseInvalidContentType = "\"Content-Type\" must be either \"application/x-protobuf\" or \"application/json\""
+	responseInvalidJSONType    = "\"Content-Type\" must be \"application/json\""
 	responseInvalidEncoding    = "\"Content-Encoding\" must be \"gzip\" or empty"
 	responseErrGzipReader      = "Error on gzip body"
 	responseErrReadBody        = "Failed to read message body"
@@ -57,9 +64,14 @@ const (
 
 	// Centralizing some HTTP and related string constants.
 	protobufContentType       = "application/x-protobuf"
+	jsonContentType           = "application/json"
 	gzipEncoding              = "gzip"
 	httpContentTypeHeader     = "Content-Type"
 	httpContentEncodingHeader = "Content-Encoding"
+
+	// The trace endpoints, whose Zipkin JSON format depends on the API version.
+	traceV1Path = "/v1/trace"
+	traceV2Path = "/v2/trace"
 )
 
 var (
@@ -69,13 +81,45 @@ var (
 	okRespBody               = initJSONResponse(responseOK)
 	invalidMethodRespBody    = initJSONResponse(responseInvalidMethod)
 	invalidContentRespBody   = initJSONResponse(responseInvalidContentType)
+	invalidJSONTypeRespBody  = initJSONResponse(responseInvalidJSONType)
 	invalidEncodingRespBody  = initJSONResponse(responseInvalidEncoding)
 	errGzipReaderRespBody    = initJSONResponse(responseErrGzipReader)
 	errReadBodyRespBody      = initJSONResponse(responseErrReadBody)
 	errUnmarshalBodyRespBody = initJSONResponse(responseErrUnmarshalBody)
 	errNextConsumerRespBody  = initJSONResponse(responseErrNextConsumer)
+
+	// sfxContentTypes are accepted by the endpoints supporting both the
+	// SignalFx protobuf and JSON formats.
+	sfxContentTypes = contentTypes{
+		supported:       []string{protobufContentType, jsonContentType},
+		invalidRespBody: invalidContentRespBody,
+	}
+	// jsonContentTypes are accepted by the endpoints supporting only JSON.
+	jsonContentTypes = contentTypes{
+		supported:       []string{jsonContentType},
+		invalidRespBody: invalidJSONTypeRespBody,
+	}
 )
 
+// contentTypes lists the content types accepted by an endpoint, along with
+// the response sent for requests with any other content type.
+type contentTypes struct {
+	supported       []string
+	invalidRespBody []byte
+}
+
+func (c contentTypes) isSupported(contentType string) bool {
+	for _, supported := range c.supported {
+		if contentType == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// datapointsDecoder decodes the body of a request to SignalFx data points.
+type datapointsDecoder func(body []byte) ([]*sfxpb.DataPoint, error)
+
 // sfxReceiver implements the component.MetricsReceiver for SignalFx metric protocol.
 type sfxReceiver struct {
 	sync.Mutex
@@ -83,6 +127,7 @@ type sfxReceiver struct {
 	config          *Config
 	metricsConsumer consumer.MetricsConsumer
 	logsConsumer    consumer.LogsConsumer
+	traceConsumer   consumer.TraceConsumer
 	server          *http.Server
 
 	startOnce sync.Once
@@ -90,6 +135,8 @@ type sfxReceiver struct {
 }
 
 var _ component.MetricsReceiver = (*sfxReceiver)(nil)
+var _ component.LogsReceiver = (*sfxReceiver)(nil)
+var _ component.TraceReceiver = (*sfxReceiver)(nil)
 
 // New creates the SignalFx receiver with the given configuration.
 func newReceiver(
@@ -118,6 +165,13 @@ func (r *sfxReceiver) RegisterLogsConsumer(lc consumer.LogsConsumer) {
 	r.logsConsumer = lc
 }
 
+func (r *sfxReceiver) RegisterTraceConsumer(tc consumer.TraceConsumer) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.traceConsumer = tc
+}
+
 // StartMetricsReception tells the receiver to start its processing.
 // By convention the consumer of the received data is set when the receiver
 // instance is created.
@@ -125,7 +179,7 @@ func (r *sfxReceiver) Start(_ context.Context, host component.Host) error {
 	r.Lock()
 	defer r.Unlock()
 
-	if r.metricsConsumer == nil && r.logsConsumer == nil {
+	if r.metricsConsumer == nil && r.logsConsumer == nil && r.traceConsumer == nil {
 		return errNilNextConsumer
 	}
 
@@ -141,9 +195,20 @@ func (r *sfxReceiver) Start(_ context.Context, host component.Host) error {
 			return
 		}
 
+		// Only serve the endpoints of the signals with a registered consumer.
 		mx := mux.NewRouter()
-		mx.HandleFunc("/v2/datapoint", r.handleDatapointReq)
-		mx.HandleFunc("/v2/event", r.handleEventReq)
+		if r.metricsConsumer != nil {
+			mx.HandleFunc("/v2/datapoint", r.handleDatapointReq)
+			mx.HandleFunc("/v1/datapoint", r.handleDatapointV1Req)
+			mx.HandleFunc("/v1/collectd", r.handleCollectdReq)
+		}
+		if r.logsConsumer != nil {
+			mx.HandleFunc("/v2/event", r.handleEventReq)
+		}
+		if r.traceConsumer != nil {
+			mx.HandleFunc(traceV1Path, r.handleTraceReq)
+			mx.HandleFunc(traceV2Path, r.handleTraceReq)
+		}
 
 		r.server = r.config.HTTPServerSettings.ToServer(mx)
 
@@ -175,21 +240,32 @@ func (r *sfxReceiver) Shutdown(context.Context) error {
 	return err
 }
 
-func (r *sfxReceiver) readBody(ctx context.Context, resp http.ResponseWriter, req *http.Request) ([]byte, bool) {
+// readBody validates the request and returns its decompressed body along
+// with its content type, stripped of any parameters.
+func (r *sfxReceiver) readBody(
+	ctx context.Context,
+	resp http.ResponseWriter,
+	req *http.Request,
+	accepted contentTypes,
+) ([]byte, string, bool) {
 	if req.Method != http.MethodPost {
 		r.failRequest(ctx, resp, http.StatusBadRequest, invalidMethodRespBody, nil)
-		return nil, false
+		return nil, "", false
 	}
 
-	if req.Header.Get(httpContentTypeHeader) != protobufContentType {
-		r.failRequest(ctx, resp, http.StatusUnsupportedMediaType, invalidContentRespBody, nil)
-		return nil, false
+	contentType := req.Header.Get(httpContentTypeHeader)
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		contentType = mediaType
+	}
+	if !accepted.isSupported(contentType) {
+		r.failRequest(ctx, resp, http.StatusUnsupportedMediaType, accepted.invalidRespBody, nil)
+		return nil, "", false
 	}
 
 	encoding := req.Header.Get(httpContentEncodingHeader)
 	if encoding != "" && encoding != gzipEncoding {
 		r.failRequest(ctx, resp, http.StatusUnsupportedMediaType, invalidEncodingRespBody, nil)
-		return nil, false
+		return nil, "", false
 	}
 
 	bodyReader := req.Body
@@ -198,16 +274,16 @@ func (r *sfxReceiver) readBody(ctx context.Context, resp http.ResponseWriter, re
 		bodyReader, err = gzip.NewReader(bodyReader)
 		if err != nil {
 			r.failRequest(ctx, resp, http.StatusBadRequest, errGzipReaderRespBody, err)
-			return nil, false
+			return nil, "", false
 		}
 	}
 
 	body, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
 		r.failRequest(ctx, resp, http.StatusBadRequest, errReadBodyRespBody, err)
-		return nil, false
+		return nil, "", false
 	}
-	return body, true
+	return body, contentType, true
 }
 
 func (r *sfxReceiver) writeResponse(ctx context.Context, resp http.ResponseWriter, err error) {
@@ -220,33 +296,98 @@ func (r *sfxReceiver) writeResponse(ctx context.Context, resp http.ResponseWrite
 	resp.Write(okRespBody)
 }
 
-func (r *sfxReceiver) handleDatapointReq(resp http.ResponseWriter, req *http.Request) {
-	transport := "http"
+func (r *sfxReceiver) transport() string {
 	if r.config.TLSSetting != nil {
-		transport = "https"
+		return "https"
 	}
+	return "http"
+}
 
+func (r *sfxReceiver) handleDatapointReq(resp http.ResponseWriter, req *http.Request) {
+	r.handleDatapoints(resp, req, sfxContentTypes, map[string]datapointsDecoder{
+		protobufContentType: func(body []byte) ([]*sfxpb.DataPoint, error) {
+			msg := &sfxpb.DataPointUploadMessage{}
+			if err := msg.Unmarshal(body); err != nil {
+				return nil, err
+			}
+			return msg.Datapoints, nil
+		},
+		jsonContentType: signalFxV2JSONToDatapoints,
+	})
+}
+
+func (r *sfxReceiver) handleDatapointV1Req(resp http.ResponseWriter, req *http.Request) {
+	r.handleDatapoints(resp, req, sfxContentTypes, map[string]datapointsDecoder{
+		protobufContentType: signalFxV1ProtobufToDatapoints,
+		jsonContentType:     signalFxV1JSONToDatapoints,
+	})
+}
+
+func (r *sfxReceiver) handleCollectdReq(resp http.ResponseWriter, req *http.Request) {
+	defaultDims := collectdDefaultDimensions(req)
+	r.handleDatapoints(resp, req, jsonContentTypes, map[string]datapointsDecoder{
+		jsonContentType: func(body []byte) ([]*sfxpb.DataPoint, error) {
+			return collectdJSONToDatapoints(body, defaultDims)
+		},
+	})
+}
+
+// unixMilli returns t as milliseconds since the Unix epoch, the unit of
+// SignalFx timestamps. It is used for data sent without a timestamp, which
+// SignalFx stamps with the time it was received.
+func unixMilli(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+// collectdDefaultDimensions returns the dimensions set through "sfxdim_"
+// prefixed query parameters, as supported by the SignalFx collectd endpoint.
+func collectdDefaultDimensions(req *http.Request) map[string]string {
+	const prefix = "sfxdim_"
+
+	dims := map[string]string{}
+	for key, values := range req.URL.Query() {
+		if strings.HasPrefix(key, prefix) && len(key) > len(prefix) && len(values) > 0 && values[0] != "" {
+			dims[key[len(prefix):]] = values[0]
+		}
+	}
+	return dims
+}
+
+func (r *sfxReceiver) handleDatapoints(
+	resp http.ResponseWriter,
+	req *http.Request,
+	accepted contentTypes,
+	decoders map[string]datapointsDecoder,
+) {
+	receivedAt := time.Now()
+	transport := r.transport()
 	ctx := obsreport.ReceiverContext(req.Context(), r.config.Name(), transport, r.config.Name())
 	ctx = obsreport.StartMetricsReceiveOp(ctx, r.config.Name(), transport)
 
-	body, ok := r.readBody(ctx, resp, req)
+	body, contentType, ok := r.readBody(ctx, resp, req, accepted)
 	if !ok {
 		return
 	}
 
-	msg := &sfxpb.DataPointUploadMessage{}
-	if err := msg.Unmarshal(body); err != nil {
+	datapoints, err := decoders[contentType](body)
+	if err != nil {
 		r.failRequest(ctx, resp, http.StatusBadRequest, errUnmarshalBodyRespBody, err)
 		return
 	}
 
-	if len(msg.Datapoints) == 0 {
+	if len(datapoints) == 0 {
 		obsreport.EndMetricsReceiveOp(ctx, typeStr, 0, 0, nil)
 		resp.Write(okRespBody)
 		return
 	}
 
-	md, _ := signalFxV2ToMetricsData(r.logger, msg.Datapoints)
+	for _, datapoint := range datapoints {
+		if datapoint != nil && datapoint.Timestamp == 0 {
+			datapoint.Timestamp = unixMilli(receivedAt)
+		}
+	}
+
+	md, _ := signalFxV2ToMetricsData(r.logger, datapoints)
 
 	if r.config.AccessTokenPassthrough {
 		if accessToken := req.Header.Get(splunk.SFxAccessTokenHeader); accessToken != "" {
@@ -260,33 +401,36 @@ func (r *sfxReceiver) handleDatapointReq(resp http.ResponseWriter, req *http.Req
 		}
 	}
 
-	err := r.metricsConsumer.ConsumeMetrics(ctx, internaldata.OCToMetrics(md))
+	err = r.metricsConsumer.ConsumeMetrics(ctx, internaldata.OCToMetrics(md))
 	obsreport.EndMetricsReceiveOp(
 		ctx,
 		typeStr,
-		len(msg.Datapoints),
-		len(msg.Datapoints),
+		len(datapoints),
+		len(datapoints),
 		err)
 
 	r.writeResponse(ctx, resp, err)
 }
 
 func (r *sfxReceiver) handleEventReq(resp http.ResponseWriter, req *http.Request) {
-	transport := "http"
-	if r.config.TLSSetting != nil {
-		transport = "https"
-	}
-
+	receivedAt := time.Now()
+	transport := r.transport()
 	ctx := obsreport.ReceiverContext(req.Context(), r.config.Name(), transport, r.config.Name())
 	ctx = obsreport.StartMetricsReceiveOp(ctx, r.config.Name(), transport)
 
-	body, ok := r.readBody(ctx, resp, req)
+	body, contentType, ok := r.readBody(ctx, resp, req, sfxContentTypes)
 	if !ok {
 		return
 	}
 
 	msg := &sfxpb.EventUploadMessage{}
-	if err := msg.Unmarshal(body); err != nil {
+	var err error
+	if contentType == jsonContentType {
+		msg.Events, err = signalFxV2JSONToEvents(body)
+	} else {
+		err = msg.Unmarshal(body)
+	}
+	if err != nil {
 		r.failRequest(ctx, resp, http.StatusBadRequest, errUnmarshalBodyRespBody, err)
 		return
 	}
@@ -297,6 +441,12 @@ func (r *sfxReceiver) handleEventReq(resp http.ResponseWriter, req *http.Request
 		return
 	}
 
+	for _, event := range msg.Events {
+		if event != nil && event.Timestamp == 0 {
+			event.Timestamp = unixMilli(receivedAt)
+		}
+	}
+
 	logSlice := signalFxV2EventsToLogRecords(r.logger, msg.Events)
 
 	ld := pdata.NewLogs()
@@ -318,7 +468,7 @@ func (r *sfxReceiver) handleEventReq(resp http.ResponseWriter, req *http.Request
 		}
 	}
 
-	err := r.logsConsumer.ConsumeLogs(ctx, ld)
+	err = r.logsConsumer.ConsumeLogs(ctx, ld)
 	obsreport.EndMetricsReceiveOp(
 		ctx,
 		typeStr,
@@ -329,6 +479,80 @@ func (r *sfxReceiver) handleEventReq(resp http.ResponseWriter, req *http.Request
 	r.writeResponse(ctx, resp, err)
 }
 
+// handleTraceReq accepts spans in the Zipkin v1 JSON format on traceV1Path,
+// and in the Zipkin v2 JSON format or, when the content type is protobuf, in
+// the SAPM format on traceV2Path.
+func (r *sfxReceiver) handleTraceReq(resp http.ResponseWriter, req *http.Request) {
+	v2 := req.URL.Path == traceV2Path
+	accepted := jsonContentTypes
+	if v2 {
+		accepted = sfxContentTypes
+	}
+
+	transport := r.transport()
+	ctx := obsreport.ReceiverContext(req.Context(), r.config.Name(), transport, r.config.Name())
+	ctx = obsreport.StartTraceDataReceiveOp(ctx, r.config.Name(), transport)
+
+	body, contentType, ok := r.readBody(ctx, resp, req, accepted)
+	if !ok {
+		return
+	}
+
+	var td pdata.Traces
+	var format string
+	var err error
+	switch {
+	case contentType == protobufContentType:
+		format = "sapm"
+		sapm := &splunksapm.PostSpansRequest{}
+		if err = sapm.Unmarshal(body); err == nil {
+			td = jaegertranslator.ProtoBatchesToInternalTraces(sapm.Batches)
+		}
+	case v2:
+		format = "zipkin_v2_json"
+		var zipkinSpans []*zipkinmodel.SpanModel
+		if err = json.Unmarshal(body, &zipkinSpans); err == nil {
+			td, err = zipkin.V2SpansToInternalTraces(zipkinSpans)
+		}
+	default:
+		format = "zipkin_v1_json"
+		td, err = zipkin.V1JSONBatchToInternalTraces(body)
+	}
+	if err != nil {
+		r.failRequest(ctx, resp, http.StatusBadRequest, errUnmarshalBodyRespBody, err)
+		return
+	}
+
+	spanCount := td.SpanCount()
+	if spanCount == 0 {
+		obsreport.EndTraceDataReceiveOp(ctx, format, 0, nil)
+		resp.Write(okRespBody)
+		return
+	}
+
+	if r.config.AccessTokenPassthrough {
+		if accessToken := req.Header.Get(splunk.SFxAccessTokenHeader); accessToken != "" {
+			rSpans := td.ResourceSpans()
+			for i := 0; i < rSpans.Len(); i++ {
+				rSpan := rSpans.At(i)
+				if rSpan.IsNil() {
+					continue
+				}
+				resource := rSpan.Resource()
+				if resource.IsNil() {
+					resource.InitEmpty()
+				}
+				resource.Attributes().UpsertString(splunk.SFxAccessTokenLabel, accessToken)
+			}
+		}
+	}
+
+	err = r.traceConsumer.ConsumeTraces(ctx, td)
+	obsreport.EndTraceDataReceiveOp(ctx, format, spanCount, err)
+
+	r.writeResponse(ctx, resp, err)
+}
+
 func (r *sfxReceiver) failRequest(
 	ctx context.Context,
 	resp http.ResponseWriter,
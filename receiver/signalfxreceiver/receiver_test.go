@@ -32,7 +32,9 @@ import (
 	"time"
 
 	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	jaegerpb "github.com/jaegertracing/jaeger/model"
 	sfxpb "github.com/signalfx/com_signalfx_metrics_protobuf/model"
+	splunksapm "github.com/signalfx/sapm-proto/gen"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
@@ -45,6 +47,7 @@ import (
 	"go.opentelemetry.io/collector/exporter/exportertest"
 	"go.opentelemetry.io/collector/testutil"
 	"go.opentelemetry.io/collector/testutil/metricstestutil"
+	"go.opentelemetry.io/collector/translator/conventions"
 	"go.opentelemetry.io/collector/translator/internaldata"
 	"go.uber.org/zap"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -269,6 +272,31 @@ func Test_sfxReceiver_handleReq(t *testing.T) {
 				assert.Equal(t, responseOK, body)
 			},
 		},
+		{
+			name: "json_msg_accepted",
+			req: func() *http.Request {
+				body := `{"gauge": [{"metric": "single", "value": 13, "dimensions": {"k0": "v0"}}]}`
+				req := httptest.NewRequest("POST", "http://localhost", bytes.NewReader([]byte(body)))
+				req.Header.Set("Content-Type", "application/json; charset=utf-8")
+				return req
+			}(),
+			assertResponse: func(t *testing.T, status int, body string) {
+				assert.Equal(t, http.StatusAccepted, status)
+				assert.Equal(t, responseOK, body)
+			},
+		},
+		{
+			name: "bad_json_in_body",
+			req: func() *http.Request {
+				req := httptest.NewRequest("POST", "http://localhost", bytes.NewReader([]byte("[")))
+				req.Header.Set("Content-Type", "application/json")
+				return req
+			}(),
+			assertResponse: func(t *testing.T, status int, body string) {
+				assert.Equal(t, http.StatusBadRequest, status)
+				assert.Equal(t, responseErrUnmarshalBody, body)
+			},
+		},
 		{
 			name: "msg_accepted_gzipped",
 			req: func() *http.Request {
@@ -426,6 +454,19 @@ func Test_sfxReceiver_handleEventReq(t *testing.T) {
 				assert.Equal(t, responseOK, body)
 			},
 		},
+		{
+			name: "json_msg_accepted",
+			req: func() *http.Request {
+				body := `[{"category": "USER_DEFINED", "eventType": "single", "dimensions": {"k0": "v0"}}]`
+				req := httptest.NewRequest("POST", "http://localhost", bytes.NewReader([]byte(body)))
+				req.Header.Set("Content-Type", "application/json")
+				return req
+			}(),
+			assertResponse: func(t *testing.T, status int, body string) {
+				assert.Equal(t, http.StatusAccepted, status)
+				assert.Equal(t, responseOK, body)
+			},
+		},
 		{
 			name: "msg_accepted_gzipped",
 			req: func() *http.Request {
@@ -487,6 +528,432 @@ func Test_sfxReceiver_handleEventReq(t *testing.T) {
 	}
 }
 
+func Test_sfxReceiver_handleDatapointV1Req(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = "localhost:0" // Actually not creating the endpoint
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        []byte
+		wantStatus  int
+		wantBody    string
+		wantMetrics []string
+	}{
+		{
+			name:        "json",
+			contentType: "application/json",
+			body:        []byte(`{"source": "src", "metric": "m1", "value": 1}{"metric": "m2", "value": 2.5}`),
+			wantStatus:  http.StatusAccepted,
+			wantBody:    responseOK,
+			wantMetrics: []string{"m1", "m2"},
+		},
+		{
+			name:        "protobuf",
+			contentType: "application/x-protobuf",
+			body: buildSFxV1ProtobufBody(t, &sfxpb.DataPoint{
+				Metric: "m1",
+				Value:  sfxpb.Datum{IntValue: int64Ptr(1)},
+			}),
+			wantStatus:  http.StatusAccepted,
+			wantBody:    responseOK,
+			wantMetrics: []string{"m1"},
+		},
+		{
+			name:        "bad_protobuf",
+			contentType: "application/x-protobuf",
+			body:        []byte{10, 1},
+			wantStatus:  http.StatusBadRequest,
+			wantBody:    responseErrUnmarshalBody,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := new(exportertest.SinkMetricsExporter)
+			rcv := newReceiver(zap.NewNop(), *config)
+			rcv.RegisterMetricsConsumer(sink)
+
+			req := httptest.NewRequest("POST", "http://localhost/v1/datapoint", bytes.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			w := httptest.NewRecorder()
+			rcv.handleDatapointV1Req(w, req)
+
+			status, body := readResponse(t, w)
+			assert.Equal(t, tt.wantStatus, status)
+			assert.Equal(t, tt.wantBody, body)
+			assert.ElementsMatch(t, tt.wantMetrics, receivedMetricNames(sink))
+		})
+	}
+}
+
+func Test_sfxReceiver_handleCollectdReq(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = "localhost:0" // Actually not creating the endpoint
+
+	sink := new(exportertest.SinkMetricsExporter)
+	rcv := newReceiver(zap.NewNop(), *config)
+	rcv.RegisterMetricsConsumer(sink)
+
+	body := `[{"dsnames": ["value"], "dstypes": ["gauge"], "host": "host1", "plugin": "memory",
+		"time": 1415062577.4949999, "type": "memory", "type_instance": "free", "values": [1024]}]`
+	req := httptest.NewRequest("POST", "http://localhost/v1/collectd?sfxdim_env=prod&other=ignored", bytes.NewReader([]byte(body)))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	rcv.handleCollectdReq(w, req)
+
+	status, respBody := readResponse(t, w)
+	assert.Equal(t, http.StatusAccepted, status)
+	assert.Equal(t, responseOK, respBody)
+
+	mds := sink.AllMetrics()
+	require.Len(t, mds, 1)
+	got := internaldata.MetricsToOC(mds[0])
+	require.Len(t, got, 1)
+	require.Len(t, got[0].Metrics, 1)
+	metric := got[0].Metrics[0]
+	assert.Equal(t, "memory.free", metric.MetricDescriptor.Name)
+	labels := map[string]string{}
+	for i, key := range metric.MetricDescriptor.LabelKeys {
+		labels[key.Key] = metric.Timeseries[0].LabelValues[i].Value
+	}
+	assert.Equal(t, map[string]string{
+		"dsname": "value",
+		"env":    "prod",
+		"host":   "host1",
+		"plugin": "memory",
+	}, labels)
+
+	// Only JSON is supported by the collectd endpoint.
+	req = httptest.NewRequest("POST", "http://localhost/v1/collectd", bytes.NewReader([]byte(body)))
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	w = httptest.NewRecorder()
+	rcv.handleCollectdReq(w, req)
+
+	status, respBody = readResponse(t, w)
+	assert.Equal(t, http.StatusUnsupportedMediaType, status)
+	assert.Equal(t, responseInvalidJSONType, respBody)
+}
+
+func Test_sfxReceiver_defaultsMissingDatapointTimestamps(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = "localhost:0" // Actually not creating the endpoint
+
+	v2Protobuf, err := (&sfxpb.DataPointUploadMessage{
+		Datapoints: []*sfxpb.DataPoint{{
+			Metric:     "m1",
+			Value:      sfxpb.Datum{IntValue: int64Ptr(1)},
+			MetricType: sfxTypePtr(sfxpb.MetricType_GAUGE),
+		}},
+	}).Marshal()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		path        string
+		contentType string
+		body        []byte
+	}{
+		{
+			name:        "v2_json",
+			path:        "/v2/datapoint",
+			contentType: "application/json",
+			body:        []byte(`{"gauge": [{"metric": "m1", "value": 1}]}`),
+		},
+		{
+			name:        "v2_protobuf",
+			path:        "/v2/datapoint",
+			contentType: "application/x-protobuf",
+			body:        v2Protobuf,
+		},
+		{
+			name:        "v1_json",
+			path:        "/v1/datapoint",
+			contentType: "application/json",
+			body:        []byte(`{"metric": "m1", "value": 1}`),
+		},
+		{
+			name:        "v1_protobuf",
+			path:        "/v1/datapoint",
+			contentType: "application/x-protobuf",
+			body: buildSFxV1ProtobufBody(t, &sfxpb.DataPoint{
+				Metric: "m1",
+				Value:  sfxpb.Datum{IntValue: int64Ptr(1)},
+			}),
+		},
+		{
+			name:        "collectd",
+			path:        "/v1/collectd",
+			contentType: "application/json",
+			body: []byte(`[{"dsnames": ["value"], "dstypes": ["gauge"], "host": "host1",
+				"plugin": "memory", "type": "memory", "type_instance": "free", "values": [1024]}]`),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := new(exportertest.SinkMetricsExporter)
+			rcv := newReceiver(zap.NewNop(), *config)
+			rcv.RegisterMetricsConsumer(sink)
+			handlers := map[string]http.HandlerFunc{
+				"/v2/datapoint": rcv.handleDatapointReq,
+				"/v1/datapoint": rcv.handleDatapointV1Req,
+				"/v1/collectd":  rcv.handleCollectdReq,
+			}
+
+			req := httptest.NewRequest("POST", "http://localhost"+tt.path, bytes.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			w := httptest.NewRecorder()
+			before := time.Now().Truncate(time.Millisecond)
+			handlers[tt.path](w, req)
+			after := time.Now()
+
+			status, body := readResponse(t, w)
+			require.Equal(t, http.StatusAccepted, status)
+			require.Equal(t, responseOK, body)
+
+			mds := sink.AllMetrics()
+			require.Len(t, mds, 1)
+			got := internaldata.MetricsToOC(mds[0])
+			require.Len(t, got, 1)
+			require.Len(t, got[0].Metrics, 1)
+			ts := got[0].Metrics[0].Timeseries[0].Points[0].Timestamp.AsTime()
+			assert.False(t, ts.Before(before), "timestamp %v is before %v", ts, before)
+			assert.False(t, ts.After(after), "timestamp %v is after %v", ts, after)
+		})
+	}
+}
+
+func Test_sfxReceiver_defaultsMissingEventTimestamps(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = "localhost:0" // Actually not creating the endpoint
+
+	protobufBody, err := buildSFxEventMsg(0, 13, 3).Marshal()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        []byte
+	}{
+		{
+			name:        "json",
+			contentType: "application/json",
+			body:        []byte(`[{"eventType": "single", "category": "USER_DEFINED"}]`),
+		},
+		{
+			name:        "protobuf",
+			contentType: "application/x-protobuf",
+			body:        protobufBody,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := new(exportertest.SinkLogsExporter)
+			rcv := newReceiver(zap.NewNop(), *config)
+			rcv.RegisterLogsConsumer(sink)
+
+			req := httptest.NewRequest("POST", "http://localhost/v2/event", bytes.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			w := httptest.NewRecorder()
+			before := time.Now().Truncate(time.Millisecond)
+			rcv.handleEventReq(w, req)
+			after := time.Now()
+
+			status, body := readResponse(t, w)
+			require.Equal(t, http.StatusAccepted, status)
+			require.Equal(t, responseOK, body)
+
+			got := sink.AllLogs()
+			require.Len(t, got, 1)
+			lr := got[0].ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0)
+			ts := time.Unix(0, int64(lr.Timestamp()))
+			assert.False(t, ts.Before(before), "timestamp %v is before %v", ts, before)
+			assert.False(t, ts.After(after), "timestamp %v is after %v", ts, after)
+		})
+	}
+}
+
+func Test_sfxReceiver_handleTraceReq(t *testing.T) {
+	sapmBody := func() []byte {
+		sapm := &splunksapm.PostSpansRequest{
+			Batches: []*jaegerpb.Batch{{
+				Process: &jaegerpb.Process{ServiceName: "svc"},
+				Spans: []*jaegerpb.Span{{
+					TraceID:       jaegerpb.NewTraceID(1, 2),
+					SpanID:        jaegerpb.NewSpanID(3),
+					OperationName: "op",
+				}},
+			}},
+		}
+		b, err := sapm.Marshal()
+		require.NoError(t, err)
+		return b
+	}
+
+	tests := []struct {
+		name        string
+		path        string
+		contentType string
+		body        []byte
+		token       string
+		passthrough bool
+		wantStatus  int
+		wantBody    string
+		wantSpans   int
+	}{
+		{
+			name:        "zipkin_v1_json",
+			path:        "/v1/trace",
+			contentType: "application/json",
+			body: []byte(`[{"traceId": "0000000000000001", "id": "0000000000000002", "name": "op",
+				"annotations": [{"timestamp": 1, "value": "sr", "endpoint": {"serviceName": "svc"}}]}]`),
+			wantStatus: http.StatusAccepted,
+			wantBody:   responseOK,
+			wantSpans:  1,
+		},
+		{
+			name:        "zipkin_v1_protobuf",
+			path:        "/v1/trace",
+			contentType: "application/x-protobuf",
+			wantStatus:  http.StatusUnsupportedMediaType,
+			wantBody:    responseInvalidJSONType,
+		},
+		{
+			name:        "zipkin_v2_json",
+			path:        "/v2/trace",
+			contentType: "application/json",
+			body: []byte(`[{"traceId": "0000000000000001", "id": "0000000000000002", "name": "op",
+				"localEndpoint": {"serviceName": "svc"}}]`),
+			token:       "myToken",
+			passthrough: true,
+			wantStatus:  http.StatusAccepted,
+			wantBody:    responseOK,
+			wantSpans:   1,
+		},
+		{
+			name:        "sapm",
+			path:        "/v2/trace",
+			contentType: "application/x-protobuf",
+			body:        sapmBody(),
+			token:       "myToken",
+			passthrough: true,
+			wantStatus:  http.StatusAccepted,
+			wantBody:    responseOK,
+			wantSpans:   1,
+		},
+		{
+			name:        "empty",
+			path:        "/v2/trace",
+			contentType: "application/json",
+			body:        []byte(`[]`),
+			wantStatus:  http.StatusOK,
+			wantBody:    responseOK,
+		},
+		{
+			name:        "bad_json",
+			path:        "/v2/trace",
+			contentType: "application/json",
+			body:        []byte(`{`),
+			wantStatus:  http.StatusBadRequest,
+			wantBody:    responseErrUnmarshalBody,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := createDefaultConfig().(*Config)
+			config.Endpoint = "localhost:0" // Actually not creating the endpoint
+			config.AccessTokenPassthrough = tt.passthrough
+
+			sink := new(exportertest.SinkTraceExporter)
+			rcv := newReceiver(zap.NewNop(), *config)
+			rcv.RegisterTraceConsumer(sink)
+
+			req := httptest.NewRequest("POST", "http://localhost"+tt.path, bytes.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			if tt.token != "" {
+				req.Header.Set("x-sf-token", tt.token)
+			}
+			w := httptest.NewRecorder()
+			rcv.handleTraceReq(w, req)
+
+			status, body := readResponse(t, w)
+			assert.Equal(t, tt.wantStatus, status)
+			assert.Equal(t, tt.wantBody, body)
+
+			tds := sink.AllTraces()
+			if tt.wantSpans == 0 {
+				assert.Empty(t, tds)
+				return
+			}
+			require.Len(t, tds, 1)
+			assert.Equal(t, tt.wantSpans, tds[0].SpanCount())
+
+			// The service name is only found if the format matching the path is used.
+			serviceName, ok := tds[0].ResourceSpans().At(0).Resource().Attributes().Get(conventions.AttributeServiceName)
+			require.True(t, ok)
+			assert.Equal(t, "svc", serviceName.StringVal())
+
+			if tt.token != "" {
+				rss := tds[0].ResourceSpans()
+				for i := 0; i < rss.Len(); i++ {
+					token, ok := rss.At(i).Resource().Attributes().Get("com.splunk.signalfx.access_token")
+					require.True(t, ok)
+					assert.Equal(t, tt.token, token.StringVal())
+				}
+			}
+		})
+	}
+}
+
+func Test_sfxReceiver_routesPerSignal(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = addr
+	r := newReceiver(zap.NewNop(), *cfg)
+	r.RegisterTraceConsumer(new(exportertest.SinkTraceExporter))
+
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer r.Shutdown(context.Background())
+
+	post := func(path string) int {
+		resp, err := http.Post("http://"+addr+path, "application/json", bytes.NewReader([]byte(`[]`)))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// Only the endpoints of signals with a consumer are served.
+	assert.Equal(t, http.StatusOK, post("/v2/trace"))
+	assert.Equal(t, http.StatusOK, post("/v1/trace"))
+	assert.Equal(t, http.StatusNotFound, post("/v2/datapoint"))
+	assert.Equal(t, http.StatusNotFound, post("/v2/event"))
+}
+
+func readResponse(t *testing.T, w *httptest.ResponseRecorder) (int, string) {
+	resp := w.Result()
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	var bodyStr string
+	require.NoError(t, json.Unmarshal(respBytes, &bodyStr))
+	return resp.StatusCode, bodyStr
+}
+
+func receivedMetricNames(sink *exportertest.SinkMetricsExporter) []string {
+	var names []string
+	for _, md := range sink.AllMetrics() {
+		for _, ocmd := range internaldata.MetricsToOC(md) {
+			for _, metric := range ocmd.Metrics {
+				names = append(names, metric.MetricDescriptor.Name)
+			}
+		}
+	}
+	return names
+}
+
 func Test_sfxReceiver_TLS(t *testing.T) {
 	addr := testutil.GetAvailableLocalAddress(t)
 	cfg := createDefaultConfig().(*Config)
@@ -0,0 +1,252 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signalfxreceiver
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	sfxpb "github.com/signalfx/com_signalfx_metrics_protobuf/model"
+)
+
+// sfSourceDimension is the dimension used by SignalFx to record the source
+// of data points sent through the v1 API.
+const sfSourceDimension = "sf_source"
+
+var errSFxV1ProtobufSize = errors.New("invalid length of v1 protobuf data-point")
+
+// jsonMetricTypes maps the keys of a SignalFx v2 JSON data-point message to
+// their metric types.
+var jsonMetricTypes = map[string]sfxpb.MetricType{
+	"gauge":              sfxpb.MetricType_GAUGE,
+	"counter":            sfxpb.MetricType_COUNTER,
+	"cumulative_counter": sfxpb.MetricType_CUMULATIVE_COUNTER,
+}
+
+type jsonDatapointV2 struct {
+	Metric     string                 `json:"metric"`
+	Timestamp  int64                  `json:"timestamp"`
+	Value      interface{}            `json:"value"`
+	Dimensions map[string]interface{} `json:"dimensions"`
+}
+
+type jsonEventV2 struct {
+	Category   *string                `json:"category"`
+	EventType  string                 `json:"eventType"`
+	Dimensions map[string]interface{} `json:"dimensions"`
+	Properties map[string]interface{} `json:"properties"`
+	Timestamp  int64                  `json:"timestamp"`
+}
+
+type jsonDatapointV1 struct {
+	Source    string      `json:"source"`
+	Metric    string      `json:"metric"`
+	Timestamp int64       `json:"timestamp"`
+	Value     interface{} `json:"value"`
+}
+
+// signalFxV2JSONToDatapoints converts a SignalFx v2 JSON data-point message,
+// a map from metric type to the list of data points of that type, to
+// SignalFx proto data points. Data points of unknown metric types are ignored.
+func signalFxV2JSONToDatapoints(body []byte) ([]*sfxpb.DataPoint, error) {
+	msg := map[string][]*jsonDatapointV2{}
+	if err := unmarshalJSON(body, &msg); err != nil {
+		return nil, err
+	}
+
+	var sfxDataPoints []*sfxpb.DataPoint
+	for key, jsonDataPoints := range msg {
+		metricType, ok := jsonMetricTypes[key]
+		if !ok {
+			continue
+		}
+		for _, jsonDataPoint := range jsonDataPoints {
+			if jsonDataPoint == nil {
+				continue
+			}
+			mt := metricType
+			sfxDataPoints = append(sfxDataPoints, &sfxpb.DataPoint{
+				Metric:     jsonDataPoint.Metric,
+				Timestamp:  jsonDataPoint.Timestamp,
+				Value:      jsonValueToDatum(jsonDataPoint.Value),
+				MetricType: &mt,
+				Dimensions: jsonToDimensions(jsonDataPoint.Dimensions),
+			})
+		}
+	}
+	return sfxDataPoints, nil
+}
+
+// signalFxV2JSONToEvents converts a SignalFx v2 JSON event message to
+// SignalFx proto events.
+func signalFxV2JSONToEvents(body []byte) ([]*sfxpb.Event, error) {
+	var jsonEvents []*jsonEventV2
+	if err := unmarshalJSON(body, &jsonEvents); err != nil {
+		return nil, err
+	}
+
+	sfxEvents := make([]*sfxpb.Event, 0, len(jsonEvents))
+	for _, jsonEvent := range jsonEvents {
+		if jsonEvent == nil {
+			continue
+		}
+		sfxEvent := &sfxpb.Event{
+			EventType:  jsonEvent.EventType,
+			Timestamp:  jsonEvent.Timestamp,
+			Dimensions: jsonToDimensions(jsonEvent.Dimensions),
+			Properties: jsonToProperties(jsonEvent.Properties),
+		}
+		if jsonEvent.Category != nil {
+			if category, ok := sfxpb.EventCategory_value[*jsonEvent.Category]; ok {
+				c := sfxpb.EventCategory(category)
+				sfxEvent.Category = &c
+			}
+		}
+		sfxEvents = append(sfxEvents, sfxEvent)
+	}
+	return sfxEvents, nil
+}
+
+// signalFxV1JSONToDatapoints converts a stream of SignalFx v1 JSON data-points
+// to SignalFx proto gauges. The source of each data point is kept as the
+// "sf_source" dimension.
+func signalFxV1JSONToDatapoints(body []byte) ([]*sfxpb.DataPoint, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var sfxDataPoints []*sfxpb.DataPoint
+	for {
+		var jsonDataPoint jsonDatapointV1
+		if err := decoder.Decode(&jsonDataPoint); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		mt := sfxpb.MetricType_GAUGE
+		sfxDataPoint := &sfxpb.DataPoint{
+			Metric:     jsonDataPoint.Metric,
+			Timestamp:  jsonDataPoint.Timestamp,
+			Value:      jsonValueToDatum(jsonDataPoint.Value),
+			MetricType: &mt,
+		}
+		addSourceDimension(sfxDataPoint, jsonDataPoint.Source)
+		sfxDataPoints = append(sfxDataPoints, sfxDataPoint)
+	}
+	return sfxDataPoints, nil
+}
+
+// signalFxV1ProtobufToDatapoints converts a stream of varint length-delimited
+// SignalFx proto data-points, as sent to the v1 API, to SignalFx proto data points.
+func signalFxV1ProtobufToDatapoints(body []byte) ([]*sfxpb.DataPoint, error) {
+	reader := bytes.NewReader(body)
+
+	var sfxDataPoints []*sfxpb.DataPoint
+	for reader.Len() > 0 {
+		size, err := binary.ReadUvarint(reader)
+		if err != nil {
+			return nil, err
+		}
+		if size > uint64(reader.Len()) {
+			return nil, errSFxV1ProtobufSize
+		}
+
+		buf := make([]byte, size)
+		if _, err := io.ReadFull(reader, buf); err != nil {
+			return nil, err
+		}
+
+		sfxDataPoint := &sfxpb.DataPoint{}
+		if err := sfxDataPoint.Unmarshal(buf); err != nil {
+			return nil, err
+		}
+		addSourceDimension(sfxDataPoint, sfxDataPoint.Source)
+		sfxDataPoints = append(sfxDataPoints, sfxDataPoint)
+	}
+	return sfxDataPoints, nil
+}
+
+func unmarshalJSON(body []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+func addSourceDimension(sfxDataPoint *sfxpb.DataPoint, source string) {
+	if source == "" {
+		return
+	}
+	sfxDataPoint.Dimensions = append(sfxDataPoint.Dimensions, &sfxpb.Dimension{
+		Key:   sfSourceDimension,
+		Value: source,
+	})
+}
+
+// jsonValueToDatum converts a decoded JSON value to a SignalFx datum. Values
+// that are neither numbers nor strings result in an empty datum, which is
+// dropped by the conversion to metrics.
+func jsonValueToDatum(value interface{}) sfxpb.Datum {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return sfxpb.Datum{IntValue: &i}
+		}
+		if f, err := v.Float64(); err == nil {
+			return sfxpb.Datum{DoubleValue: &f}
+		}
+	case string:
+		return sfxpb.Datum{StrValue: &v}
+	}
+	return sfxpb.Datum{}
+}
+
+func jsonToDimensions(dims map[string]interface{}) []*sfxpb.Dimension {
+	dimensions := make([]*sfxpb.Dimension, 0, len(dims))
+	for k, v := range dims {
+		if v == nil {
+			continue
+		}
+		dimensions = append(dimensions, &sfxpb.Dimension{
+			Key:   k,
+			Value: fmt.Sprint(v),
+		})
+	}
+	return dimensions
+}
+
+func jsonToProperties(props map[string]interface{}) []*sfxpb.Property {
+	properties := make([]*sfxpb.Property, 0, len(props))
+	for k, v := range props {
+		pv := &sfxpb.PropertyValue{}
+		switch t := v.(type) {
+		case json.Number:
+			if i, err := t.Int64(); err == nil {
+				pv.IntValue = &i
+			} else if f, err := t.Float64(); err == nil {
+				pv.DoubleValue = &f
+			}
+		case string:
+			pv.StrValue = &t
+		case bool:
+			pv.BoolValue = &t
+		}
+		properties = append(properties, &sfxpb.Property{Key: k, Value: pv})
+	}
+	return properties
+}
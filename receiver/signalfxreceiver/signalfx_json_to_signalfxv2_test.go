@@ -0,0 +1,183 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signalfxreceiver
+
+import (
+	"encoding/binary"
+	"sort"
+	"testing"
+
+	sfxpb "github.com/signalfx/com_signalfx_metrics_protobuf/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_signalFxV2JSONToDatapoints(t *testing.T) {
+	body := []byte(`{
+		"gauge": [{"metric": "g", "timestamp": 1000, "value": 1.5, "dimensions": {"host": "h1", "empty": null}}],
+		"counter": [{"metric": "c", "timestamp": 2000, "value": 3}],
+		"cumulative_counter": [{"metric": "cc", "timestamp": 3000, "value": 7}],
+		"unknown": [{"metric": "u", "timestamp": 4000, "value": 1}]
+	}`)
+
+	got, err := signalFxV2JSONToDatapoints(body)
+	require.NoError(t, err)
+	sortDatapoints(got)
+
+	want := []*sfxpb.DataPoint{
+		{
+			Metric:     "c",
+			Timestamp:  2000,
+			Value:      sfxpb.Datum{IntValue: int64Ptr(3)},
+			MetricType: sfxTypePtr(sfxpb.MetricType_COUNTER),
+			Dimensions: []*sfxpb.Dimension{},
+		},
+		{
+			Metric:     "cc",
+			Timestamp:  3000,
+			Value:      sfxpb.Datum{IntValue: int64Ptr(7)},
+			MetricType: sfxTypePtr(sfxpb.MetricType_CUMULATIVE_COUNTER),
+			Dimensions: []*sfxpb.Dimension{},
+		},
+		{
+			Metric:     "g",
+			Timestamp:  1000,
+			Value:      sfxpb.Datum{DoubleValue: float64Ptr(1.5)},
+			MetricType: sfxTypePtr(sfxpb.MetricType_GAUGE),
+			Dimensions: []*sfxpb.Dimension{{Key: "host", Value: "h1"}},
+		},
+	}
+	assert.Equal(t, want, got)
+
+	_, err = signalFxV2JSONToDatapoints([]byte(`[1, 2]`))
+	assert.Error(t, err)
+}
+
+func Test_signalFxV2JSONToEvents(t *testing.T) {
+	body := []byte(`[{
+		"category": "USER_DEFINED",
+		"eventType": "deployment",
+		"dimensions": {"service": "api"},
+		"properties": {"version": "1.2", "count": 2, "ratio": 0.5, "canary": true},
+		"timestamp": 1000
+	}, {
+		"category": "not-a-category",
+		"eventType": "other",
+		"timestamp": 2000
+	}]`)
+
+	got, err := signalFxV2JSONToEvents(body)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+
+	category := sfxpb.EventCategory_USER_DEFINED
+	assert.Equal(t, "deployment", got[0].EventType)
+	assert.Equal(t, int64(1000), got[0].Timestamp)
+	assert.Equal(t, &category, got[0].Category)
+	assert.Equal(t, []*sfxpb.Dimension{{Key: "service", Value: "api"}}, got[0].Dimensions)
+
+	props := map[string]*sfxpb.PropertyValue{}
+	for _, prop := range got[0].Properties {
+		props[prop.Key] = prop.Value
+	}
+	assert.Equal(t, "1.2", props["version"].GetStrValue())
+	assert.Equal(t, int64(2), props["count"].GetIntValue())
+	assert.Equal(t, 0.5, props["ratio"].GetDoubleValue())
+	assert.True(t, props["canary"].GetBoolValue())
+
+	assert.Equal(t, "other", got[1].EventType)
+	assert.Nil(t, got[1].Category)
+
+	_, err = signalFxV2JSONToEvents([]byte(`{}`))
+	assert.Error(t, err)
+}
+
+func Test_signalFxV1JSONToDatapoints(t *testing.T) {
+	body := []byte(`{"source": "src", "metric": "m1", "value": 1}
+		{"metric": "m2", "timestamp": 1000, "value": 2.5}`)
+
+	got, err := signalFxV1JSONToDatapoints(body)
+	require.NoError(t, err)
+
+	want := []*sfxpb.DataPoint{
+		{
+			Metric:     "m1",
+			Value:      sfxpb.Datum{IntValue: int64Ptr(1)},
+			MetricType: sfxTypePtr(sfxpb.MetricType_GAUGE),
+			Dimensions: []*sfxpb.Dimension{{Key: sfSourceDimension, Value: "src"}},
+		},
+		{
+			Metric:     "m2",
+			Timestamp:  1000,
+			Value:      sfxpb.Datum{DoubleValue: float64Ptr(2.5)},
+			MetricType: sfxTypePtr(sfxpb.MetricType_GAUGE),
+		},
+	}
+	assert.Equal(t, want, got)
+
+	_, err = signalFxV1JSONToDatapoints([]byte(`{"metric": `))
+	assert.Error(t, err)
+}
+
+func Test_signalFxV1ProtobufToDatapoints(t *testing.T) {
+	dps := []*sfxpb.DataPoint{
+		{
+			Source:     "src",
+			Metric:     "m1",
+			Value:      sfxpb.Datum{IntValue: int64Ptr(1)},
+			MetricType: sfxTypePtr(sfxpb.MetricType_COUNTER),
+		},
+		{
+			Metric: "m2",
+			Value:  sfxpb.Datum{DoubleValue: float64Ptr(2.5)},
+		},
+	}
+	body := buildSFxV1ProtobufBody(t, dps...)
+
+	got, err := signalFxV1ProtobufToDatapoints(body)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "m1", got[0].Metric)
+	assert.Equal(t, []*sfxpb.Dimension{{Key: sfSourceDimension, Value: "src"}}, got[0].Dimensions)
+	assert.Equal(t, "m2", got[1].Metric)
+	assert.Empty(t, got[1].Dimensions)
+
+	// The declared size exceeds the remaining data.
+	_, err = signalFxV1ProtobufToDatapoints(append(body, 10, 1))
+	assert.Equal(t, errSFxV1ProtobufSize, err)
+}
+
+func buildSFxV1ProtobufBody(t *testing.T, dps ...*sfxpb.DataPoint) []byte {
+	var body []byte
+	for _, dp := range dps {
+		b, err := dp.Marshal()
+		require.NoError(t, err)
+		size := make([]byte, binary.MaxVarintLen64)
+		body = append(body, size[:binary.PutUvarint(size, uint64(len(b)))]...)
+		body = append(body, b...)
+	}
+	return body
+}
+
+func sortDatapoints(dps []*sfxpb.DataPoint) {
+	sort.Slice(dps, func(i, j int) bool {
+		return dps[i].Metric < dps[j].Metric
+	})
+	for _, dp := range dps {
+		sort.Slice(dp.Dimensions, func(i, j int) bool {
+			return dp.Dimensions[i].Key < dp.Dimensions[j].Key
+		})
+	}
+}